@@ -20,15 +20,21 @@ var (
 func main() {
 	printBuildInfo()
 
-	cfg, err := app.GetConfig(config.AgentConfig{
+	defaultConfig := config.AgentConfig{
 		SendMetricsURL:        "localhost:8080",
 		SendMetricsEndPoint:   "/updates",
 		UpdateMetricsInterval: 2,
 		SendMetricsInterval:   5,
 		MetricsBufferSize:     12,
+		BackpressurePolicy:    "block",
 		RateLimit:             1,
 		LoggerLvl:             "info",
-	})
+		SpoolMaxSize:          64 * 1024 * 1024,
+		SpoolMaxAge:           24 * 60 * 60,
+		SpoolSegmentSize:      1024 * 1024,
+	}
+
+	cfg, err := app.GetConfig(defaultConfig)
 	if err != nil {
 		log.Fatalf("%+v", err)
 	}
@@ -38,7 +44,7 @@ func main() {
 		log.Fatalf("%+v", err)
 	}
 
-	err = app.Run(&cfg, logger)
+	err = app.Run(&cfg, defaultConfig, buildVersion, logger)
 	if err != nil {
 		logger.Fatal(err.Error(), zap.String("event", "start agent"))
 	}
@@ -2,11 +2,18 @@ package agent
 
 import (
 	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/go-chi/chi"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -14,6 +21,7 @@ import (
 	"google.golang.org/grpc/encoding/gzip"
 	"gopkg.in/h2non/gentleman.v2"
 
+	collector "github.com/Stern-Ritter/metrics-and-alerting-service/internal/collector/agent"
 	compress "github.com/Stern-Ritter/metrics-and-alerting-service/internal/compress/agent"
 	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
 	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
@@ -25,70 +33,272 @@ import (
 	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
 )
 
-// Run starts the agent, setting up and managing tasks.
+// Run starts the agent, setting up and managing tasks. The build version is reported
+// in the agent self-telemetry metrics. The agent config is reloaded with the default config
+// on SIGHUP and on the config file changes.
 // It returns an error if there are issues starting the agent.
-func Run(config *config.AgentConfig, logger *logger.AgentLogger) error {
+func Run(config *config.AgentConfig, defaultConfig config.AgentConfig, buildVersion string,
+	logger *logger.AgentLogger) error {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	defer cancel()
 
-	cache := storage.NewAgentMemCache(metrics.SupportedGaugeMetrics, metrics.SupportedCounterMetrics, logger)
-	runtimeMonitor := monitors.RuntimeMonitor{}
-	utilMonitor := monitors.UtilMonitor{}
-	random := utils.NewRandom()
+	cache := storage.NewAgentMemCache(make(map[string]metrics.GaugeMetric), metrics.SupportedCounterMetrics, logger)
+	collectors, err := newCollectors(config)
+	if err != nil {
+		logger.Fatal(err.Error(), zap.String("event", "create metrics collectors"))
+	}
+
+	cache.SetAggregations(config.Aggregations)
+	agent := service.NewAgent(&cache, collectors, config, logger)
+	agent.SetBuildVersion(buildVersion)
+	relabelRules, err := service.NewRelabelRules(config.Relabel)
+	if err != nil {
+		logger.Fatal(err.Error(), zap.String("event", "create relabel rules"))
+	}
+	agent.SetRelabelRules(relabelRules)
+
+	workersWg := sync.WaitGroup{}
+	closers := make(map[string]func(), len(config.Destinations))
+	defer func() {
+		for _, closeDestination := range closers {
+			closeDestination()
+		}
+	}()
+	for i := range config.Destinations {
+		destination, closeDestination, err := startDestination(&config.Destinations[i], config, logger, &workersWg)
+		if err != nil {
+			logger.Fatal(err.Error(), zap.String("event", "start destination"),
+				zap.String("destination", config.Destinations[i].Name))
+		}
+		closers[config.Destinations[i].Name] = closeDestination
+		agent.AddDestination(destination)
+	}
 
-	rsaPublicKey, err := service.GetRSAPublicKey(config.CryptoKeyPath)
+	tasks, err := startTasks(ctx, agent)
 	if err != nil {
-		logger.Fatal(err.Error(), zap.String("event", "get rsa public key"))
+		logger.Fatal(err.Error(), zap.String("event", "start metrics collectors"))
 	}
 
-	agent := service.NewAgent(&cache, &runtimeMonitor, &utilMonitor, &random, config, rsaPublicKey, logger)
+	pushWg := sync.WaitGroup{}
+	if err := runPushServer(ctx, agent, &pushWg); err != nil {
+		logger.Fatal(err.Error(), zap.String("event", "start push server"))
+	}
 
-	tasksWg := sync.WaitGroup{}
-	service.SetInterval(ctx, &tasksWg, agent.UpdateRuntimeMetrics, time.Duration(agent.Config.UpdateMetricsInterval)*time.Second)
-	service.SetInterval(ctx, &tasksWg, agent.UpdateUtilMetrics, time.Duration(agent.Config.UpdateMetricsInterval)*time.Second)
-	service.SetInterval(ctx, &tasksWg, agent.SendMetrics, time.Duration(agent.Config.SendMetricsInterval)*time.Second)
+	r := &reloader{agent: agent, defaultConfig: defaultConfig, tasks: tasks, closers: closers, logger: logger}
+	r.run(ctx)
 
-	workersWg := sync.WaitGroup{}
+	r.tasks.stop()
+	pushWg.Wait()
+	for _, destination := range agent.Destinations {
+		destination.StopSendMetricsWorkerPool()
+	}
+	workersWg.Wait()
+
+	return nil
+}
+
+// startDestination creates the destination with its spool and its HTTP or gRPC client
+// and starts the destination send metrics worker pool. The returned function closes
+// the destination connection.
+func startDestination(cfg *config.DestinationConfig, agentCfg *config.AgentConfig, logger *logger.AgentLogger,
+	wg *sync.WaitGroup) (*service.Destination, func(), error) {
+	destination := service.NewDestination(cfg, agentCfg, nil, logger)
 
-	if config.GRPC {
+	if len(cfg.SpoolDir) > 0 {
+		spool, err := storage.NewSpool(cfg.SpoolDir, agentCfg.SpoolMaxSize,
+			time.Duration(agentCfg.SpoolMaxAge)*time.Second, agentCfg.SpoolSegmentSize, destination.Logger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open metrics spool: %w", err)
+		}
+		destination.SetSpool(spool)
+	}
+
+	clients, err := newDestinationClients(cfg, destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	destination.Update(cfg, clients.rsaPublicKey, clients.httpClient, clients.grpcClient)
+
+	destination.StartSendMetricsWorkerPool(wg, sendMetricsWorker(cfg, destination))
+
+	return destination, clients.close, nil
+}
+
+// destinationClients holds the RSA public key and the client of a destination.
+type destinationClients struct {
+	rsaPublicKey *rsa.PublicKey
+	httpClient   *gentleman.Client
+	grpcClient   pb.MetricsV1ServiceClient
+	close        func()
+}
+
+// newDestinationClients loads the RSA public key and creates the HTTP or gRPC client for the destination config.
+// The clients sign and encrypt requests with the current keys of the destination.
+func newDestinationClients(cfg *config.DestinationConfig, destination *service.Destination) (*destinationClients, error) {
+	rsaPublicKey, err := service.GetRSAPublicKey(cfg.CryptoKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("get rsa public key: %w", err)
+	}
+
+	if cfg.GRPC {
 		opts := make([]grpc.DialOption, 0)
 
 		opts = append(opts, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
-		isEncryptionEnabled := len(agent.Config.TLSCertPath) > 0
+		isEncryptionEnabled := len(cfg.TLSCertPath) > 0
 		if isEncryptionEnabled {
-			creds, err := credentials.NewClientTLSFromFile(agent.Config.TLSCertPath, "")
+			creds, err := credentials.NewClientTLSFromFile(cfg.TLSCertPath, "")
 			if err != nil {
-				logger.Fatal(err.Error(), zap.String("event", "load credentials"))
+				return nil, fmt.Errorf("load credentials: %w", err)
 			}
 			opts = append(opts, grpc.WithTransportCredentials(creds))
 		} else {
 			opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		}
-		opts = append(opts, grpc.WithUnaryInterceptor(agent.SignInterceptor))
+		opts = append(opts, grpc.WithUnaryInterceptor(destination.SignInterceptor))
+		opts = append(opts, grpc.WithStreamInterceptor(destination.SignStreamInterceptor))
 
-		conn, err := grpc.Dial(config.SendMetricsURL, opts...)
+		conn, err := grpc.Dial(cfg.SendMetricsURL, opts...)
 		if err != nil {
-			logger.Fatal(err.Error(), zap.String("event", "get grpc connection"))
+			return nil, fmt.Errorf("get grpc connection: %w", err)
 		}
-		defer conn.Close()
-		client := pb.NewMetricsV1ServiceClient(conn)
-		agent.SetGRPCClient(client)
 
-		agent.StartSendMetricsWorkerPool(&workersWg, agent.SendMetricsWithGrpcWorker)
-	} else {
-		client := gentleman.New()
-		client.URL(config.SendMetricsURL)
-		client.UseHandler("before dial", compress.GzipMiddleware)
-		client.UseHandler("before dial", agent.EncryptMiddleware)
-		client.UseHandler("before dial", agent.SignMiddleware)
-		agent.SetHTTPClient(client)
+		closeConn := func() {
+			if err := conn.Close(); err != nil {
+				destination.Logger.Error(err.Error(), zap.String("event", "close grpc connection"))
+			}
+		}
+		return &destinationClients{rsaPublicKey: rsaPublicKey, grpcClient: pb.NewMetricsV1ServiceClient(conn),
+			close: closeConn}, nil
+	}
 
-		agent.StartSendMetricsWorkerPool(&workersWg, agent.SendMetricsWithHTTPWorker)
+	client := gentleman.New()
+	client.URL(cfg.SendMetricsURL)
+	client.UseHandler("before dial", compress.GzipMiddleware)
+	client.UseHandler("before dial", destination.EncryptMiddleware)
+	client.UseHandler("before dial", destination.SignMiddleware)
+
+	return &destinationClients{rsaPublicKey: rsaPublicKey, httpClient: client, close: func() {}}, nil
+}
+
+// sendMetricsWorker returns the send metrics worker of the destination for the protocol of the destination config.
+func sendMetricsWorker(cfg *config.DestinationConfig, destination *service.Destination) service.SendMetricsWorker {
+	if cfg.GRPC {
+		return destination.SendMetricsWithGrpcStreamWorker
 	}
+	return destination.SendMetricsWithHTTPWorker
+}
 
-	tasksWg.Wait()
-	agent.StopSendMetricsWorkerPool()
-	workersWg.Wait()
+// newCollectors creates the registry of the agent metrics collectors.
+func newCollectors(config *config.AgentConfig) (*collector.Registry, error) {
+	random := utils.NewRandom()
+
+	processCollector, err := collector.NewProcessCollector(config.Processes)
+	if err != nil {
+		return nil, err
+	}
+	execCollector, err := collector.NewExecCollector(config.Exec)
+	if err != nil {
+		return nil, err
+	}
+	probeCollector, err := collector.NewProbeCollector(config.Probes)
+	if err != nil {
+		return nil, err
+	}
+	logCollector, err := collector.NewLogCollector(config.Logs)
+	if err != nil {
+		return nil, err
+	}
+
+	return collector.NewRegistry(
+		collector.NewRuntimeCollector(&monitors.RuntimeMonitor{}, &random),
+		collector.NewUtilCollector(&monitors.UtilMonitor{}),
+		collector.NewDiskCollector(config.Disk),
+		collector.NewNetworkCollector(config.Network),
+		processCollector,
+		execCollector,
+		probeCollector,
+		logCollector,
+	)
+}
+
+// removeStaleSocket removes the Unix socket left at the path by the previous agent run.
+// It returns an error if something other than a socket is at the path.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("push socket path %s exists and isn't a socket", path)
+	}
+	return os.Remove(path)
+}
+
+// runPushServer starts the HTTP server accepting metrics from local applications on the push address
+// and the push Unix socket. The server is shut down when the context is done.
+func runPushServer(ctx context.Context, agent *service.Agent, wg *sync.WaitGroup) error {
+	listeners := make([]net.Listener, 0, 2)
+	if len(agent.Config.PushAddress) > 0 {
+		listener, err := net.Listen("tcp", agent.Config.PushAddress)
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
+	if len(agent.Config.PushSocket) > 0 {
+		if err := removeStaleSocket(agent.Config.PushSocket); err != nil {
+			closeListeners(listeners)
+			return err
+		}
+		listener, err := net.Listen("unix", agent.Config.PushSocket)
+		if err != nil {
+			closeListeners(listeners)
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
+	if len(listeners) == 0 {
+		return nil
+	}
+
+	r := chi.NewRouter()
+	r.Post("/update", agent.PushMetricHandler)
+	r.Post("/update/", agent.PushMetricHandler)
+	r.Post("/updates", agent.PushMetricsBatchHandler)
+	r.Post("/updates/", agent.PushMetricsBatchHandler)
+	srv := &http.Server{Handler: r, ReadHeaderTimeout: 5 * time.Second}
+
+	for _, listener := range listeners {
+		wg.Add(1)
+		go func(listener net.Listener) {
+			defer wg.Done()
+			agent.Logger.Info("Starting push server", zap.String("event", "start push server"),
+				zap.String("address", listener.Addr().String()))
+			if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				agent.Logger.Error(err.Error(), zap.String("event", "serve push server"))
+			}
+		}(listener)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			agent.Logger.Error(err.Error(), zap.String("event", "shutdown push server"))
+		}
+	}()
 
 	return nil
 }
+
+func closeListeners(listeners []net.Listener) {
+	for _, listener := range listeners {
+		listener.Close()
+	}
+}
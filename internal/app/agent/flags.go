@@ -2,30 +2,140 @@ package agent
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/caarlos0/env"
 
 	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	service "github.com/Stern-Ritter/metrics-and-alerting-service/internal/service/agent"
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
 )
 
 type jsonConfig struct {
-	SendMetricsURL        string `json:"address,omitempty"`
-	SendMetricsEndPoint   string `json:"endpoint,omitempty"`
-	UpdateMetricsInterval int    `json:"poll_interval,omitempty"`
-	SendMetricsInterval   int    `json:"report_interval,omitempty"`
-	MetricsBufferSize     int    `json:"metrics_buffer_size,omitempty"`
-	RateLimit             int    `json:"rate_limit,omitempty"`
-	GRPC                  bool   `json:"grpc,omitempty"`
-	SecretKey             string `json:"sign_key,omitempty"`
-	CryptoKeyPath         string `json:"crypto_key,omitempty"`
-	TLSCertPath           string `json:"tls_cert,omitempty"`
-	LoggerLvl             string `json:"logger_level,omitempty"`
+	SendMetricsURL        string                         `json:"address,omitempty"`
+	SendMetricsEndPoint   string                         `json:"endpoint,omitempty"`
+	UpdateMetricsInterval int                            `json:"poll_interval,omitempty"`
+	SendMetricsInterval   int                            `json:"report_interval,omitempty"`
+	ScheduleAlign         bool                           `json:"schedule_align,omitempty"`
+	ScheduleJitter        int                            `json:"schedule_jitter,omitempty"`
+	MetricsBufferSize     int                            `json:"metrics_buffer_size,omitempty"`
+	BackpressurePolicy    string                         `json:"backpressure_policy,omitempty"`
+	RateLimit             int                            `json:"rate_limit,omitempty"`
+	BatchMaxItems         int                            `json:"batch_max_items,omitempty"`
+	BatchMaxBytes         int                            `json:"batch_max_bytes,omitempty"`
+	GRPC                  bool                           `json:"grpc,omitempty"`
+	SecretKey             string                         `json:"sign_key,omitempty"`
+	CryptoKeyPath         string                         `json:"crypto_key,omitempty"`
+	TLSCertPath           string                         `json:"tls_cert,omitempty"`
+	LoggerLvl             string                         `json:"logger_level,omitempty"`
+	ConfigWatchInterval   int                            `json:"config_watch_interval,omitempty"`
+	PushAddress           string                         `json:"push_address,omitempty"`
+	PushSocket            string                         `json:"push_socket,omitempty"`
+	AgentID               string                         `json:"agent_id,omitempty"`
+	SpoolDir              string                         `json:"spool_dir,omitempty"`
+	SpoolMaxSize          int64                          `json:"spool_max_size,omitempty"`
+	SpoolMaxAge           int                            `json:"spool_max_age,omitempty"`
+	SpoolSegmentSize      int64                          `json:"spool_segment_size,omitempty"`
+	Destinations          []jsonDestinationConfig        `json:"destinations,omitempty"`
+	Collectors            map[string]jsonCollectorConfig `json:"collectors,omitempty"`
+	Disk                  *jsonDiskCollectorConfig       `json:"disk,omitempty"`
+	Network               *jsonNetworkCollectorConfig    `json:"network,omitempty"`
+	Processes             []jsonProcessConfig            `json:"processes,omitempty"`
+	Exec                  []jsonExecConfig               `json:"exec,omitempty"`
+	Probes                []jsonProbeConfig              `json:"probes,omitempty"`
+	Logs                  []jsonLogConfig                `json:"logs,omitempty"`
+	Aggregations          []jsonAggregationConfig        `json:"aggregations,omitempty"`
+	Relabel               []jsonRelabelConfig            `json:"relabel,omitempty"`
+}
+
+type jsonDestinationConfig struct {
+	Name                string `json:"name"`
+	SendMetricsURL      string `json:"address"`
+	SendMetricsEndPoint string `json:"endpoint,omitempty"`
+	GRPC                bool   `json:"grpc,omitempty"`
+	SecretKey           string `json:"sign_key,omitempty"`
+	CryptoKeyPath       string `json:"crypto_key,omitempty"`
+	TLSCertPath         string `json:"tls_cert,omitempty"`
+	RateLimit           int    `json:"rate_limit,omitempty"`
+	BackpressurePolicy  string `json:"backpressure_policy,omitempty"`
+	BatchMaxItems       int    `json:"batch_max_items,omitempty"`
+	BatchMaxBytes       int    `json:"batch_max_bytes,omitempty"`
+	SpoolDir            string `json:"spool_dir,omitempty"`
+}
+
+type jsonCollectorConfig struct {
+	Enabled      *bool `json:"enabled,omitempty"`
+	PollInterval int   `json:"poll_interval,omitempty"`
+	Timeout      int   `json:"timeout,omitempty"`
+}
+
+type jsonFilterConfig struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+type jsonDiskCollectorConfig struct {
+	FSTypes     jsonFilterConfig `json:"fs_types,omitempty"`
+	MountPoints jsonFilterConfig `json:"mount_points,omitempty"`
+}
+
+type jsonNetworkCollectorConfig struct {
+	Interfaces jsonFilterConfig `json:"interfaces,omitempty"`
+}
+
+type jsonProcessConfig struct {
+	Name         string `json:"name"`
+	ProcessName  string `json:"process_name,omitempty"`
+	PIDFile      string `json:"pidfile,omitempty"`
+	CmdlineRegex string `json:"cmdline,omitempty"`
+}
+
+type jsonExecConfig struct {
+	Name    string   `json:"name"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	Timeout int      `json:"timeout,omitempty"`
+}
+
+type jsonProbeConfig struct {
+	Name               string `json:"name"`
+	Type               string `json:"type"`
+	Target             string `json:"target"`
+	Timeout            int    `json:"timeout,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+type jsonLogConfig struct {
+	Name  string              `json:"name"`
+	Path  string              `json:"path"`
+	Rules []jsonLogRuleConfig `json:"rules"`
+}
+
+type jsonLogRuleConfig struct {
+	Name    string    `json:"name"`
+	Regex   string    `json:"regex"`
+	Buckets []float64 `json:"buckets,omitempty"`
+}
+
+type jsonAggregationConfig struct {
+	Pattern   string   `json:"pattern"`
+	Functions []string `json:"functions,omitempty"`
+}
+
+type jsonRelabelConfig struct {
+	Action      string            `json:"action"`
+	Regex       string            `json:"regex,omitempty"`
+	Replacement string            `json:"replacement,omitempty"`
+	Prefix      string            `json:"prefix,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
 }
 
 // GetConfig initializes the agent config by parsing command-line flags, environment variables, and a JSON config file.
@@ -39,9 +149,12 @@ type jsonConfig struct {
 func GetConfig(defaultCfg config.AgentConfig) (config.AgentConfig, error) {
 	cfg := config.AgentConfig{}
 
-	parseFlags(&cfg)
+	err := parseFlags(&cfg, os.Args[1:])
+	if err != nil {
+		return cfg, err
+	}
 
-	err := env.Parse(&cfg)
+	err = env.Parse(&cfg)
 	if err != nil {
 		return cfg, err
 	}
@@ -59,6 +172,15 @@ func GetConfig(defaultCfg config.AgentConfig) (config.AgentConfig, error) {
 
 	trimStringVarsSpaces(&cfg)
 
+	mergeDestinationsConfig(&cfg)
+
+	if len(cfg.AgentID) == 0 {
+		cfg.AgentID, err = defaultAgentID()
+		if err != nil {
+			return cfg, err
+		}
+	}
+
 	err = validateConfig(cfg)
 	if err != nil {
 		return cfg, err
@@ -67,17 +189,33 @@ func GetConfig(defaultCfg config.AgentConfig) (config.AgentConfig, error) {
 	return cfg, nil
 }
 
-func parseFlags(cfg *config.AgentConfig) {
-	flag.StringVar(&cfg.SendMetricsURL, "a", "", "address and port to run server in format <host>:<port>")
-	flag.IntVar(&cfg.UpdateMetricsInterval, "p", 0, "interval for updating metrics in seconds")
-	flag.IntVar(&cfg.SendMetricsInterval, "r", 0, "interval for sending metrics to the server in seconds")
-	flag.IntVar(&cfg.RateLimit, "l", 0, "limit of concurrent requests to the server")
-	flag.BoolVar(&cfg.GRPC, "grpc", false, "grpc usage")
-	flag.StringVar(&cfg.SecretKey, "k", "", "secret authentication key")
-	flag.StringVar(&cfg.CryptoKeyPath, "crypto-key", "", "path to secret public key for asymmetric encryption")
-	flag.StringVar(&cfg.TLSCertPath, "tls-cert", "", "path to tls certificate")
-	flag.StringVar(&cfg.ConfigFile, "c", "", "path to json config file")
-	flag.Parse()
+// parseFlags parses the command-line arguments with a new flag set, so the config can be parsed again on reload.
+func parseFlags(cfg *config.AgentConfig, args []string) error {
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	fs.StringVar(&cfg.SendMetricsURL, "a", "", "address and port to run server in format <host>:<port>")
+	fs.IntVar(&cfg.UpdateMetricsInterval, "p", 0, "interval for updating metrics in seconds")
+	fs.IntVar(&cfg.SendMetricsInterval, "r", 0, "interval for sending metrics to the server in seconds")
+	fs.IntVar(&cfg.RateLimit, "l", 0, "limit of concurrent requests to the server")
+	fs.IntVar(&cfg.BatchMaxItems, "batch-max-items", 0, "maximum number of metrics in a batch sent to the server")
+	fs.IntVar(&cfg.BatchMaxBytes, "batch-max-bytes", 0, "maximum json-encoded size of a batch sent to the server in bytes")
+	fs.BoolVar(&cfg.ScheduleAlign, "schedule-align", false, "align tasks runs to the wall clock multiples of their intervals")
+	fs.IntVar(&cfg.ScheduleJitter, "schedule-jitter", 0, "maximum random delay of the tasks first runs in seconds")
+	fs.BoolVar(&cfg.GRPC, "grpc", false, "grpc usage")
+	fs.StringVar(&cfg.BackpressurePolicy, "backpressure-policy", "",
+		"policy applied when the metrics buffer is full: block, drop-oldest, drop-newest or coalesce")
+	fs.StringVar(&cfg.SecretKey, "k", "", "secret authentication key")
+	fs.StringVar(&cfg.CryptoKeyPath, "crypto-key", "", "path to secret public key for asymmetric encryption")
+	fs.StringVar(&cfg.TLSCertPath, "tls-cert", "", "path to tls certificate")
+	fs.StringVar(&cfg.ConfigFile, "c", "", "path to json config file")
+	fs.IntVar(&cfg.ConfigWatchInterval, "config-watch-interval", 0,
+		"interval for checking the json config file changes in seconds, the file isn't watched when it's zero")
+	fs.StringVar(&cfg.PushAddress, "push-address", "", "localhost address and port to accept metrics from local applications")
+	fs.StringVar(&cfg.PushSocket, "push-socket", "", "path to unix socket to accept metrics from local applications")
+	fs.StringVar(&cfg.AgentID, "agent-id", "", "agent identity used to deduplicate metrics batches on the server")
+	fs.StringVar(&cfg.SpoolDir, "spool-dir", "", "directory for spooling metrics that failed to be sent")
+	fs.Int64Var(&cfg.SpoolMaxSize, "spool-max-size", 0, "maximum size of the spool in bytes")
+	fs.IntVar(&cfg.SpoolMaxAge, "spool-max-age", 0, "maximum age of spooled metrics in seconds")
+	return fs.Parse(args)
 }
 
 func parseJSONConfig(cfg *config.AgentConfig, fPath string) error {
@@ -104,13 +242,76 @@ func mergeJSONConfig(cfg *config.AgentConfig, jsonCfg jsonConfig) {
 	cfg.SendMetricsEndPoint = utils.Coalesce(cfg.SendMetricsEndPoint, jsonCfg.SendMetricsEndPoint)
 	cfg.UpdateMetricsInterval = utils.Coalesce(cfg.UpdateMetricsInterval, jsonCfg.UpdateMetricsInterval)
 	cfg.SendMetricsInterval = utils.Coalesce(cfg.SendMetricsInterval, jsonCfg.SendMetricsInterval)
+	cfg.ScheduleAlign = utils.Coalesce(cfg.ScheduleAlign, jsonCfg.ScheduleAlign)
+	cfg.ScheduleJitter = utils.Coalesce(cfg.ScheduleJitter, jsonCfg.ScheduleJitter)
 	cfg.MetricsBufferSize = utils.Coalesce(cfg.MetricsBufferSize, jsonCfg.MetricsBufferSize)
+	cfg.BackpressurePolicy = utils.Coalesce(cfg.BackpressurePolicy, jsonCfg.BackpressurePolicy)
 	cfg.RateLimit = utils.Coalesce(cfg.RateLimit, jsonCfg.RateLimit)
+	cfg.BatchMaxItems = utils.Coalesce(cfg.BatchMaxItems, jsonCfg.BatchMaxItems)
+	cfg.BatchMaxBytes = utils.Coalesce(cfg.BatchMaxBytes, jsonCfg.BatchMaxBytes)
 	cfg.GRPC = utils.Coalesce(cfg.GRPC, jsonCfg.GRPC)
 	cfg.SecretKey = utils.Coalesce(cfg.SecretKey, jsonCfg.SecretKey)
 	cfg.CryptoKeyPath = utils.Coalesce(cfg.CryptoKeyPath, jsonCfg.CryptoKeyPath)
 	cfg.TLSCertPath = utils.Coalesce(cfg.TLSCertPath, jsonCfg.TLSCertPath)
 	cfg.LoggerLvl = utils.Coalesce(cfg.LoggerLvl, jsonCfg.LoggerLvl)
+	cfg.ConfigWatchInterval = utils.Coalesce(cfg.ConfigWatchInterval, jsonCfg.ConfigWatchInterval)
+	cfg.PushAddress = utils.Coalesce(cfg.PushAddress, jsonCfg.PushAddress)
+	cfg.PushSocket = utils.Coalesce(cfg.PushSocket, jsonCfg.PushSocket)
+	cfg.AgentID = utils.Coalesce(cfg.AgentID, jsonCfg.AgentID)
+	cfg.SpoolDir = utils.Coalesce(cfg.SpoolDir, jsonCfg.SpoolDir)
+	cfg.SpoolMaxSize = utils.Coalesce(cfg.SpoolMaxSize, jsonCfg.SpoolMaxSize)
+	cfg.SpoolMaxAge = utils.Coalesce(cfg.SpoolMaxAge, jsonCfg.SpoolMaxAge)
+	cfg.SpoolSegmentSize = utils.Coalesce(cfg.SpoolSegmentSize, jsonCfg.SpoolSegmentSize)
+
+	for _, d := range jsonCfg.Destinations {
+		cfg.Destinations = append(cfg.Destinations, config.DestinationConfig(d))
+	}
+
+	if cfg.Collectors == nil && jsonCfg.Collectors != nil {
+		cfg.Collectors = make(map[string]config.CollectorConfig, len(jsonCfg.Collectors))
+		for name, c := range jsonCfg.Collectors {
+			cfg.Collectors[name] = config.CollectorConfig(c)
+		}
+	}
+
+	if jsonCfg.Disk != nil {
+		cfg.Disk = config.DiskCollectorConfig{
+			FSTypes:     config.FilterConfig(jsonCfg.Disk.FSTypes),
+			MountPoints: config.FilterConfig(jsonCfg.Disk.MountPoints),
+		}
+	}
+
+	if jsonCfg.Network != nil {
+		cfg.Network = config.NetworkCollectorConfig{Interfaces: config.FilterConfig(jsonCfg.Network.Interfaces)}
+	}
+
+	for _, p := range jsonCfg.Processes {
+		cfg.Processes = append(cfg.Processes, config.ProcessConfig(p))
+	}
+
+	for _, e := range jsonCfg.Exec {
+		cfg.Exec = append(cfg.Exec, config.ExecConfig(e))
+	}
+
+	for _, p := range jsonCfg.Probes {
+		cfg.Probes = append(cfg.Probes, config.ProbeConfig(p))
+	}
+
+	for _, a := range jsonCfg.Aggregations {
+		cfg.Aggregations = append(cfg.Aggregations, config.AggregationConfig(a))
+	}
+
+	for _, r := range jsonCfg.Relabel {
+		cfg.Relabel = append(cfg.Relabel, config.RelabelConfig(r))
+	}
+
+	for _, l := range jsonCfg.Logs {
+		logCfg := config.LogConfig{Name: l.Name, Path: l.Path}
+		for _, r := range l.Rules {
+			logCfg.Rules = append(logCfg.Rules, config.LogRuleConfig(r))
+		}
+		cfg.Logs = append(cfg.Logs, logCfg)
+	}
 }
 
 func mergeDefaultConfig(cfg *config.AgentConfig, defaultCgf config.AgentConfig) {
@@ -118,14 +319,30 @@ func mergeDefaultConfig(cfg *config.AgentConfig, defaultCgf config.AgentConfig)
 	cfg.SendMetricsEndPoint = utils.Coalesce(cfg.SendMetricsEndPoint, defaultCgf.SendMetricsEndPoint)
 	cfg.UpdateMetricsInterval = utils.Coalesce(cfg.UpdateMetricsInterval, defaultCgf.UpdateMetricsInterval)
 	cfg.SendMetricsInterval = utils.Coalesce(cfg.SendMetricsInterval, defaultCgf.SendMetricsInterval)
+	cfg.ScheduleAlign = utils.Coalesce(cfg.ScheduleAlign, defaultCgf.ScheduleAlign)
+	cfg.ScheduleJitter = utils.Coalesce(cfg.ScheduleJitter, defaultCgf.ScheduleJitter)
 	cfg.MetricsBufferSize = utils.Coalesce(cfg.MetricsBufferSize, defaultCgf.MetricsBufferSize)
+	cfg.BackpressurePolicy = utils.Coalesce(cfg.BackpressurePolicy, defaultCgf.BackpressurePolicy)
 	cfg.RateLimit = utils.Coalesce(cfg.RateLimit, defaultCgf.RateLimit)
+	cfg.BatchMaxItems = utils.Coalesce(cfg.BatchMaxItems, defaultCgf.BatchMaxItems)
+	cfg.BatchMaxBytes = utils.Coalesce(cfg.BatchMaxBytes, defaultCgf.BatchMaxBytes)
 	cfg.GRPC = utils.Coalesce(cfg.GRPC, defaultCgf.GRPC)
 	cfg.SecretKey = utils.Coalesce(cfg.SecretKey, defaultCgf.SecretKey)
 	cfg.CryptoKeyPath = utils.Coalesce(cfg.CryptoKeyPath, defaultCgf.CryptoKeyPath)
 	cfg.TLSCertPath = utils.Coalesce(cfg.TLSCertPath, defaultCgf.TLSCertPath)
 	cfg.ConfigFile = utils.Coalesce(cfg.ConfigFile, defaultCgf.ConfigFile)
 	cfg.LoggerLvl = utils.Coalesce(cfg.LoggerLvl, defaultCgf.LoggerLvl)
+	cfg.ConfigWatchInterval = utils.Coalesce(cfg.ConfigWatchInterval, defaultCgf.ConfigWatchInterval)
+	cfg.PushAddress = utils.Coalesce(cfg.PushAddress, defaultCgf.PushAddress)
+	cfg.PushSocket = utils.Coalesce(cfg.PushSocket, defaultCgf.PushSocket)
+	cfg.AgentID = utils.Coalesce(cfg.AgentID, defaultCgf.AgentID)
+	cfg.SpoolDir = utils.Coalesce(cfg.SpoolDir, defaultCgf.SpoolDir)
+	cfg.SpoolMaxSize = utils.Coalesce(cfg.SpoolMaxSize, defaultCgf.SpoolMaxSize)
+	cfg.SpoolMaxAge = utils.Coalesce(cfg.SpoolMaxAge, defaultCgf.SpoolMaxAge)
+	cfg.SpoolSegmentSize = utils.Coalesce(cfg.SpoolSegmentSize, defaultCgf.SpoolSegmentSize)
+	if cfg.Collectors == nil {
+		cfg.Collectors = defaultCgf.Collectors
+	}
 }
 
 func trimStringVarsSpaces(cfg *config.AgentConfig) {
@@ -136,8 +353,234 @@ func trimStringVarsSpaces(cfg *config.AgentConfig) {
 	cfg.TLSCertPath = strings.TrimSpace(cfg.TLSCertPath)
 	cfg.ConfigFile = strings.TrimSpace(cfg.ConfigFile)
 	cfg.LoggerLvl = strings.TrimSpace(cfg.LoggerLvl)
+	cfg.BackpressurePolicy = strings.TrimSpace(cfg.BackpressurePolicy)
+	cfg.PushAddress = strings.TrimSpace(cfg.PushAddress)
+	cfg.PushSocket = strings.TrimSpace(cfg.PushSocket)
+	cfg.AgentID = strings.TrimSpace(cfg.AgentID)
+	cfg.SpoolDir = strings.TrimSpace(cfg.SpoolDir)
+}
+
+// defaultDestinationName is the name of the destination made of the top-level send settings.
+const defaultDestinationName = "default"
+
+// mergeDestinationsConfig sets up the servers the agent sends metrics statistics to. When no destinations
+// are configured, the single default destination is made of the top-level send settings. Otherwise,
+// the destinations take the endpoint, the rate limit, the backpressure policy, the batch limits and
+// the spool directory from the top-level settings unless they set their own, while the keys and
+// certificates of each destination are never shared. The spool of a destination is kept in a subdirectory
+// named after it.
+func mergeDestinationsConfig(cfg *config.AgentConfig) {
+	if len(cfg.Destinations) == 0 {
+		cfg.Destinations = []config.DestinationConfig{{
+			Name:                defaultDestinationName,
+			SendMetricsURL:      cfg.SendMetricsURL,
+			SendMetricsEndPoint: cfg.SendMetricsEndPoint,
+			GRPC:                cfg.GRPC,
+			SecretKey:           cfg.SecretKey,
+			CryptoKeyPath:       cfg.CryptoKeyPath,
+			TLSCertPath:         cfg.TLSCertPath,
+			RateLimit:           cfg.RateLimit,
+			BackpressurePolicy:  cfg.BackpressurePolicy,
+			BatchMaxItems:       cfg.BatchMaxItems,
+			BatchMaxBytes:       cfg.BatchMaxBytes,
+			SpoolDir:            cfg.SpoolDir,
+		}}
+		return
+	}
+
+	for i := range cfg.Destinations {
+		d := &cfg.Destinations[i]
+		d.Name = strings.TrimSpace(d.Name)
+		d.SendMetricsURL = strings.TrimSpace(d.SendMetricsURL)
+		d.SendMetricsEndPoint = utils.Coalesce(strings.TrimSpace(d.SendMetricsEndPoint), cfg.SendMetricsEndPoint)
+		d.SecretKey = strings.TrimSpace(d.SecretKey)
+		d.CryptoKeyPath = strings.TrimSpace(d.CryptoKeyPath)
+		d.TLSCertPath = strings.TrimSpace(d.TLSCertPath)
+		d.RateLimit = utils.Coalesce(d.RateLimit, cfg.RateLimit)
+		d.BackpressurePolicy = utils.Coalesce(strings.TrimSpace(d.BackpressurePolicy), cfg.BackpressurePolicy)
+		d.BatchMaxItems = utils.Coalesce(d.BatchMaxItems, cfg.BatchMaxItems)
+		d.BatchMaxBytes = utils.Coalesce(d.BatchMaxBytes, cfg.BatchMaxBytes)
+		d.SpoolDir = strings.TrimSpace(d.SpoolDir)
+		if len(d.SpoolDir) == 0 && len(cfg.SpoolDir) > 0 && len(d.Name) > 0 {
+			d.SpoolDir = filepath.Join(cfg.SpoolDir, d.Name)
+		}
+	}
+}
+
+// defaultAgentID returns the agent identity of the host name and a random suffix generated
+// for the process, so the batch keys of the agents running on the hosts with the same name don't match.
+func defaultAgentID() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("get agent id from host name: %w", err)
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generate agent id suffix: %w", err)
+	}
+
+	return fmt.Sprintf("%s-%s", hostname, hex.EncodeToString(suffix)), nil
 }
 
 func validateConfig(cfg config.AgentConfig) error {
-	return utils.ValidateHostnamePort(cfg.SendMetricsURL)
+	err := validateDestinations(cfg.Destinations)
+	if err != nil {
+		return err
+	}
+
+	err = validateIntervals(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range cfg.Destinations {
+		if err := validateBackpressurePolicy(d.BackpressurePolicy, cfg.MetricsBufferSize); err != nil {
+			return fmt.Errorf("destination %s: %w", d.Name, err)
+		}
+	}
+
+	err = validateAggregations(cfg.Aggregations)
+	if err != nil {
+		return err
+	}
+
+	err = validateRelabel(cfg.Relabel)
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.PushAddress) > 0 {
+		return validateLoopbackAddress(cfg.PushAddress)
+	}
+	return nil
+}
+
+// validateDestinations checks that each destination has a unique name, a valid address, non-negative
+// batch limits and a spool directory that isn't shared with another destination.
+func validateDestinations(destinations []config.DestinationConfig) error {
+	names := make(map[string]struct{}, len(destinations))
+	spoolDirs := make(map[string]struct{}, len(destinations))
+
+	for _, d := range destinations {
+		if len(d.Name) == 0 {
+			return fmt.Errorf("destination name is required")
+		}
+		if _, exists := names[d.Name]; exists {
+			return fmt.Errorf("duplicate destination name: %s", d.Name)
+		}
+		names[d.Name] = struct{}{}
+
+		if err := utils.ValidateHostnamePort(d.SendMetricsURL); err != nil {
+			return fmt.Errorf("destination %s: %w", d.Name, err)
+		}
+
+		if d.BatchMaxItems < 0 || d.BatchMaxBytes < 0 {
+			return fmt.Errorf("destination %s: batch limits can't be negative: %d items, %d bytes",
+				d.Name, d.BatchMaxItems, d.BatchMaxBytes)
+		}
+
+		if len(d.SpoolDir) > 0 {
+			spoolDir := filepath.Clean(d.SpoolDir)
+			if _, exists := spoolDirs[spoolDir]; exists {
+				return fmt.Errorf("destination %s: spool directory %s is used by another destination", d.Name, d.SpoolDir)
+			}
+			spoolDirs[spoolDir] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// validateIntervals checks that the agent tasks are scheduled at positive intervals
+// and the schedule jitter and the collector timeouts aren't negative.
+func validateIntervals(cfg config.AgentConfig) error {
+	if cfg.UpdateMetricsInterval <= 0 {
+		return fmt.Errorf("poll interval should be positive: %d", cfg.UpdateMetricsInterval)
+	}
+	if cfg.SendMetricsInterval <= 0 {
+		return fmt.Errorf("report interval should be positive: %d", cfg.SendMetricsInterval)
+	}
+	if cfg.ScheduleJitter < 0 {
+		return fmt.Errorf("schedule jitter can't be negative: %d", cfg.ScheduleJitter)
+	}
+	for name, c := range cfg.Collectors {
+		if c.PollInterval < 0 {
+			return fmt.Errorf("collector %s poll interval can't be negative: %d", name, c.PollInterval)
+		}
+		if c.Timeout < 0 {
+			return fmt.Errorf("collector %s timeout can't be negative: %d", name, c.Timeout)
+		}
+	}
+	return nil
+}
+
+// validateBackpressurePolicy checks that the backpressure policy is known. The policies other than block
+// take batches out of the full metrics channel, so they require the buffered metrics channel.
+func validateBackpressurePolicy(policy string, bufferSize int) error {
+	switch policy {
+	case config.BackpressureBlock:
+		return nil
+	case config.BackpressureDropOldest, config.BackpressureDropNewest, config.BackpressureCoalesce:
+		if bufferSize <= 0 {
+			return fmt.Errorf("backpressure policy %s requires positive metrics buffer size", policy)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid backpressure policy: %s", policy)
+	}
+}
+
+// validateAggregations checks that the aggregations have patterns and known aggregate functions.
+func validateAggregations(aggregations []config.AggregationConfig) error {
+	for _, a := range aggregations {
+		if len(strings.TrimSpace(a.Pattern)) == 0 {
+			return fmt.Errorf("aggregation pattern is required")
+		}
+		for _, function := range a.Functions {
+			switch function {
+			case config.AggregateMin, config.AggregateMax, config.AggregateAvg, config.AggregateCount:
+			default:
+				return fmt.Errorf("aggregation %s: invalid aggregate function: %s", a.Pattern, function)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRelabel checks that the relabel rules have known actions, valid regexes and labels
+// and the settings their actions require.
+func validateRelabel(rules []config.RelabelConfig) error {
+	if _, err := service.NewRelabelRules(rules); err != nil {
+		return err
+	}
+
+	for _, r := range rules {
+		switch {
+		case r.Action == config.RelabelReplace && len(r.Replacement) == 0:
+			return fmt.Errorf("relabel %s %s: replacement is required", r.Action, r.Regex)
+		case r.Action == config.RelabelPrefix && len(r.Prefix) == 0:
+			return fmt.Errorf("relabel %s %s: prefix is required", r.Action, r.Regex)
+		case r.Action == config.RelabelAddLabels && len(r.Labels) == 0:
+			return fmt.Errorf("relabel %s %s: labels are required", r.Action, r.Regex)
+		}
+	}
+	return nil
+}
+
+// validateLoopbackAddress checks that the address listens on the loopback interface only,
+// so the metrics push endpoint isn't reachable from other hosts.
+func validateLoopbackAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid push address %s: %w", address, err)
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("invalid push address %s: host should be localhost or a loopback IP", address)
 }
@@ -0,0 +1,263 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
+	service "github.com/Stern-Ritter/metrics-and-alerting-service/internal/service/agent"
+)
+
+// replacedConnCloseDelay is the time the replaced destination connections are kept open,
+// so the workers can finish sending their current batches with them.
+const replacedConnCloseDelay = 30 * time.Second
+
+// scheduledTasks are the agent tasks run at the configured intervals: the metrics collectors
+// and sending metrics. The tasks are restarted on config reload to apply the new intervals.
+type scheduledTasks struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// startTasks schedules the enabled metrics collectors and sending metrics at the agent config intervals.
+func startTasks(ctx context.Context, agent *service.Agent) (*scheduledTasks, error) {
+	tasksCtx, cancel := context.WithCancel(ctx)
+	tasks := &scheduledTasks{cancel: cancel}
+
+	if err := agent.StartCollectors(tasksCtx, &tasks.wg); err != nil {
+		tasks.stop()
+		return nil, err
+	}
+	agent.StartSendMetrics(tasksCtx, &tasks.wg)
+
+	return tasks, nil
+}
+
+// stop stops the tasks and waits for the running ones to finish.
+func (t *scheduledTasks) stop() {
+	t.cancel()
+	t.wg.Wait()
+}
+
+// reloader applies the reloaded agent config to the running agent. The intervals, the collectors
+// enable flags, the gauge aggregations, the relabel rules, the rate limits, the backpressure policies,
+// the clients and the keys of the destinations are applied without losing the cached metrics, the other
+// settings require the agent restart.
+type reloader struct {
+	agent         *service.Agent
+	defaultConfig config.AgentConfig
+	tasks         *scheduledTasks
+	closers       map[string]func()
+	logger        *logger.AgentLogger
+}
+
+// run reloads the agent config on SIGHUP and, when the config watch interval is set,
+// on the config file changes until the context is done.
+func (r *reloader) run(ctx context.Context) {
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+
+	changeCh := make(chan struct{}, 1)
+	cfg := r.agent.Config
+	if cfg.ConfigWatchInterval > 0 && len(cfg.ConfigFile) > 0 {
+		go watchConfigFile(ctx, cfg.ConfigFile, time.Duration(cfg.ConfigWatchInterval)*time.Second, changeCh, r.logger)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hupCh:
+			r.reloadAndLog(ctx, "sighup")
+		case <-changeCh:
+			r.reloadAndLog(ctx, "config file change")
+		}
+	}
+}
+
+func (r *reloader) reloadAndLog(ctx context.Context, trigger string) {
+	if err := r.reload(ctx); err != nil {
+		r.logger.Error(err.Error(), zap.String("event", "reload config"), zap.String("trigger", trigger))
+		return
+	}
+	r.logger.Info("Agent config reloaded", zap.String("event", "reload config"), zap.String("trigger", trigger))
+}
+
+// destinationUpdate holds the new config and clients of the destination changed on config reload.
+type destinationUpdate struct {
+	destination *service.Destination
+	cfg         *config.DestinationConfig
+	clients     *destinationClients
+}
+
+// reload parses the agent config again and applies it to the running agent. The clients of the changed
+// destinations are created before applying the config, so the agent keeps the old config when the new one
+// is invalid. The settings that can't be changed without the agent restart keep their old values.
+func (r *reloader) reload(ctx context.Context) error {
+	newCfg, err := GetConfig(r.defaultConfig)
+	if err != nil {
+		return fmt.Errorf("get config: %w", err)
+	}
+	if err := r.agent.ValidateCollectors(&newCfg); err != nil {
+		return err
+	}
+	relabelRules, err := service.NewRelabelRules(newCfg.Relabel)
+	if err != nil {
+		return err
+	}
+
+	for _, setting := range keepRestartOnlySettings(&newCfg, r.agent.Config) {
+		r.logger.Warn("Config setting change requires agent restart", zap.String("event", "reload config"),
+			zap.String("setting", setting))
+	}
+
+	updates := make([]destinationUpdate, 0, len(r.agent.Destinations))
+	for i, destination := range r.agent.Destinations {
+		cfg := &newCfg.Destinations[i]
+		if *cfg == *destination.CurrentConfig() {
+			continue
+		}
+
+		clients, err := newDestinationClients(cfg, destination)
+		if err != nil {
+			for _, u := range updates {
+				u.clients.close()
+			}
+			return fmt.Errorf("destination %s: %w", cfg.Name, err)
+		}
+		updates = append(updates, destinationUpdate{destination: destination, cfg: cfg, clients: clients})
+	}
+
+	r.tasks.stop()
+	r.agent.SetRelabelRules(relabelRules)
+	if !reflect.DeepEqual(newCfg.Aggregations, r.agent.Config.Aggregations) {
+		r.agent.Cache.SetAggregations(newCfg.Aggregations)
+	}
+	r.agent.Config = &newCfg
+
+	for _, u := range updates {
+		oldCfg := u.destination.CurrentConfig()
+		u.destination.Update(u.cfg, u.clients.rsaPublicKey, u.clients.httpClient, u.clients.grpcClient)
+		if u.cfg.GRPC != oldCfg.GRPC {
+			u.destination.ReplaceSendMetricsWorkerPool(sendMetricsWorker(u.cfg, u.destination))
+		}
+		if u.cfg.RateLimit != oldCfg.RateLimit {
+			u.destination.ResizeSendMetricsWorkerPool(u.cfg.RateLimit)
+		}
+
+		if closeReplaced, exists := r.closers[u.cfg.Name]; exists {
+			time.AfterFunc(replacedConnCloseDelay, closeReplaced)
+		}
+		r.closers[u.cfg.Name] = u.clients.close
+	}
+
+	tasks, err := startTasks(ctx, r.agent)
+	if err != nil {
+		return fmt.Errorf("restart tasks: %w", err)
+	}
+	r.tasks = tasks
+
+	return nil
+}
+
+// keepRestartOnlySettings sets the settings of the new config that can't be changed without the agent restart
+// to their old values and returns the names of the changed ones. The destinations are kept in the old order
+// and can't be added or removed.
+func keepRestartOnlySettings(newCfg, oldCfg *config.AgentConfig) []string {
+	changed := make([]string, 0)
+
+	keepSetting(&changed, "metrics_buffer_size", &newCfg.MetricsBufferSize, oldCfg.MetricsBufferSize)
+	keepSetting(&changed, "logger_level", &newCfg.LoggerLvl, oldCfg.LoggerLvl)
+	keepSetting(&changed, "config_watch_interval", &newCfg.ConfigWatchInterval, oldCfg.ConfigWatchInterval)
+	keepSetting(&changed, "push_address", &newCfg.PushAddress, oldCfg.PushAddress)
+	keepSetting(&changed, "push_socket", &newCfg.PushSocket, oldCfg.PushSocket)
+	keepSetting(&changed, "agent_id", &newCfg.AgentID, oldCfg.AgentID)
+	keepSetting(&changed, "spool_dir", &newCfg.SpoolDir, oldCfg.SpoolDir)
+	keepSetting(&changed, "spool_max_size", &newCfg.SpoolMaxSize, oldCfg.SpoolMaxSize)
+	keepSetting(&changed, "spool_max_age", &newCfg.SpoolMaxAge, oldCfg.SpoolMaxAge)
+	keepSetting(&changed, "spool_segment_size", &newCfg.SpoolSegmentSize, oldCfg.SpoolSegmentSize)
+	keepSetting(&changed, "disk", &newCfg.Disk, oldCfg.Disk)
+	keepSetting(&changed, "network", &newCfg.Network, oldCfg.Network)
+	keepSetting(&changed, "processes", &newCfg.Processes, oldCfg.Processes)
+	keepSetting(&changed, "exec", &newCfg.Exec, oldCfg.Exec)
+	keepSetting(&changed, "probes", &newCfg.Probes, oldCfg.Probes)
+	keepSetting(&changed, "logs", &newCfg.Logs, oldCfg.Logs)
+
+	destinations := make([]config.DestinationConfig, 0, len(oldCfg.Destinations))
+	for _, oldDestination := range oldCfg.Destinations {
+		i := slices.IndexFunc(newCfg.Destinations, func(d config.DestinationConfig) bool {
+			return d.Name == oldDestination.Name
+		})
+		if i < 0 {
+			changed = append(changed, "destinations")
+			destinations = append(destinations, oldDestination)
+			continue
+		}
+
+		destination := newCfg.Destinations[i]
+		keepSetting(&changed, fmt.Sprintf("destinations.%s.spool_dir", destination.Name),
+			&destination.SpoolDir, oldDestination.SpoolDir)
+		destinations = append(destinations, destination)
+	}
+	if len(newCfg.Destinations) != len(oldCfg.Destinations) && !slices.Contains(changed, "destinations") {
+		changed = append(changed, "destinations")
+	}
+	newCfg.Destinations = destinations
+
+	return changed
+}
+
+// keepSetting sets the new value of the setting to the old one and adds the setting name
+// to the changed settings when the values differ.
+func keepSetting[T any](changed *[]string, name string, newValue *T, oldValue T) {
+	if !reflect.DeepEqual(*newValue, oldValue) {
+		*changed = append(*changed, name)
+		*newValue = oldValue
+	}
+}
+
+// watchConfigFile checks the config file at the interval and notifies about its changes
+// until the context is done.
+func watchConfigFile(ctx context.Context, path string, interval time.Duration, changeCh chan<- struct{},
+	logger *logger.AgentLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastInfo os.FileInfo
+	if info, err := os.Stat(path); err == nil {
+		lastInfo = info
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				logger.Error(err.Error(), zap.String("event", "watch config file"))
+				continue
+			}
+			if lastInfo != nil && info.ModTime().Equal(lastInfo.ModTime()) && info.Size() == lastInfo.Size() {
+				continue
+			}
+			lastInfo = info
+
+			select {
+			case changeCh <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/pkg/errors"
 	"github.com/pressly/goose/v3"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -159,6 +160,9 @@ func runGrpcServer(server *service.Server, signals chan os.Signal, idleConnsClos
 	opts = append(opts, grpc.ChainUnaryInterceptor(logger.LoggerInterceptor(server.Logger)))
 	opts = append(opts, grpc.ChainUnaryInterceptor(server.SubnetInterceptor))
 	opts = append(opts, grpc.ChainUnaryInterceptor(server.SignInterceptor))
+	opts = append(opts, grpc.ChainStreamInterceptor(logger.LoggerStreamInterceptor(server.Logger)))
+	opts = append(opts, grpc.ChainStreamInterceptor(server.SubnetStreamInterceptor))
+	opts = append(opts, grpc.ChainStreamInterceptor(server.SignStreamInterceptor))
 	isEncryptionEnabled := len(server.Config.TLSCertPath) > 0 && len(server.Config.TLSKeyPath) > 0
 	if isEncryptionEnabled {
 		creds, err := credentials.NewServerTLSFromFile(server.Config.TLSCertPath, server.Config.TLSKeyPath)
@@ -170,6 +174,7 @@ func runGrpcServer(server *service.Server, signals chan os.Signal, idleConnsClos
 
 	srv := grpc.NewServer(opts...)
 	pb.RegisterMetricsV1ServiceServer(srv, server)
+	colmetricspb.RegisterMetricsServiceServer(srv, server)
 
 	go func() {
 		<-signals
@@ -242,28 +247,56 @@ func addRoutes(s *service.Server) *chi.Mux {
 	r.Use(s.Logger.LoggerMiddleware)
 	r.Use(s.SubnetMiddleware)
 	r.Use(s.SignMiddleware)
-	r.Use(s.EncryptMiddleware)
-	r.Use(compress.GzipMiddleware)
 
-	r.Get("/", s.GetMetricsHandler)
+	r.Group(func(r chi.Router) {
+		r.Use(s.EncryptMiddleware)
+		r.Use(compress.GzipMiddleware)
 
-	r.Mount("/debug/pprof", http.DefaultServeMux)
+		r.Get("/", s.GetMetricsHandler)
 
-	r.Route("/update", func(r chi.Router) {
-		r.Post("/", s.UpdateMetricHandlerWithBody)
-		r.Post("/{type}/{name}/{value}", s.UpdateMetricHandlerWithPathVars)
-	})
+		r.Mount("/debug/pprof", http.DefaultServeMux)
+
+		r.Route("/update", func(r chi.Router) {
+			r.Post("/", s.UpdateMetricHandlerWithBody)
+			r.Post("/{type}/{name}/{value}", s.UpdateMetricHandlerWithPathVars)
+		})
+
+		r.Route("/updates", func(r chi.Router) {
+			r.Post("/", s.UpdateMetricsBatchHandlerWithBody)
+		})
+
+		r.Route("/value", func(r chi.Router) {
+			r.Post("/", s.GetMetricHandlerWithBody)
+			r.Get("/{type}/{name}", s.GetMetricHandlerWithPathVars)
+		})
+
+		r.Get("/ping", s.PingDatabaseHandler)
 
-	r.Route("/updates", func(r chi.Router) {
-		r.Post("/", s.UpdateMetricsBatchHandlerWithBody)
+		r.Get("/export", s.ExportMetricsHandler)
+
+		// OTLP/HTTP requests are sent by the agents only, so they must be signed as gRPC OTLP requests are.
+		if !s.Config.OTLPAllowUnauthenticated {
+			r.With(s.RequireSignMiddleware).Post("/v1/metrics", s.OTLPMetricsHandler)
+		}
 	})
 
-	r.Route("/value", func(r chi.Router) {
-		r.Post("/", s.GetMetricHandlerWithBody)
-		r.Get("/{type}/{name}", s.GetMetricHandlerWithPathVars)
+	// Imports are sent by external tools, which don't support the agent body encryption, and OTLP/HTTP requests
+	// are sent by OpenTelemetry SDKs, when they are allowed without authentication.
+	// Import bodies are also streamed rather than buffered.
+	r.Group(func(r chi.Router) {
+		r.Use(compress.GzipMiddleware)
+
+		if s.Config.OTLPAllowUnauthenticated {
+			r.Post("/v1/metrics", s.OTLPMetricsHandler)
+		}
+
+		r.Route("/import", func(r chi.Router) {
+			r.Post("/", s.ImportMetricsHandler)
+		})
 	})
 
-	r.Get("/ping", s.PingDatabaseHandler)
+	// Server-Sent Events are flushed to the client as they are written, so the stream isn't compressed.
+	r.Get("/watch", s.WatchMetricsHandler)
 
 	return r
 }
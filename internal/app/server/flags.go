@@ -14,19 +14,20 @@ import (
 )
 
 type jsonConfig struct {
-	URL             string `json:"address,omitempty"`
-	StoreInterval   int    `json:"store_interval,omitempty"`
-	FileStoragePath string `json:"store_file,omitempty"`
-	Restore         bool   `json:"restore,omitempty"`
-	DatabaseDSN     string `json:"database_dsn,omitempty"`
-	GRPC            bool   `json:"grpc,omitempty"`
-	SecretKey       string `json:"sign_key,omitempty"`
-	CryptoKeyPath   string `json:"crypto_key,omitempty"`
-	TLSCertPath     string `json:"tls_cert,omitempty"`
-	TLSKeyPath      string `json:"tls_key,omitempty"`
-	TrustedSubnet   string `json:"trusted_subnet,omitempty"`
-	ShutdownTimeout int    `json:"shutdown_timeout,omitempty"`
-	LoggerLvl       string `json:"logger_level,omitempty"`
+	URL                      string `json:"address,omitempty"`
+	StoreInterval            int    `json:"store_interval,omitempty"`
+	FileStoragePath          string `json:"store_file,omitempty"`
+	Restore                  bool   `json:"restore,omitempty"`
+	DatabaseDSN              string `json:"database_dsn,omitempty"`
+	GRPC                     bool   `json:"grpc,omitempty"`
+	SecretKey                string `json:"sign_key,omitempty"`
+	CryptoKeyPath            string `json:"crypto_key,omitempty"`
+	TLSCertPath              string `json:"tls_cert,omitempty"`
+	TLSKeyPath               string `json:"tls_key,omitempty"`
+	TrustedSubnet            string `json:"trusted_subnet,omitempty"`
+	OTLPAllowUnauthenticated bool   `json:"otlp_allow_unauthenticated,omitempty"`
+	ShutdownTimeout          int    `json:"shutdown_timeout,omitempty"`
+	LoggerLvl                string `json:"logger_level,omitempty"`
 }
 
 // GetConfig initializes the server config by parsing command-line flags, environment variables, and a JSON config file.
@@ -81,6 +82,8 @@ func parseFlags(cfg *config.ServerConfig) {
 	flag.StringVar(&cfg.TLSKeyPath, "tls-key", "", "path to tls key")
 	flag.StringVar(&cfg.TrustedSubnet, "t", "", "trusted subnet for agents")
 	flag.StringVar(&cfg.ConfigFile, "c", "", "path to json config file")
+	flag.BoolVar(&cfg.OTLPAllowUnauthenticated, "otlp-allow-unauthenticated", false,
+		"accept otlp export requests without sign and encryption")
 	flag.Parse()
 }
 
@@ -112,6 +115,7 @@ func mergeJSONConfig(cfg *config.ServerConfig, jsonCfg jsonConfig) {
 	cfg.TLSCertPath = utils.Coalesce(cfg.TLSCertPath, jsonCfg.TLSCertPath)
 	cfg.TLSKeyPath = utils.Coalesce(cfg.TLSKeyPath, jsonCfg.TLSKeyPath)
 	cfg.TrustedSubnet = utils.Coalesce(cfg.TrustedSubnet, jsonCfg.TrustedSubnet)
+	cfg.OTLPAllowUnauthenticated = utils.Coalesce(cfg.OTLPAllowUnauthenticated, jsonCfg.OTLPAllowUnauthenticated)
 	cfg.ShutdownTimeout = utils.Coalesce(cfg.ShutdownTimeout, jsonCfg.ShutdownTimeout)
 	cfg.LoggerLvl = utils.Coalesce(cfg.LoggerLvl, jsonCfg.LoggerLvl)
 }
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// Collector collects metrics statistics for the agent.
+type Collector interface {
+	// Name returns the unique collector name used in the agent config.
+	Name() string
+	// Describe returns the metrics the collector can update.
+	Describe() []metrics.Descriptor
+	// Collect collects metrics statistics. Gauge metrics hold current values,
+	// counter metrics hold increments since the previous collection.
+	Collect(ctx context.Context) ([]metrics.Metrics, error)
+}
+
+// Registry holds collectors in the order of their registration.
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+	names      map[string]struct{}
+}
+
+// NewRegistry is constructor for creating a new Registry with the provided collectors.
+func NewRegistry(collectors ...Collector) (*Registry, error) {
+	r := &Registry{names: make(map[string]struct{})}
+	for _, c := range collectors {
+		if err := r.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds the collector to the registry. It returns an error
+// if a collector with the same name is already registered.
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.names[c.Name()]; exists {
+		return fmt.Errorf("collector %s is already registered", c.Name())
+	}
+	r.names[c.Name()] = struct{}{}
+	r.collectors = append(r.collectors, c)
+
+	return nil
+}
+
+// Has reports whether a collector with the specified name is registered.
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.names[name]
+	return exists
+}
+
+// Collectors returns all registered collectors.
+func (r *Registry) Collectors() []Collector {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collectors := make([]Collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	return collectors
+}
+
+// counterDelta returns the increment of a cumulative counter. When the counter was reset,
+// the current value is the increment.
+func counterDelta(prev, cur uint64) int64 {
+	if cur < prev {
+		return int64(cur)
+	}
+	return int64(cur - prev)
+}
+
+// matchFilter reports whether the value is selected by the filter glob patterns.
+func matchFilter(filter config.FilterConfig, value string) bool {
+	matchAny := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if ok, err := path.Match(pattern, value); err == nil && ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(filter.Include) > 0 && !matchAny(filter.Include) {
+		return false
+	}
+	return !matchAny(filter.Exclude)
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/monitors"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
+)
+
+func TestRegistry(t *testing.T) {
+	random := utils.NewRandom()
+	runtimeCollector := NewRuntimeCollector(&monitors.RuntimeMonitor{}, &random)
+	utilCollector := NewUtilCollector(&monitors.UtilMonitor{})
+
+	t.Run("should register collectors in order", func(t *testing.T) {
+		registry, err := NewRegistry(runtimeCollector, utilCollector)
+		require.NoError(t, err)
+
+		assert.True(t, registry.Has(RuntimeCollectorName))
+		assert.True(t, registry.Has(UtilCollectorName))
+		assert.False(t, registry.Has("unknown"))
+		assert.Equal(t, []Collector{runtimeCollector, utilCollector}, registry.Collectors())
+	})
+
+	t.Run("should return error when collector name is already registered", func(t *testing.T) {
+		_, err := NewRegistry(runtimeCollector, runtimeCollector)
+		assert.Error(t, err)
+	})
+}
+
+func TestCollectorsCollectDeclaredMetrics(t *testing.T) {
+	random := utils.NewRandom()
+	collectors := []Collector{
+		NewRuntimeCollector(&monitors.RuntimeMonitor{}, &random),
+		NewUtilCollector(&monitors.UtilMonitor{}),
+	}
+
+	for _, c := range collectors {
+		t.Run(c.Name(), func(t *testing.T) {
+			// Context switches and CPU utilization are collected starting from the second poll.
+			_, err := c.Collect(context.Background())
+			require.NoError(t, err)
+			ms, err := c.Collect(context.Background())
+			require.NoError(t, err)
+			require.NotEmpty(t, ms)
+
+			descriptors := c.Describe()
+			for _, m := range ms {
+				declared := false
+				for _, d := range descriptors {
+					if d.Match(m.ID, metrics.MetricType(m.MType)) {
+						declared = true
+						break
+					}
+				}
+				assert.True(t, declared, "metric %s should be declared by the collector", m.ID)
+			}
+		})
+	}
+}
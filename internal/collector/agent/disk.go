@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// DiskCollectorName is the name of the disk and filesystem metrics collector.
+const DiskCollectorName = "disk"
+
+const (
+	diskTotalMetric       = "DiskTotal"
+	diskUsedMetric        = "DiskUsed"
+	diskFreeMetric        = "DiskFree"
+	diskInodesTotalMetric = "DiskInodesTotal"
+	diskInodesUsedMetric  = "DiskInodesUsed"
+	diskInodesFreeMetric  = "DiskInodesFree"
+	diskReadBytesMetric   = "DiskReadBytes"
+	diskWriteBytesMetric  = "DiskWriteBytes"
+	diskReadOpsMetric     = "DiskReadOps"
+	diskWriteOpsMetric    = "DiskWriteOps"
+)
+
+// DiskCollector collects filesystem usage for each selected mount point and I/O counters
+// for the devices of the selected mount points. Metric names hold the mountpoint or device label,
+// for example DiskUsed{mountpoint=/} or DiskReadBytes{device=sda1}. I/O counters hold
+// increments since the previous poll, so they are returned starting from the second poll.
+type DiskCollector struct {
+	config     config.DiskCollectorConfig
+	partitions func(ctx context.Context, all bool) ([]disk.PartitionStat, error)
+	usage      func(ctx context.Context, path string) (*disk.UsageStat, error)
+	ioCounters func(ctx context.Context, names ...string) (map[string]disk.IOCountersStat, error)
+
+	mu         sync.Mutex
+	prevCounts map[string]disk.IOCountersStat
+}
+
+// NewDiskCollector is constructor for creating a new DiskCollector.
+func NewDiskCollector(config config.DiskCollectorConfig) *DiskCollector {
+	return &DiskCollector{
+		config:     config,
+		partitions: disk.PartitionsWithContext,
+		usage:      disk.UsageWithContext,
+		ioCounters: disk.IOCountersWithContext,
+	}
+}
+
+// Name returns the collector name.
+func (c *DiskCollector) Name() string {
+	return DiskCollectorName
+}
+
+// Describe returns the metrics the collector can update.
+func (c *DiskCollector) Describe() []metrics.Descriptor {
+	return []metrics.Descriptor{
+		metrics.NewPrefixDescriptor(diskTotalMetric+"{", metrics.Gauge),
+		metrics.NewPrefixDescriptor(diskUsedMetric+"{", metrics.Gauge),
+		metrics.NewPrefixDescriptor(diskFreeMetric+"{", metrics.Gauge),
+		metrics.NewPrefixDescriptor(diskInodesTotalMetric+"{", metrics.Gauge),
+		metrics.NewPrefixDescriptor(diskInodesUsedMetric+"{", metrics.Gauge),
+		metrics.NewPrefixDescriptor(diskInodesFreeMetric+"{", metrics.Gauge),
+		metrics.NewPrefixDescriptor(diskReadBytesMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(diskWriteBytesMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(diskReadOpsMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(diskWriteOpsMetric+"{", metrics.Counter),
+	}
+}
+
+// Collect reads filesystem usage and device I/O statistics. Mount points that can't be read
+// are skipped, the last error is returned with the collected metrics. Mount points and devices
+// with names that can't be used as label values are skipped.
+func (c *DiskCollector) Collect(ctx context.Context) ([]metrics.Metrics, error) {
+	partitions, err := c.partitions(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	result := make([]metrics.Metrics, 0)
+	devices := make([]string, 0, len(partitions))
+	seenDevices := make(map[string]struct{}, len(partitions))
+	for _, p := range partitions {
+		if !matchFilter(c.config.FSTypes, p.Fstype) || !matchFilter(c.config.MountPoints, p.Mountpoint) {
+			continue
+		}
+		if !metrics.IsValidLabelValue(p.Mountpoint) {
+			continue
+		}
+
+		usage, err := c.usage(ctx, p.Mountpoint)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		labels := map[string]string{"mountpoint": p.Mountpoint}
+		result = append(result,
+			metrics.NewGaugeMetrics(metrics.SeriesName(diskTotalMetric, labels), float64(usage.Total)),
+			metrics.NewGaugeMetrics(metrics.SeriesName(diskUsedMetric, labels), float64(usage.Used)),
+			metrics.NewGaugeMetrics(metrics.SeriesName(diskFreeMetric, labels), float64(usage.Free)),
+			metrics.NewGaugeMetrics(metrics.SeriesName(diskInodesTotalMetric, labels), float64(usage.InodesTotal)),
+			metrics.NewGaugeMetrics(metrics.SeriesName(diskInodesUsedMetric, labels), float64(usage.InodesUsed)),
+			metrics.NewGaugeMetrics(metrics.SeriesName(diskInodesFreeMetric, labels), float64(usage.InodesFree)),
+		)
+
+		device := filepath.Base(p.Device)
+		if _, seen := seenDevices[device]; !seen && metrics.IsValidLabelValue(device) {
+			seenDevices[device] = struct{}{}
+			devices = append(devices, device)
+		}
+	}
+
+	if len(devices) == 0 {
+		return result, lastErr
+	}
+
+	counters, err := c.ioCounters(ctx, devices...)
+	if err != nil {
+		return result, err
+	}
+	result = append(result, c.ioCountersDeltas(counters)...)
+
+	return result, lastErr
+}
+
+func (c *DiskCollector) ioCountersDeltas(counters map[string]disk.IOCountersStat) []metrics.Metrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make([]metrics.Metrics, 0, len(counters)*4)
+	for device, cur := range counters {
+		prev, ok := c.prevCounts[device]
+		if !ok {
+			continue
+		}
+
+		labels := map[string]string{"device": device}
+		result = append(result,
+			metrics.NewCounterMetrics(metrics.SeriesName(diskReadBytesMetric, labels), counterDelta(prev.ReadBytes, cur.ReadBytes)),
+			metrics.NewCounterMetrics(metrics.SeriesName(diskWriteBytesMetric, labels), counterDelta(prev.WriteBytes, cur.WriteBytes)),
+			metrics.NewCounterMetrics(metrics.SeriesName(diskReadOpsMetric, labels), counterDelta(prev.ReadCount, cur.ReadCount)),
+			metrics.NewCounterMetrics(metrics.SeriesName(diskWriteOpsMetric, labels), counterDelta(prev.WriteCount, cur.WriteCount)),
+		)
+	}
+	c.prevCounts = counters
+
+	return result
+}
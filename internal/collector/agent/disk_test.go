@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func newTestDiskCollector(cfg config.DiskCollectorConfig, counters []map[string]disk.IOCountersStat) *DiskCollector {
+	c := NewDiskCollector(cfg)
+	c.partitions = func(_ context.Context, _ bool) ([]disk.PartitionStat, error) {
+		return []disk.PartitionStat{
+			{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+			{Device: "/dev/sda2", Mountpoint: "/home", Fstype: "ext4"},
+			{Device: "/dev/loop0", Mountpoint: "/snap/core", Fstype: "squashfs"},
+			{Device: "/dev/sdb1", Mountpoint: "/broken", Fstype: "xfs"},
+			{Device: "/dev/sdc1", Mountpoint: "/media/My Disk", Fstype: "ext4"},
+		}, nil
+	}
+	c.usage = func(_ context.Context, path string) (*disk.UsageStat, error) {
+		if path == "/broken" {
+			return nil, errors.New("usage error")
+		}
+		return &disk.UsageStat{Path: path, Total: 100, Used: 40, Free: 60, InodesTotal: 10, InodesUsed: 3, InodesFree: 7}, nil
+	}
+	poll := 0
+	c.ioCounters = func(_ context.Context, names ...string) (map[string]disk.IOCountersStat, error) {
+		result := make(map[string]disk.IOCountersStat)
+		for _, name := range names {
+			if stat, ok := counters[poll][name]; ok {
+				result[name] = stat
+			}
+		}
+		poll++
+		return result, nil
+	}
+	return c
+}
+
+func metricsByName(ms []metrics.Metrics) map[string]metrics.Metrics {
+	result := make(map[string]metrics.Metrics, len(ms))
+	for _, m := range ms {
+		result[m.ID] = m
+	}
+	return result
+}
+
+func TestDiskCollectorCollect(t *testing.T) {
+	counters := []map[string]disk.IOCountersStat{
+		{"sda1": {ReadBytes: 1000, WriteBytes: 2000, ReadCount: 10, WriteCount: 20}},
+		{"sda1": {ReadBytes: 1500, WriteBytes: 2100, ReadCount: 15, WriteCount: 21}},
+	}
+	cfg := config.DiskCollectorConfig{
+		FSTypes:     config.FilterConfig{Exclude: []string{"squashfs"}},
+		MountPoints: config.FilterConfig{Exclude: []string{"/home"}},
+	}
+	c := newTestDiskCollector(cfg, counters)
+
+	ms, err := c.Collect(context.Background())
+	assert.Error(t, err, "should return error of unreadable mount point")
+	byName := metricsByName(ms)
+	assert.Len(t, byName, 6, "should collect usage of selected mount points with valid label values only and skip counters on the first poll")
+	assert.Equal(t, float64(40), *byName["DiskUsed{mountpoint=/}"].Value)
+	assert.Equal(t, float64(7), *byName["DiskInodesFree{mountpoint=/}"].Value)
+
+	ms, _ = c.Collect(context.Background())
+	byName = metricsByName(ms)
+	require.Contains(t, byName, "DiskReadBytes{device=sda1}")
+	assert.Equal(t, int64(500), *byName["DiskReadBytes{device=sda1}"].Delta)
+	assert.Equal(t, int64(100), *byName["DiskWriteBytes{device=sda1}"].Delta)
+	assert.Equal(t, int64(5), *byName["DiskReadOps{device=sda1}"].Delta)
+	assert.Equal(t, int64(1), *byName["DiskWriteOps{device=sda1}"].Delta)
+
+	descriptors := c.Describe()
+	for _, m := range ms {
+		declared := false
+		for _, d := range descriptors {
+			declared = declared || d.Match(m.ID, metrics.MetricType(m.MType))
+		}
+		assert.True(t, declared, "metric %s should be declared by the collector", m.ID)
+	}
+}
+
+func TestMatchFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   config.FilterConfig
+		value    string
+		expected bool
+	}{
+		{name: "empty filter", filter: config.FilterConfig{}, value: "ext4", expected: true},
+		{name: "included value", filter: config.FilterConfig{Include: []string{"ext*", "xfs"}}, value: "ext4", expected: true},
+		{name: "not included value", filter: config.FilterConfig{Include: []string{"xfs"}}, value: "ext4", expected: false},
+		{name: "excluded value", filter: config.FilterConfig{Exclude: []string{"/snap/*"}}, value: "/snap/core", expected: false},
+		{name: "included and excluded value", filter: config.FilterConfig{Include: []string{"*"}, Exclude: []string{"tmpfs"}},
+			value: "tmpfs", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchFilter(tt.filter, tt.value))
+		})
+	}
+}
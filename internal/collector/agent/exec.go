@@ -0,0 +1,277 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// ExecCollectorName is the name of the exec metrics collector.
+const ExecCollectorName = "exec"
+
+const (
+	execExitCodeMetric = "ExecExitCode"
+	execDurationMetric = "ExecDuration"
+
+	defaultExecTimeout = 10 * time.Second
+	execMaxOutputSize  = 1024 * 1024
+	execWaitDelay      = time.Second
+)
+
+// execCommand is a command run by the exec collector.
+type execCommand struct {
+	name    string
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+// ExecCollector runs the configured commands concurrently and parses metrics from their stdout.
+// The output is a JSON array of metrics in the format of the server's /updates endpoint, or lines
+// in format: name type value. Empty lines and lines starting with # are skipped. Metric names
+// are prefixed with the command name, for example the backup command reporting AgeSeconds
+// updates the backup_AgeSeconds metric. The exit code and the duration in seconds of each command
+// are reported as ExecExitCode{command=name} and ExecDuration{command=name}. The exit code is -1
+// when the command couldn't be started or was killed on timeout.
+type ExecCollector struct {
+	commands []execCommand
+}
+
+// NewExecCollector is constructor for creating a new ExecCollector. It returns an error
+// if a command config has no name or executable, or the command name is used more than once.
+func NewExecCollector(cfgs []config.ExecConfig) (*ExecCollector, error) {
+	commands := make([]execCommand, 0, len(cfgs))
+	names := make(map[string]struct{}, len(cfgs))
+	for _, cfg := range cfgs {
+		name := strings.TrimSpace(cfg.Name)
+		if !metrics.IsValidLabelValue(name) {
+			return nil, fmt.Errorf("invalid exec command name: %q", cfg.Name)
+		}
+		if len(strings.TrimSpace(cfg.Command)) == 0 {
+			return nil, fmt.Errorf("exec command %s has no executable", name)
+		}
+		if _, exists := names[name]; exists {
+			return nil, fmt.Errorf("exec command %s is configured more than once", name)
+		}
+		names[name] = struct{}{}
+
+		timeout := defaultExecTimeout
+		if cfg.Timeout > 0 {
+			timeout = time.Duration(cfg.Timeout) * time.Second
+		}
+		commands = append(commands, execCommand{name: name, command: cfg.Command, args: cfg.Args, timeout: timeout})
+	}
+
+	return &ExecCollector{commands: commands}, nil
+}
+
+// Name returns the collector name.
+func (c *ExecCollector) Name() string {
+	return ExecCollectorName
+}
+
+// Describe returns the metrics the collector can update.
+func (c *ExecCollector) Describe() []metrics.Descriptor {
+	descriptors := make([]metrics.Descriptor, 0, len(c.commands)*4)
+	for _, cmd := range c.commands {
+		labels := map[string]string{"command": cmd.name}
+		descriptors = append(descriptors,
+			metrics.NewGaugeDescriptor(metrics.SeriesName(execExitCodeMetric, labels)),
+			metrics.NewGaugeDescriptor(metrics.SeriesName(execDurationMetric, labels)),
+			metrics.NewPrefixDescriptor(cmd.metricsPrefix(), metrics.Gauge),
+			metrics.NewPrefixDescriptor(cmd.metricsPrefix(), metrics.Counter),
+		)
+	}
+	return descriptors
+}
+
+// Collect runs the commands and returns the parsed metrics. Errors of failed commands and
+// unparsable output lines are joined and returned with the collected metrics.
+func (c *ExecCollector) Collect(ctx context.Context) ([]metrics.Metrics, error) {
+	type execResult struct {
+		metrics []metrics.Metrics
+		err     error
+	}
+
+	results := make([]execResult, len(c.commands))
+	wg := sync.WaitGroup{}
+	for i, cmd := range c.commands {
+		wg.Add(1)
+		go func(i int, cmd execCommand) {
+			defer wg.Done()
+			ms, err := cmd.run(ctx)
+			results[i] = execResult{metrics: ms, err: err}
+		}(i, cmd)
+	}
+	wg.Wait()
+
+	var errs []error
+	result := make([]metrics.Metrics, 0)
+	for _, r := range results {
+		result = append(result, r.metrics...)
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func (cmd execCommand) metricsPrefix() string {
+	return cmd.name + "_"
+}
+
+func (cmd execCommand) run(ctx context.Context) ([]metrics.Metrics, error) {
+	ctx, cancel := context.WithTimeout(ctx, cmd.timeout)
+	defer cancel()
+
+	stdout := &cappedBuffer{limit: execMaxOutputSize}
+	c := exec.CommandContext(ctx, cmd.command, cmd.args...)
+	c.Stdout = stdout
+	c.WaitDelay = execWaitDelay
+
+	start := time.Now()
+	runErr := c.Run()
+	duration := time.Since(start)
+
+	exitCode := -1
+	if c.ProcessState != nil {
+		exitCode = c.ProcessState.ExitCode()
+	}
+
+	labels := map[string]string{"command": cmd.name}
+	result := []metrics.Metrics{
+		metrics.NewGaugeMetrics(metrics.SeriesName(execExitCodeMetric, labels), float64(exitCode)),
+		metrics.NewGaugeMetrics(metrics.SeriesName(execDurationMetric, labels), duration.Seconds()),
+	}
+
+	var errs []error
+	if runErr != nil {
+		errs = append(errs, fmt.Errorf("exec command %s: %w", cmd.name, runErr))
+	}
+	if stdout.truncated {
+		errs = append(errs, fmt.Errorf("exec command %s: output exceeds %d bytes", cmd.name, execMaxOutputSize))
+	}
+
+	ms, err := parseExecOutput(stdout.Bytes())
+	if err != nil {
+		errs = append(errs, fmt.Errorf("exec command %s: %w", cmd.name, err))
+	}
+	for _, m := range ms {
+		m.ID = cmd.metricsPrefix() + m.ID
+		result = append(result, m)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// parseExecOutput parses metrics from a JSON array or from lines in format: name type value.
+// Valid metrics are returned with the errors of invalid ones.
+func parseExecOutput(output []byte) ([]metrics.Metrics, error) {
+	trimmed := bytes.TrimSpace(output)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	if trimmed[0] == '[' {
+		ms := make([]metrics.Metrics, 0)
+		if err := json.Unmarshal(trimmed, &ms); err != nil {
+			return nil, err
+		}
+
+		var errs []error
+		valid := make([]metrics.Metrics, 0, len(ms))
+		for _, m := range ms {
+			if err := validateExecMetrics(m); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			valid = append(valid, m)
+		}
+		return valid, errors.Join(errs...)
+	}
+
+	var errs []error
+	result := make([]metrics.Metrics, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			errs = append(errs, fmt.Errorf("line %d: expected name type value, got %q", number, line))
+			continue
+		}
+		m, err := metrics.NewMetricsWithStringValue(fields[0], fields[1], fields[2])
+		if err == nil {
+			err = validateExecMetrics(m)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", number, err))
+			continue
+		}
+		result = append(result, m)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func validateExecMetrics(m metrics.Metrics) error {
+	if len(strings.TrimSpace(m.ID)) == 0 {
+		return errors.New("metric name is empty")
+	}
+
+	switch metrics.MetricType(m.MType) {
+	case metrics.Gauge:
+		if m.Value == nil {
+			return fmt.Errorf("metric %s: value is missing", m.ID)
+		}
+	case metrics.Counter:
+		if m.Delta == nil {
+			return fmt.Errorf("metric %s: delta is missing", m.ID)
+		}
+		if *m.Delta < 0 {
+			return fmt.Errorf("metric %s: delta can't be negative: %d", m.ID, *m.Delta)
+		}
+	default:
+		return fmt.Errorf("metric %s: invalid metric type: %s", m.ID, m.MType)
+	}
+
+	return nil
+}
+
+// cappedBuffer is a bytes.Buffer that discards writes beyond the limit, so a command
+// with a large output isn't blocked on writing to stdout.
+type cappedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write writes the data to the buffer up to the limit.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if free := b.limit - b.Len(); len(p) > free {
+		b.truncated = true
+		b.Buffer.Write(p[:max(free, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
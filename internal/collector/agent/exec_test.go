@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func TestNewExecCollector(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfgs    []config.ExecConfig
+		wantErr bool
+	}{
+		{name: "valid commands", cfgs: []config.ExecConfig{
+			{Name: "backup", Command: "/bin/backup"},
+			{Name: "queue", Command: "/bin/queue", Args: []string{"--json"}, Timeout: 5},
+		}},
+		{name: "empty name", cfgs: []config.ExecConfig{{Command: "/bin/backup"}}, wantErr: true},
+		{name: "name with labels syntax", cfgs: []config.ExecConfig{{Name: "a{b}", Command: "/bin/backup"}}, wantErr: true},
+		{name: "empty command", cfgs: []config.ExecConfig{{Name: "backup"}}, wantErr: true},
+		{name: "duplicate name", cfgs: []config.ExecConfig{
+			{Name: "backup", Command: "/bin/backup"},
+			{Name: "backup", Command: "/bin/backup2"},
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewExecCollector(tt.cfgs)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExecCollectorCollect(t *testing.T) {
+	c, err := NewExecCollector([]config.ExecConfig{
+		{Name: "lines", Command: "sh", Args: []string{"-c", "echo '# comment'; echo 'Age gauge 12.5'; echo 'Runs counter 3'"}},
+		{Name: "json", Command: "sh", Args: []string{"-c", `echo '[{"id":"Size","type":"gauge","value":7}]'`}},
+		{Name: "failed", Command: "sh", Args: []string{"-c", "echo 'Partial gauge 1'; echo 'bad line'; exit 3"}},
+		{Name: "slow", Command: "sleep", Args: []string{"5"}, Timeout: 1},
+		{Name: "missing", Command: "/nonexistent/command"},
+	})
+	require.NoError(t, err)
+
+	ms, err := c.Collect(context.Background())
+	assert.Error(t, err, "should return errors of failed commands")
+	byName := metricsByName(ms)
+
+	assert.Equal(t, 12.5, *byName["lines_Age"].Value)
+	assert.Equal(t, int64(3), *byName["lines_Runs"].Delta)
+	assert.Equal(t, float64(0), *byName["ExecExitCode{command=lines}"].Value)
+	assert.Contains(t, byName, "ExecDuration{command=lines}")
+	assert.Equal(t, float64(7), *byName["json_Size"].Value)
+	assert.Equal(t, float64(1), *byName["failed_Partial"].Value, "should parse valid lines of a failed command")
+	assert.Equal(t, float64(3), *byName["ExecExitCode{command=failed}"].Value)
+	assert.Equal(t, float64(-1), *byName["ExecExitCode{command=slow}"].Value, "should kill command on timeout")
+	assert.Less(t, *byName["ExecDuration{command=slow}"].Value, 4.0)
+	assert.Equal(t, float64(-1), *byName["ExecExitCode{command=missing}"].Value)
+
+	descriptors := c.Describe()
+	for _, m := range ms {
+		declared := false
+		for _, d := range descriptors {
+			declared = declared || d.Match(m.ID, metrics.MetricType(m.MType))
+		}
+		assert.True(t, declared, "metric %s should be declared by the collector", m.ID)
+	}
+}
+
+func TestParseExecOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "empty output", output: "  \n", wantCount: 0},
+		{name: "lines", output: "A gauge 1\n\nB counter 2\n", wantCount: 2},
+		{name: "invalid line value", output: "A gauge x\nB counter 2\n", wantCount: 1, wantErr: true},
+		{name: "invalid line type", output: "A histogram 1\n", wantCount: 0, wantErr: true},
+		{name: "negative line delta", output: "A gauge -1\nB counter -2\n", wantCount: 1, wantErr: true},
+		{name: "json", output: `[{"id":"A","type":"gauge","value":1},{"id":"B","type":"counter","delta":2}]`, wantCount: 2},
+		{name: "json without value", output: `[{"id":"A","type":"gauge"},{"id":"B","type":"counter","delta":2}]`,
+			wantCount: 1, wantErr: true},
+		{name: "json with negative delta", output: `[{"id":"A","type":"gauge","value":1},{"id":"B","type":"counter","delta":-2}]`,
+			wantCount: 1, wantErr: true},
+		{name: "invalid json", output: `[{"id":`, wantCount: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms, err := parseExecOutput([]byte(tt.output))
+			assert.Len(t, ms, tt.wantCount)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
+)
+
+// LogCollectorName is the name of the log tail metrics collector.
+const LogCollectorName = "log"
+
+const (
+	logMatchesMetric     = "LogMatches"
+	logValueCountMetric  = "LogValueCount"
+	logValueSumMetric    = "LogValueSum"
+	logValueMinMetric    = "LogValueMin"
+	logValueMaxMetric    = "LogValueMax"
+	logValueAvgMetric    = "LogValueAvg"
+	logValueBucketMetric = "LogValueBucket"
+
+	logValueGroup     = "value"
+	logMaxReadSize    = 16 * 1024 * 1024
+	logMaxPartialLine = 1024 * 1024
+	logReadBufferSize = 64 * 1024
+	logInfBucketLabel = "+Inf"
+)
+
+// logRule matches log lines and aggregates the captured values.
+type logRule struct {
+	name       string
+	regex      *regexp.Regexp
+	valueIndex int // valueIndex is the index of the value group, -1 when the regex doesn't capture values
+	buckets    []float64
+}
+
+// logRuleStats holds the rule statistics for the lines read at one poll.
+type logRuleStats struct {
+	matches      int64
+	values       int64
+	sum          float64
+	min          float64
+	max          float64
+	bucketCounts []int64
+}
+
+// logTail reads new lines of a log file between polls.
+type logTail struct {
+	name    string
+	path    string
+	rules   []logRule
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	partial []byte
+	started bool
+}
+
+// LogCollector tails log files and applies the configured rules to new lines. The first poll
+// starts at the end of an existing file, so the lines written before the agent start aren't counted.
+// A rotated file is read to the end before the new file is read from the beginning, a truncated
+// file is read from the beginning. Truncation is detected when the file is smaller than the read offset.
+//
+// For each rule the collector reports the counter LogMatches{log=name,rule=name} of matched lines.
+// When the rule regex has the "value" named group, the captured numbers are reported as
+// the counter LogValueCount, the gauges LogValueSum, LogValueMin, LogValueMax and LogValueAvg
+// of the values read since the previous poll, and the counters LogValueBucket{le=bound} of values
+// less than or equal to each bucket bound. The gauges are reported only when values were read,
+// otherwise the agent removes them from the cache until the next values are read.
+type LogCollector struct {
+	mu    sync.Mutex
+	tails []*logTail
+}
+
+// NewLogCollector is constructor for creating a new LogCollector. It returns an error
+// if a log or rule config has no name, the log has no path or a rule regex is invalid.
+func NewLogCollector(cfgs []config.LogConfig) (*LogCollector, error) {
+	tails := make([]*logTail, 0, len(cfgs))
+	names := make(map[string]struct{}, len(cfgs))
+	for _, cfg := range cfgs {
+		name := strings.TrimSpace(cfg.Name)
+		if !metrics.IsValidLabelValue(name) {
+			return nil, fmt.Errorf("invalid log name: %q", cfg.Name)
+		}
+		if len(strings.TrimSpace(cfg.Path)) == 0 {
+			return nil, fmt.Errorf("log %s has no path", name)
+		}
+		if _, exists := names[name]; exists {
+			return nil, fmt.Errorf("log %s is configured more than once", name)
+		}
+		names[name] = struct{}{}
+
+		rules, err := newLogRules(name, cfg.Rules)
+		if err != nil {
+			return nil, err
+		}
+		tails = append(tails, &logTail{name: name, path: cfg.Path, rules: rules})
+	}
+
+	return &LogCollector{tails: tails}, nil
+}
+
+func newLogRules(logName string, cfgs []config.LogRuleConfig) ([]logRule, error) {
+	if len(cfgs) == 0 {
+		return nil, fmt.Errorf("log %s has no rules", logName)
+	}
+
+	rules := make([]logRule, 0, len(cfgs))
+	names := make(map[string]struct{}, len(cfgs))
+	for _, cfg := range cfgs {
+		name := strings.TrimSpace(cfg.Name)
+		if !metrics.IsValidLabelValue(name) {
+			return nil, fmt.Errorf("log %s: invalid rule name: %q", logName, cfg.Name)
+		}
+		if _, exists := names[name]; exists {
+			return nil, fmt.Errorf("log %s: rule %s is configured more than once", logName, name)
+		}
+		names[name] = struct{}{}
+
+		regex, err := regexp.Compile(cfg.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("log %s: rule %s: %w", logName, name, err)
+		}
+
+		buckets := append([]float64(nil), cfg.Buckets...)
+		sort.Float64s(buckets)
+		rules = append(rules, logRule{
+			name:       name,
+			regex:      regex,
+			valueIndex: regex.SubexpIndex(logValueGroup),
+			buckets:    buckets,
+		})
+	}
+
+	return rules, nil
+}
+
+// Name returns the collector name.
+func (c *LogCollector) Name() string {
+	return LogCollectorName
+}
+
+// Describe returns the metrics the collector can update.
+func (c *LogCollector) Describe() []metrics.Descriptor {
+	descriptors := make([]metrics.Descriptor, 0)
+	for _, tail := range c.tails {
+		for _, rule := range tail.rules {
+			labels := map[string]string{"log": tail.name, "rule": rule.name}
+			descriptors = append(descriptors, metrics.NewCounterDescriptor(metrics.SeriesName(logMatchesMetric, labels)))
+			if rule.valueIndex < 0 {
+				continue
+			}
+
+			// The value gauges are declared as prefix families matching one series, so the agent removes them
+			// from the cache when the poll reads no values and their stale values aren't sent again.
+			descriptors = append(descriptors,
+				metrics.NewCounterDescriptor(metrics.SeriesName(logValueCountMetric, labels)),
+				metrics.NewPrefixDescriptor(metrics.SeriesName(logValueSumMetric, labels), metrics.Gauge),
+				metrics.NewPrefixDescriptor(metrics.SeriesName(logValueMinMetric, labels), metrics.Gauge),
+				metrics.NewPrefixDescriptor(metrics.SeriesName(logValueMaxMetric, labels), metrics.Gauge),
+				metrics.NewPrefixDescriptor(metrics.SeriesName(logValueAvgMetric, labels), metrics.Gauge),
+			)
+			for _, le := range rule.bucketLabels() {
+				descriptors = append(descriptors,
+					metrics.NewCounterDescriptor(metrics.SeriesName(logValueBucketMetric, rule.bucketSeriesLabels(labels, le))))
+			}
+		}
+	}
+	return descriptors
+}
+
+// Collect reads new lines of the log files. Files that can't be read are skipped,
+// their errors are joined and returned with the collected metrics.
+func (c *LogCollector) Collect(_ context.Context) ([]metrics.Metrics, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	result := make([]metrics.Metrics, 0)
+	for _, tail := range c.tails {
+		stats := make([]logRuleStats, len(tail.rules))
+		for i, rule := range tail.rules {
+			stats[i].bucketCounts = make([]int64, len(rule.buckets)+1)
+		}
+
+		err := tail.read(func(line []byte) {
+			for i, rule := range tail.rules {
+				stats[i].apply(rule, line)
+			}
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("log %s: %w", tail.name, err))
+		}
+
+		for i, rule := range tail.rules {
+			result = append(result, stats[i].metrics(tail.name, rule)...)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func (s *logRuleStats) apply(rule logRule, line []byte) {
+	if rule.valueIndex < 0 {
+		if rule.regex.Match(line) {
+			s.matches++
+		}
+		return
+	}
+
+	match := rule.regex.FindSubmatch(line)
+	if match == nil {
+		return
+	}
+	s.matches++
+
+	value, err := strconv.ParseFloat(string(match[rule.valueIndex]), 64)
+	if err != nil {
+		return
+	}
+	if s.values == 0 || value < s.min {
+		s.min = value
+	}
+	if s.values == 0 || value > s.max {
+		s.max = value
+	}
+	s.values++
+	s.sum += value
+	for i, bound := range rule.buckets {
+		if value <= bound {
+			s.bucketCounts[i]++
+		}
+	}
+	s.bucketCounts[len(rule.buckets)]++
+}
+
+func (s *logRuleStats) metrics(logName string, rule logRule) []metrics.Metrics {
+	labels := map[string]string{"log": logName, "rule": rule.name}
+	result := []metrics.Metrics{metrics.NewCounterMetrics(metrics.SeriesName(logMatchesMetric, labels), s.matches)}
+	if rule.valueIndex < 0 {
+		return result
+	}
+
+	result = append(result, metrics.NewCounterMetrics(metrics.SeriesName(logValueCountMetric, labels), s.values))
+	for i, le := range rule.bucketLabels() {
+		name := metrics.SeriesName(logValueBucketMetric, rule.bucketSeriesLabels(labels, le))
+		result = append(result, metrics.NewCounterMetrics(name, s.bucketCounts[i]))
+	}
+	if s.values > 0 {
+		result = append(result,
+			metrics.NewGaugeMetrics(metrics.SeriesName(logValueSumMetric, labels), s.sum),
+			metrics.NewGaugeMetrics(metrics.SeriesName(logValueMinMetric, labels), s.min),
+			metrics.NewGaugeMetrics(metrics.SeriesName(logValueMaxMetric, labels), s.max),
+			metrics.NewGaugeMetrics(metrics.SeriesName(logValueAvgMetric, labels), s.sum/float64(s.values)),
+		)
+	}
+	return result
+}
+
+func (r logRule) bucketLabels() []string {
+	if len(r.buckets) == 0 {
+		return nil
+	}
+
+	labels := make([]string, 0, len(r.buckets)+1)
+	for _, bound := range r.buckets {
+		labels = append(labels, utils.FormatGaugeMetricValue(bound))
+	}
+	return append(labels, logInfBucketLabel)
+}
+
+func (r logRule) bucketSeriesLabels(labels map[string]string, le string) map[string]string {
+	bucketLabels := utils.CopyMap(labels)
+	bucketLabels["le"] = le
+	return bucketLabels
+}
+
+// read passes the complete lines written since the previous read to the handler.
+func (t *logTail) read(handle func(line []byte)) error {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			t.started = true
+			return t.drain(handle)
+		}
+		return err
+	}
+
+	if t.file != nil && !os.SameFile(t.info, info) {
+		if err := t.drain(handle); err != nil {
+			return err
+		}
+	}
+
+	if t.file == nil {
+		file, err := os.Open(t.path)
+		if err != nil {
+			return err
+		}
+		t.file, t.info, t.partial = file, info, nil
+		t.offset = 0
+		if !t.started {
+			t.offset = info.Size()
+		}
+	}
+	t.started = true
+
+	if info.Size() < t.offset {
+		t.offset, t.partial = 0, nil
+	}
+
+	return t.readLines(handle)
+}
+
+// drain reads the rest of the open file, that was rotated or removed, and closes it.
+func (t *logTail) drain(handle func(line []byte)) error {
+	if t.file == nil {
+		return nil
+	}
+
+	err := t.readLines(handle)
+	if len(t.partial) > 0 {
+		handle(t.partial)
+	}
+	t.file.Close()
+	t.file, t.info, t.partial = nil, nil, nil
+	return err
+}
+
+func (t *logTail) readLines(handle func(line []byte)) error {
+	reader := io.NewSectionReader(t.file, t.offset, logMaxReadSize)
+	buf := make([]byte, logReadBufferSize)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			t.offset += int64(n)
+			t.partial = append(t.partial, buf[:n]...)
+			for {
+				i := bytes.IndexByte(t.partial, '\n')
+				if i < 0 {
+					break
+				}
+				handle(bytes.TrimRight(t.partial[:i], "\r"))
+				t.partial = t.partial[i+1:]
+			}
+			if len(t.partial) > logMaxPartialLine {
+				handle(t.partial)
+				t.partial = nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			t.partial = append([]byte(nil), t.partial...)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func appendLog(t *testing.T, path, data string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(data)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+func TestNewLogCollector(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfgs    []config.LogConfig
+		wantErr bool
+	}{
+		{name: "valid log", cfgs: []config.LogConfig{
+			{Name: "app", Path: "/var/log/app.log", Rules: []config.LogRuleConfig{{Name: "errors", Regex: "ERROR"}}},
+		}},
+		{name: "empty name", cfgs: []config.LogConfig{
+			{Path: "/var/log/app.log", Rules: []config.LogRuleConfig{{Name: "errors", Regex: "ERROR"}}},
+		}, wantErr: true},
+		{name: "empty path", cfgs: []config.LogConfig{
+			{Name: "app", Rules: []config.LogRuleConfig{{Name: "errors", Regex: "ERROR"}}},
+		}, wantErr: true},
+		{name: "no rules", cfgs: []config.LogConfig{{Name: "app", Path: "/var/log/app.log"}}, wantErr: true},
+		{name: "invalid regex", cfgs: []config.LogConfig{
+			{Name: "app", Path: "/var/log/app.log", Rules: []config.LogRuleConfig{{Name: "errors", Regex: "("}}},
+		}, wantErr: true},
+		{name: "duplicate rule", cfgs: []config.LogConfig{
+			{Name: "app", Path: "/var/log/app.log", Rules: []config.LogRuleConfig{
+				{Name: "errors", Regex: "ERROR"},
+				{Name: "errors", Regex: "FATAL"},
+			}},
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLogCollector(tt.cfgs)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLogCollectorCollect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendLog(t, path, "ERROR written before start\n")
+
+	c, err := NewLogCollector([]config.LogConfig{{
+		Name: "app",
+		Path: path,
+		Rules: []config.LogRuleConfig{
+			{Name: "errors", Regex: `\bERROR\b`},
+			{Name: "latency", Regex: `duration=(?P<value>[0-9.]+)ms`, Buckets: []float64{100, 10}},
+		},
+	}})
+	require.NoError(t, err)
+
+	collect := func() map[string]metrics.Metrics {
+		ms, err := c.Collect(context.Background())
+		require.NoError(t, err)
+		return metricsByName(ms)
+	}
+
+	byName := collect()
+	assert.Equal(t, int64(0), *byName["LogMatches{log=app,rule=errors}"].Delta, "should skip lines written before start")
+	assert.NotContains(t, byName, "LogValueAvg{log=app,rule=latency}", "should not report gauges without values")
+
+	appendLog(t, path, "INFO duration=5ms\nERROR failed duration=50ms\nERROR timeout duration=200ms\nERROR part")
+	byName = collect()
+	assert.Equal(t, int64(2), *byName["LogMatches{log=app,rule=errors}"].Delta, "should skip incomplete line")
+	assert.Equal(t, int64(3), *byName["LogMatches{log=app,rule=latency}"].Delta)
+	assert.Equal(t, int64(3), *byName["LogValueCount{log=app,rule=latency}"].Delta)
+	assert.Equal(t, float64(255), *byName["LogValueSum{log=app,rule=latency}"].Value)
+	assert.Equal(t, float64(5), *byName["LogValueMin{log=app,rule=latency}"].Value)
+	assert.Equal(t, float64(200), *byName["LogValueMax{log=app,rule=latency}"].Value)
+	assert.Equal(t, float64(85), *byName["LogValueAvg{log=app,rule=latency}"].Value)
+	assert.Equal(t, int64(1), *byName["LogValueBucket{le=10,log=app,rule=latency}"].Delta)
+	assert.Equal(t, int64(2), *byName["LogValueBucket{le=100,log=app,rule=latency}"].Delta)
+	assert.Equal(t, int64(3), *byName["LogValueBucket{le=+Inf,log=app,rule=latency}"].Delta)
+
+	appendLog(t, path, "ial\nERROR before rotation\n")
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendLog(t, path, "ERROR after rotation\n")
+	byName = collect()
+	assert.Equal(t, int64(3), *byName["LogMatches{log=app,rule=errors}"].Delta,
+		"should read the rest of the rotated file and the new file")
+
+	require.NoError(t, os.Truncate(path, 0))
+	appendLog(t, path, "ERROR\n")
+	byName = collect()
+	assert.Equal(t, int64(1), *byName["LogMatches{log=app,rule=errors}"].Delta, "should read truncated file from the beginning")
+
+	require.NoError(t, os.Remove(path))
+	byName = collect()
+	assert.Equal(t, int64(0), *byName["LogMatches{log=app,rule=errors}"].Delta, "should skip removed file")
+	appendLog(t, path, "ERROR recreated\n")
+	byName = collect()
+	assert.Equal(t, int64(1), *byName["LogMatches{log=app,rule=errors}"].Delta, "should read recreated file from the beginning")
+
+	descriptors := c.Describe()
+	for _, m := range collect() {
+		declared := false
+		for _, d := range descriptors {
+			declared = declared || d.Match(m.ID, metrics.MetricType(m.MType))
+		}
+		assert.True(t, declared, "metric %s should be declared by the collector", m.ID)
+	}
+	for _, d := range descriptors {
+		if d.Type == metrics.Gauge {
+			assert.True(t, d.Prefix, "gauge %s should be declared as removable family", d.Name)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package agent
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/net"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// NetworkCollectorName is the name of the network interface metrics collector.
+const NetworkCollectorName = "network"
+
+const (
+	netBytesSentMetric      = "NetBytesSent"
+	netBytesRecvMetric      = "NetBytesRecv"
+	netPacketsSentMetric    = "NetPacketsSent"
+	netPacketsRecvMetric    = "NetPacketsRecv"
+	netErrorsInMetric       = "NetErrorsIn"
+	netErrorsOutMetric      = "NetErrorsOut"
+	netDropsInMetric        = "NetDropsIn"
+	netDropsOutMetric       = "NetDropsOut"
+	netTCPConnectionsMetric = "NetTCPConnections"
+)
+
+// tcpStates is a list of TCP connection states reported by the collector.
+var tcpStates = []string{
+	"ESTABLISHED", "SYN_SENT", "SYN_RECV", "FIN_WAIT1", "FIN_WAIT2", "TIME_WAIT",
+	"CLOSE", "CLOSE_WAIT", "LAST_ACK", "LISTEN", "CLOSING",
+}
+
+// NetworkCollector collects traffic counters for each selected network interface and
+// the number of TCP connections in each state. Metric names hold the interface or state label,
+// for example NetBytesSent{interface=eth0} or NetTCPConnections{state=ESTABLISHED}.
+// Interface counters hold increments since the previous poll, so they are returned
+// starting from the second poll.
+type NetworkCollector struct {
+	config      config.NetworkCollectorConfig
+	ioCounters  func(ctx context.Context, perInterface bool) ([]net.IOCountersStat, error)
+	connections func(ctx context.Context, kind string) ([]net.ConnectionStat, error)
+
+	mu         sync.Mutex
+	prevCounts map[string]net.IOCountersStat
+}
+
+// NewNetworkCollector is constructor for creating a new NetworkCollector.
+func NewNetworkCollector(config config.NetworkCollectorConfig) *NetworkCollector {
+	return &NetworkCollector{
+		config:      config,
+		ioCounters:  net.IOCountersWithContext,
+		connections: net.ConnectionsWithContext,
+	}
+}
+
+// Name returns the collector name.
+func (c *NetworkCollector) Name() string {
+	return NetworkCollectorName
+}
+
+// Describe returns the metrics the collector can update.
+func (c *NetworkCollector) Describe() []metrics.Descriptor {
+	descriptors := []metrics.Descriptor{
+		metrics.NewPrefixDescriptor(netBytesSentMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(netBytesRecvMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(netPacketsSentMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(netPacketsRecvMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(netErrorsInMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(netErrorsOutMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(netDropsInMetric+"{", metrics.Counter),
+		metrics.NewPrefixDescriptor(netDropsOutMetric+"{", metrics.Counter),
+	}
+	for _, state := range tcpStates {
+		name := metrics.SeriesName(netTCPConnectionsMetric, map[string]string{"state": state})
+		descriptors = append(descriptors, metrics.NewGaugeDescriptor(name))
+	}
+	return descriptors
+}
+
+// Collect reads network interface counters and TCP connection states.
+// When one of the statistics can't be read, the other one is returned with the error.
+func (c *NetworkCollector) Collect(ctx context.Context) ([]metrics.Metrics, error) {
+	var lastErr error
+	result := make([]metrics.Metrics, 0)
+
+	counters, err := c.ioCounters(ctx, true)
+	if err != nil {
+		lastErr = err
+	} else {
+		result = append(result, c.ioCountersDeltas(counters)...)
+	}
+
+	connections, err := c.connections(ctx, "tcp")
+	if err != nil {
+		lastErr = err
+	} else {
+		result = append(result, tcpConnectionsMetrics(connections)...)
+	}
+
+	return result, lastErr
+}
+
+func (c *NetworkCollector) ioCountersDeltas(counters []net.IOCountersStat) []metrics.Metrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make([]metrics.Metrics, 0, len(counters)*8)
+	current := make(map[string]net.IOCountersStat, len(counters))
+	for _, cur := range counters {
+		if !matchFilter(c.config.Interfaces, cur.Name) {
+			continue
+		}
+		current[cur.Name] = cur
+
+		prev, ok := c.prevCounts[cur.Name]
+		if !ok {
+			continue
+		}
+
+		labels := map[string]string{"interface": cur.Name}
+		result = append(result,
+			metrics.NewCounterMetrics(metrics.SeriesName(netBytesSentMetric, labels), counterDelta(prev.BytesSent, cur.BytesSent)),
+			metrics.NewCounterMetrics(metrics.SeriesName(netBytesRecvMetric, labels), counterDelta(prev.BytesRecv, cur.BytesRecv)),
+			metrics.NewCounterMetrics(metrics.SeriesName(netPacketsSentMetric, labels), counterDelta(prev.PacketsSent, cur.PacketsSent)),
+			metrics.NewCounterMetrics(metrics.SeriesName(netPacketsRecvMetric, labels), counterDelta(prev.PacketsRecv, cur.PacketsRecv)),
+			metrics.NewCounterMetrics(metrics.SeriesName(netErrorsInMetric, labels), counterDelta(prev.Errin, cur.Errin)),
+			metrics.NewCounterMetrics(metrics.SeriesName(netErrorsOutMetric, labels), counterDelta(prev.Errout, cur.Errout)),
+			metrics.NewCounterMetrics(metrics.SeriesName(netDropsInMetric, labels), counterDelta(prev.Dropin, cur.Dropin)),
+			metrics.NewCounterMetrics(metrics.SeriesName(netDropsOutMetric, labels), counterDelta(prev.Dropout, cur.Dropout)),
+		)
+	}
+	c.prevCounts = current
+
+	return result
+}
+
+// tcpConnectionsMetrics counts TCP connections by state. States without connections are
+// reported with zero value, so the gauges don't keep stale counts.
+func tcpConnectionsMetrics(connections []net.ConnectionStat) []metrics.Metrics {
+	counts := make(map[string]int, len(tcpStates))
+	for _, conn := range connections {
+		counts[conn.Status]++
+	}
+
+	result := make([]metrics.Metrics, 0, len(tcpStates))
+	for _, state := range tcpStates {
+		name := metrics.SeriesName(netTCPConnectionsMetric, map[string]string{"state": state})
+		result = append(result, metrics.NewGaugeMetrics(name, float64(counts[state])))
+	}
+	return result
+}
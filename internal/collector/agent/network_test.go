@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func TestNetworkCollectorCollect(t *testing.T) {
+	counters := [][]net.IOCountersStat{
+		{
+			{Name: "eth0", BytesSent: 1000, BytesRecv: 2000, PacketsSent: 10, PacketsRecv: 20},
+			{Name: "lo", BytesSent: 500, BytesRecv: 500},
+		},
+		{
+			{Name: "eth0", BytesSent: 1500, BytesRecv: 2600, PacketsSent: 15, PacketsRecv: 26, Errin: 1, Dropout: 2},
+			{Name: "lo", BytesSent: 700, BytesRecv: 700},
+		},
+	}
+	poll := 0
+
+	c := NewNetworkCollector(config.NetworkCollectorConfig{Interfaces: config.FilterConfig{Exclude: []string{"lo"}}})
+	c.ioCounters = func(_ context.Context, _ bool) ([]net.IOCountersStat, error) {
+		result := counters[poll]
+		poll++
+		return result, nil
+	}
+	c.connections = func(_ context.Context, _ string) ([]net.ConnectionStat, error) {
+		return []net.ConnectionStat{{Status: "ESTABLISHED"}, {Status: "ESTABLISHED"}, {Status: "LISTEN"}}, nil
+	}
+
+	ms, err := c.Collect(context.Background())
+	require.NoError(t, err)
+	byName := metricsByName(ms)
+	assert.Len(t, byName, len(tcpStates), "should skip interface counters on the first poll")
+	assert.Equal(t, float64(2), *byName["NetTCPConnections{state=ESTABLISHED}"].Value)
+	assert.Equal(t, float64(1), *byName["NetTCPConnections{state=LISTEN}"].Value)
+	assert.Equal(t, float64(0), *byName["NetTCPConnections{state=TIME_WAIT}"].Value)
+
+	ms, err = c.Collect(context.Background())
+	require.NoError(t, err)
+	byName = metricsByName(ms)
+	assert.Equal(t, int64(500), *byName["NetBytesSent{interface=eth0}"].Delta)
+	assert.Equal(t, int64(600), *byName["NetBytesRecv{interface=eth0}"].Delta)
+	assert.Equal(t, int64(5), *byName["NetPacketsSent{interface=eth0}"].Delta)
+	assert.Equal(t, int64(1), *byName["NetErrorsIn{interface=eth0}"].Delta)
+	assert.Equal(t, int64(2), *byName["NetDropsOut{interface=eth0}"].Delta)
+	assert.NotContains(t, byName, "NetBytesSent{interface=lo}", "should skip excluded interface")
+
+	descriptors := c.Describe()
+	for _, m := range ms {
+		declared := false
+		for _, d := range descriptors {
+			declared = declared || d.Match(m.ID, metrics.MetricType(m.MType))
+		}
+		assert.True(t, declared, "metric %s should be declared by the collector", m.ID)
+	}
+}
+
+func TestNetworkCollectorCollectWithConnectionsError(t *testing.T) {
+	c := NewNetworkCollector(config.NetworkCollectorConfig{})
+	c.ioCounters = func(_ context.Context, _ bool) ([]net.IOCountersStat, error) {
+		return []net.IOCountersStat{{Name: "eth0"}}, nil
+	}
+	c.connections = func(_ context.Context, _ string) ([]net.ConnectionStat, error) {
+		return nil, errors.New("connections error")
+	}
+
+	_, err := c.Collect(context.Background())
+	assert.Error(t, err, "should return connections error")
+}
+
+func TestCounterDelta(t *testing.T) {
+	assert.Equal(t, int64(5), counterDelta(10, 15), "should return increment")
+	assert.Equal(t, int64(3), counterDelta(10, 3), "should return current value after counter reset")
+}
@@ -0,0 +1,234 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// ProbeCollectorName is the name of the synthetic probe metrics collector.
+const ProbeCollectorName = "probe"
+
+const (
+	probeTypeHTTP = "http"
+	probeTypeTCP  = "tcp"
+
+	probeUpMetric             = "ProbeUp"
+	probeStatusCodeMetric     = "ProbeStatusCode"
+	probeLatencyMetric        = "ProbeLatency"
+	probeCertExpiryDaysMetric = "ProbeCertExpiryDays"
+
+	defaultProbeTimeout = 10 * time.Second
+	probeMaxBodySize    = 1024 * 1024
+)
+
+// probe is an endpoint checked by the probe collector.
+type probe struct {
+	name    string
+	kind    string
+	target  string
+	timeout time.Duration
+	client  *http.Client
+	dialer  *net.Dialer
+}
+
+// probeResult is the result of a single probe check.
+type probeResult struct {
+	up         bool
+	statusCode int
+	latency    time.Duration
+	certExpiry *time.Time
+}
+
+// ProbeCollector checks HTTP(S) URLs and TCP endpoints concurrently. For each probe it reports
+// ProbeUp{probe=name} as 1 when the check succeeded and 0 otherwise, and ProbeLatency{probe=name}
+// in seconds. HTTP probes succeed on 2xx and 3xx responses and report ProbeStatusCode{probe=name},
+// which is 0 when no response was received. HTTPS probes report ProbeCertExpiryDays{probe=name}
+// as the days left until the earliest expiry of the server certificates.
+type ProbeCollector struct {
+	probes []probe
+	now    func() time.Time
+}
+
+// NewProbeCollector is constructor for creating a new ProbeCollector. It returns an error
+// if a probe config has no name, an unsupported type or an invalid target.
+func NewProbeCollector(cfgs []config.ProbeConfig) (*ProbeCollector, error) {
+	probes := make([]probe, 0, len(cfgs))
+	names := make(map[string]struct{}, len(cfgs))
+	for _, cfg := range cfgs {
+		p, err := newProbe(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := names[p.name]; exists {
+			return nil, fmt.Errorf("probe %s is configured more than once", p.name)
+		}
+		names[p.name] = struct{}{}
+		probes = append(probes, p)
+	}
+
+	return &ProbeCollector{probes: probes, now: time.Now}, nil
+}
+
+func newProbe(cfg config.ProbeConfig) (probe, error) {
+	name := strings.TrimSpace(cfg.Name)
+	if !metrics.IsValidLabelValue(name) {
+		return probe{}, fmt.Errorf("invalid probe name: %q", cfg.Name)
+	}
+
+	timeout := defaultProbeTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	p := probe{name: name, kind: strings.ToLower(cfg.Type), target: cfg.Target, timeout: timeout}
+
+	switch p.kind {
+	case probeTypeHTTP:
+		u, err := url.Parse(cfg.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return probe{}, fmt.Errorf("probe %s: invalid http target: %s", name, cfg.Target)
+		}
+		transport := &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}, //nolint:gosec
+		}
+		p.client = &http.Client{Transport: transport}
+	case probeTypeTCP:
+		if _, _, err := net.SplitHostPort(cfg.Target); err != nil {
+			return probe{}, fmt.Errorf("probe %s: invalid tcp target: %w", name, err)
+		}
+		p.dialer = &net.Dialer{}
+	default:
+		return probe{}, fmt.Errorf("probe %s: unsupported probe type: %s", name, cfg.Type)
+	}
+
+	return p, nil
+}
+
+// Name returns the collector name.
+func (c *ProbeCollector) Name() string {
+	return ProbeCollectorName
+}
+
+// Describe returns the metrics the collector can update.
+func (c *ProbeCollector) Describe() []metrics.Descriptor {
+	descriptors := make([]metrics.Descriptor, 0, len(c.probes)*4)
+	for _, p := range c.probes {
+		labels := map[string]string{"probe": p.name}
+		descriptors = append(descriptors,
+			metrics.NewGaugeDescriptor(metrics.SeriesName(probeUpMetric, labels)),
+			metrics.NewGaugeDescriptor(metrics.SeriesName(probeLatencyMetric, labels)),
+		)
+		if p.kind == probeTypeHTTP {
+			descriptors = append(descriptors,
+				metrics.NewGaugeDescriptor(metrics.SeriesName(probeStatusCodeMetric, labels)),
+				metrics.NewGaugeDescriptor(metrics.SeriesName(probeCertExpiryDaysMetric, labels)),
+			)
+		}
+	}
+	return descriptors
+}
+
+// Collect checks all probes. Failed checks are reported in metrics, not as errors.
+func (c *ProbeCollector) Collect(ctx context.Context) ([]metrics.Metrics, error) {
+	results := make([]probeResult, len(c.probes))
+	wg := sync.WaitGroup{}
+	for i, p := range c.probes {
+		wg.Add(1)
+		go func(i int, p probe) {
+			defer wg.Done()
+			results[i] = p.check(ctx)
+		}(i, p)
+	}
+	wg.Wait()
+
+	now := c.now()
+	result := make([]metrics.Metrics, 0, len(c.probes)*4)
+	for i, p := range c.probes {
+		r := results[i]
+		labels := map[string]string{"probe": p.name}
+
+		up := 0.0
+		if r.up {
+			up = 1
+		}
+		result = append(result,
+			metrics.NewGaugeMetrics(metrics.SeriesName(probeUpMetric, labels), up),
+			metrics.NewGaugeMetrics(metrics.SeriesName(probeLatencyMetric, labels), r.latency.Seconds()),
+		)
+		if p.kind == probeTypeHTTP {
+			result = append(result, metrics.NewGaugeMetrics(metrics.SeriesName(probeStatusCodeMetric, labels), float64(r.statusCode)))
+		}
+		if r.certExpiry != nil {
+			days := r.certExpiry.Sub(now).Hours() / 24
+			result = append(result, metrics.NewGaugeMetrics(metrics.SeriesName(probeCertExpiryDaysMetric, labels), days))
+		}
+	}
+
+	return result, nil
+}
+
+func (p probe) check(ctx context.Context) probeResult {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	if p.kind == probeTypeTCP {
+		return p.checkTCP(ctx)
+	}
+	return p.checkHTTP(ctx)
+}
+
+func (p probe) checkTCP(ctx context.Context) probeResult {
+	start := time.Now()
+	conn, err := p.dialer.DialContext(ctx, "tcp", p.target)
+	latency := time.Since(start)
+	if err != nil {
+		return probeResult{latency: latency}
+	}
+	conn.Close()
+
+	return probeResult{up: true, latency: latency}
+}
+
+func (p probe) checkHTTP(ctx context.Context) probeResult {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.target, nil)
+	if err != nil {
+		return probeResult{}
+	}
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return probeResult{latency: time.Since(start)}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, probeMaxBodySize))
+	latency := time.Since(start)
+
+	result := probeResult{
+		up:         resp.StatusCode >= 200 && resp.StatusCode < 400,
+		statusCode: resp.StatusCode,
+		latency:    latency,
+	}
+	if resp.TLS != nil {
+		for _, cert := range resp.TLS.PeerCertificates {
+			if result.certExpiry == nil || cert.NotAfter.Before(*result.certExpiry) {
+				notAfter := cert.NotAfter
+				result.certExpiry = &notAfter
+			}
+		}
+	}
+
+	return result
+}
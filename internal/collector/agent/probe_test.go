@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func TestNewProbeCollector(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfgs    []config.ProbeConfig
+		wantErr bool
+	}{
+		{name: "valid probes", cfgs: []config.ProbeConfig{
+			{Name: "web", Type: "http", Target: "https://example.com/health"},
+			{Name: "db", Type: "TCP", Target: "localhost:5432", Timeout: 2},
+		}},
+		{name: "empty name", cfgs: []config.ProbeConfig{{Type: "tcp", Target: "localhost:5432"}}, wantErr: true},
+		{name: "unsupported type", cfgs: []config.ProbeConfig{{Name: "db", Type: "udp", Target: "localhost:53"}},
+			wantErr: true},
+		{name: "invalid http target", cfgs: []config.ProbeConfig{{Name: "web", Type: "http", Target: "ftp://example.com"}},
+			wantErr: true},
+		{name: "invalid tcp target", cfgs: []config.ProbeConfig{{Name: "db", Type: "tcp", Target: "localhost"}},
+			wantErr: true},
+		{name: "duplicate name", cfgs: []config.ProbeConfig{
+			{Name: "db", Type: "tcp", Target: "localhost:5432"},
+			{Name: "db", Type: "tcp", Target: "localhost:5433"},
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProbeCollector(tt.cfgs)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProbeCollectorCollect(t *testing.T) {
+	okServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer okServer.Close()
+
+	failServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failServer.Close()
+
+	tlsServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	tlsServer.Config.ErrorLog = log.New(io.Discard, "", 0)
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddr := closedListener.Addr().String()
+	closedListener.Close()
+
+	c, err := NewProbeCollector([]config.ProbeConfig{
+		{Name: "ok", Type: "http", Target: okServer.URL},
+		{Name: "fail", Type: "http", Target: failServer.URL},
+		{Name: "tls", Type: "http", Target: tlsServer.URL, InsecureSkipVerify: true},
+		{Name: "untrusted", Type: "http", Target: tlsServer.URL},
+		{Name: "tcp", Type: "tcp", Target: listener.Addr().String()},
+		{Name: "closed", Type: "tcp", Target: closedAddr},
+	})
+	require.NoError(t, err)
+
+	ms, err := c.Collect(context.Background())
+	require.NoError(t, err)
+	byName := metricsByName(ms)
+
+	assert.Equal(t, float64(1), *byName["ProbeUp{probe=ok}"].Value)
+	assert.Equal(t, float64(200), *byName["ProbeStatusCode{probe=ok}"].Value)
+	assert.Greater(t, *byName["ProbeLatency{probe=ok}"].Value, float64(0))
+	assert.NotContains(t, byName, "ProbeCertExpiryDays{probe=ok}", "should not report certificate expiry for http")
+
+	assert.Equal(t, float64(0), *byName["ProbeUp{probe=fail}"].Value)
+	assert.Equal(t, float64(503), *byName["ProbeStatusCode{probe=fail}"].Value)
+
+	assert.Equal(t, float64(1), *byName["ProbeUp{probe=tls}"].Value)
+	assert.Equal(t, float64(204), *byName["ProbeStatusCode{probe=tls}"].Value)
+	assert.Greater(t, *byName["ProbeCertExpiryDays{probe=tls}"].Value, float64(0))
+
+	assert.Equal(t, float64(0), *byName["ProbeUp{probe=untrusted}"].Value, "should fail on untrusted certificate")
+	assert.Equal(t, float64(0), *byName["ProbeStatusCode{probe=untrusted}"].Value)
+
+	assert.Equal(t, float64(1), *byName["ProbeUp{probe=tcp}"].Value)
+	assert.Equal(t, float64(0), *byName["ProbeUp{probe=closed}"].Value)
+
+	descriptors := c.Describe()
+	for _, m := range ms {
+		declared := false
+		for _, d := range descriptors {
+			declared = declared || d.Match(m.ID, metrics.MetricType(m.MType))
+		}
+		assert.True(t, declared, "metric %s should be declared by the collector", m.ID)
+	}
+}
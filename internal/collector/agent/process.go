@@ -0,0 +1,341 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// ProcessCollectorName is the name of the process metrics collector.
+const ProcessCollectorName = "process"
+
+const (
+	processCountMetric      = "ProcessCount"
+	processRSSMetric        = "ProcessRSS"
+	processCPUPercentMetric = "ProcessCPUPercent"
+	processOpenFDsMetric    = "ProcessOpenFDs"
+	processThreadsMetric    = "ProcessThreads"
+	processUptimeMetric     = "ProcessUptime"
+)
+
+// processStat holds the statistics of a single process.
+type processStat struct {
+	rss        uint64
+	cpuTime    float64 // cpuTime is the total user and system CPU time in seconds
+	openFDs    int32
+	threads    int32
+	createTime time.Time
+}
+
+// processSelector selects processes by the executable name, PID file or command line.
+type processSelector struct {
+	name        string
+	processName string
+	pidFile     string
+	cmdline     *regexp.Regexp
+}
+
+// cpuSample is the CPU time of a process at the moment of the previous poll.
+type cpuSample struct {
+	cpuTime float64
+	at      time.Time
+}
+
+// ProcessCollector collects statistics of the watched processes. When several processes
+// match a selector, their statistics are summed, and the uptime of the oldest one is reported.
+// Metric names hold the process identity from the config, for example ProcessRSS{process=postgres}.
+// ProcessCount is zero when no process matches. CPU percent is computed from the CPU time
+// between two polls, so it is zero for a process at its first poll.
+type ProcessCollector struct {
+	selectors []processSelector
+	pids      func(ctx context.Context) ([]int32, error)
+	identity  func(ctx context.Context, pid int32) (string, string, error)
+	stat      func(ctx context.Context, pid int32) (processStat, error)
+	now       func() time.Time
+
+	mu       sync.Mutex
+	prevCPUs map[int32]cpuSample
+}
+
+// NewProcessCollector is constructor for creating a new ProcessCollector. It returns an error
+// if a process config has no identity, or doesn't have exactly one selector.
+func NewProcessCollector(cfgs []config.ProcessConfig) (*ProcessCollector, error) {
+	selectors := make([]processSelector, 0, len(cfgs))
+	names := make(map[string]struct{}, len(cfgs))
+	for _, cfg := range cfgs {
+		selector, err := newProcessSelector(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := names[selector.name]; exists {
+			return nil, fmt.Errorf("process %s is configured more than once", selector.name)
+		}
+		names[selector.name] = struct{}{}
+		selectors = append(selectors, selector)
+	}
+
+	return &ProcessCollector{
+		selectors: selectors,
+		pids:      process.PidsWithContext,
+		identity:  readProcessIdentity,
+		stat:      readProcessStat,
+		now:       time.Now,
+		prevCPUs:  make(map[int32]cpuSample),
+	}, nil
+}
+
+func newProcessSelector(cfg config.ProcessConfig) (processSelector, error) {
+	name := strings.TrimSpace(cfg.Name)
+	if !metrics.IsValidLabelValue(name) {
+		return processSelector{}, fmt.Errorf("invalid process name: %q", cfg.Name)
+	}
+
+	selector := processSelector{name: name, processName: cfg.ProcessName, pidFile: cfg.PIDFile}
+	selectorsCount := 0
+	for _, value := range []string{cfg.ProcessName, cfg.PIDFile, cfg.CmdlineRegex} {
+		if len(value) > 0 {
+			selectorsCount++
+		}
+	}
+	if selectorsCount != 1 {
+		return processSelector{}, fmt.Errorf("process %s should have one of process_name, pidfile or cmdline", name)
+	}
+
+	if len(cfg.CmdlineRegex) > 0 {
+		cmdline, err := regexp.Compile(cfg.CmdlineRegex)
+		if err != nil {
+			return processSelector{}, fmt.Errorf("process %s cmdline: %w", name, err)
+		}
+		selector.cmdline = cmdline
+	}
+
+	return selector, nil
+}
+
+// Name returns the collector name.
+func (c *ProcessCollector) Name() string {
+	return ProcessCollectorName
+}
+
+// Describe returns the metrics the collector can update.
+func (c *ProcessCollector) Describe() []metrics.Descriptor {
+	descriptors := make([]metrics.Descriptor, 0, len(c.selectors)*6)
+	for _, selector := range c.selectors {
+		labels := map[string]string{"process": selector.name}
+		for _, name := range []string{processCountMetric, processRSSMetric, processCPUPercentMetric,
+			processOpenFDsMetric, processThreadsMetric, processUptimeMetric} {
+			descriptors = append(descriptors, metrics.NewGaugeDescriptor(metrics.SeriesName(name, labels)))
+		}
+	}
+	return descriptors
+}
+
+// Collect reads statistics of the watched processes. Processes that can't be read are skipped,
+// the last error is returned with the collected metrics.
+func (c *ProcessCollector) Collect(ctx context.Context) ([]metrics.Metrics, error) {
+	if len(c.selectors) == 0 {
+		return nil, nil
+	}
+
+	var lastErr error
+	identities, err := c.processIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	cpus := make(map[int32]cpuSample)
+	result := make([]metrics.Metrics, 0, len(c.selectors)*6)
+	for _, selector := range c.selectors {
+		pids, err := c.selectPids(selector, identities)
+		if err != nil {
+			lastErr = err
+		}
+
+		var count int
+		var total processStat
+		var cpuPercent float64
+		var oldest time.Time
+		for _, pid := range pids {
+			stat, err := c.stat(ctx, pid)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			count++
+			total.rss += stat.rss
+			total.openFDs += stat.openFDs
+			total.threads += stat.threads
+			if prev, ok := c.prevCPUs[pid]; ok && now.After(prev.at) {
+				cpuPercent += max(stat.cpuTime-prev.cpuTime, 0) / now.Sub(prev.at).Seconds() * 100
+			}
+			cpus[pid] = cpuSample{cpuTime: stat.cpuTime, at: now}
+			if oldest.IsZero() || stat.createTime.Before(oldest) {
+				oldest = stat.createTime
+			}
+		}
+
+		var uptime float64
+		if count > 0 {
+			uptime = max(now.Sub(oldest).Seconds(), 0)
+		}
+
+		labels := map[string]string{"process": selector.name}
+		result = append(result,
+			metrics.NewGaugeMetrics(metrics.SeriesName(processCountMetric, labels), float64(count)),
+			metrics.NewGaugeMetrics(metrics.SeriesName(processRSSMetric, labels), float64(total.rss)),
+			metrics.NewGaugeMetrics(metrics.SeriesName(processCPUPercentMetric, labels), cpuPercent),
+			metrics.NewGaugeMetrics(metrics.SeriesName(processOpenFDsMetric, labels), float64(total.openFDs)),
+			metrics.NewGaugeMetrics(metrics.SeriesName(processThreadsMetric, labels), float64(total.threads)),
+			metrics.NewGaugeMetrics(metrics.SeriesName(processUptimeMetric, labels), uptime),
+		)
+	}
+	c.prevCPUs = cpus
+
+	return result, lastErr
+}
+
+// processIdentity holds the executable name and the command line of a process.
+type processIdentity struct {
+	pid     int32
+	name    string
+	cmdline string
+}
+
+// processIdentities reads identities of all processes when a selector needs them.
+func (c *ProcessCollector) processIdentities(ctx context.Context) ([]processIdentity, error) {
+	needScan := false
+	for _, selector := range c.selectors {
+		needScan = needScan || len(selector.pidFile) == 0
+	}
+	if !needScan {
+		return nil, nil
+	}
+
+	pids, err := c.pids(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]processIdentity, 0, len(pids))
+	for _, pid := range pids {
+		name, cmdline, err := c.identity(ctx, pid)
+		if err != nil {
+			continue
+		}
+		identities = append(identities, processIdentity{pid: pid, name: name, cmdline: cmdline})
+	}
+	return identities, nil
+}
+
+func (c *ProcessCollector) selectPids(selector processSelector, identities []processIdentity) ([]int32, error) {
+	if len(selector.pidFile) > 0 {
+		pid, err := readPIDFile(selector.pidFile)
+		if err != nil {
+			return nil, err
+		}
+		return []int32{pid}, nil
+	}
+
+	pids := make([]int32, 0)
+	for _, identity := range identities {
+		matched := selector.processName == identity.name ||
+			selector.cmdline != nil && selector.cmdline.MatchString(identity.cmdline)
+		if matched {
+			pids = append(pids, identity.pid)
+		}
+	}
+	return pids, nil
+}
+
+// readPIDFile reads the process ID from the first line of the PID file.
+func readPIDFile(path string) (int32, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("pid file %s is empty", path)
+	}
+
+	pid, err := strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("pid file %s: %w", path, err)
+	}
+	return int32(pid), nil
+}
+
+func readProcessIdentity(ctx context.Context, pid int32) (string, string, error) {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return "", "", err
+	}
+
+	name, err := p.NameWithContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	cmdline, err := p.CmdlineWithContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	return name, cmdline, nil
+}
+
+func readProcessStat(ctx context.Context, pid int32) (processStat, error) {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return processStat{}, err
+	}
+
+	memInfo, err := p.MemoryInfoWithContext(ctx)
+	if err != nil {
+		return processStat{}, err
+	}
+	times, err := p.TimesWithContext(ctx)
+	if err != nil {
+		return processStat{}, err
+	}
+	openFDs, err := p.NumFDsWithContext(ctx)
+	if err != nil {
+		return processStat{}, err
+	}
+	threads, err := p.NumThreadsWithContext(ctx)
+	if err != nil {
+		return processStat{}, err
+	}
+	createTime, err := p.CreateTimeWithContext(ctx)
+	if err != nil {
+		return processStat{}, err
+	}
+
+	return processStat{
+		rss:        memInfo.RSS,
+		cpuTime:    times.User + times.System,
+		openFDs:    openFDs,
+		threads:    threads,
+		createTime: time.UnixMilli(createTime),
+	}, nil
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func TestNewProcessCollector(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfgs    []config.ProcessConfig
+		wantErr bool
+	}{
+		{name: "valid selectors", cfgs: []config.ProcessConfig{
+			{Name: "server", ProcessName: "server"},
+			{Name: "agent", CmdlineRegex: "cmd/agent"},
+			{Name: "postgres", PIDFile: "/var/run/postgres.pid"},
+		}},
+		{name: "empty identity", cfgs: []config.ProcessConfig{{ProcessName: "server"}}, wantErr: true},
+		{name: "no selector", cfgs: []config.ProcessConfig{{Name: "server"}}, wantErr: true},
+		{name: "several selectors", cfgs: []config.ProcessConfig{{Name: "server", ProcessName: "server", PIDFile: "/pid"}},
+			wantErr: true},
+		{name: "invalid regex", cfgs: []config.ProcessConfig{{Name: "server", CmdlineRegex: "("}}, wantErr: true},
+		{name: "duplicate identity", cfgs: []config.ProcessConfig{
+			{Name: "server", ProcessName: "server"},
+			{Name: "server", ProcessName: "server2"},
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProcessCollector(tt.cfgs)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProcessCollectorCollect(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "db.pid")
+	require.NoError(t, os.WriteFile(pidFile, []byte("30\n/var/lib/db\n"), 0o600))
+
+	c, err := NewProcessCollector([]config.ProcessConfig{
+		{Name: "worker", ProcessName: "worker"},
+		{Name: "server", CmdlineRegex: `cmd/server\b`},
+		{Name: "db", PIDFile: pidFile},
+		{Name: "missing", ProcessName: "missing"},
+	})
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(time.Hour)
+	cpuTimes := map[int32]float64{10: 1, 11: 2, 20: 5, 30: 7}
+	c.now = func() time.Time { return now }
+	c.pids = func(_ context.Context) ([]int32, error) {
+		return []int32{10, 11, 20, 30}, nil
+	}
+	c.identity = func(_ context.Context, pid int32) (string, string, error) {
+		switch pid {
+		case 10, 11:
+			return "worker", "/usr/bin/worker", nil
+		case 20:
+			return "server", "/app/cmd/server -c config.json", nil
+		default:
+			return "db", "/usr/bin/db", nil
+		}
+	}
+	c.stat = func(_ context.Context, pid int32) (processStat, error) {
+		return processStat{
+			rss:        uint64(pid) * 1000,
+			cpuTime:    cpuTimes[pid],
+			openFDs:    pid,
+			threads:    2,
+			createTime: start.Add(time.Duration(pid) * time.Minute),
+		}, nil
+	}
+
+	ms, err := c.Collect(context.Background())
+	require.NoError(t, err)
+	byName := metricsByName(ms)
+	assert.Equal(t, float64(2), *byName["ProcessCount{process=worker}"].Value)
+	assert.Equal(t, float64(21000), *byName["ProcessRSS{process=worker}"].Value, "should sum rss of matched processes")
+	assert.Equal(t, float64(21), *byName["ProcessOpenFDs{process=worker}"].Value)
+	assert.Equal(t, float64(4), *byName["ProcessThreads{process=worker}"].Value)
+	assert.Equal(t, float64(50*60), *byName["ProcessUptime{process=worker}"].Value, "should report uptime of the oldest process")
+	assert.Equal(t, float64(0), *byName["ProcessCPUPercent{process=worker}"].Value, "should report zero cpu on the first poll")
+	assert.Equal(t, float64(1), *byName["ProcessCount{process=server}"].Value)
+	assert.Equal(t, float64(30000), *byName["ProcessRSS{process=db}"].Value, "should read pid from the pid file")
+	assert.Equal(t, float64(0), *byName["ProcessCount{process=missing}"].Value)
+
+	now = now.Add(10 * time.Second)
+	cpuTimes[10] += 1
+	cpuTimes[11] += 2
+	ms, err = c.Collect(context.Background())
+	require.NoError(t, err)
+	byName = metricsByName(ms)
+	assert.InDelta(t, 30.0, *byName["ProcessCPUPercent{process=worker}"].Value, 1e-9,
+		"should compute cpu percent from cpu time between polls")
+
+	descriptors := c.Describe()
+	for _, m := range ms {
+		declared := false
+		for _, d := range descriptors {
+			declared = declared || d.Match(m.ID, metrics.MetricType(m.MType))
+		}
+		assert.True(t, declared, "metric %s should be declared by the collector", m.ID)
+	}
+}
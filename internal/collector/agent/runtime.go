@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/monitors"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
+)
+
+// RuntimeCollectorName is the name of the Go runtime metrics collector.
+const RuntimeCollectorName = "runtime"
+
+var runtimeGaugeMetrics = []string{
+	"Alloc", "BuckHashSys", "Frees", "GCCPUFraction", "GCSys", "HeapAlloc", "HeapIdle", "HeapInuse",
+	"HeapObjects", "HeapReleased", "HeapSys", "LastGC", "Lookups", "MCacheInuse", "MCacheSys", "MSpanInuse",
+	"MSpanSys", "Mallocs", "NextGC", "NumForcedGC", "NumGC", "OtherSys", "PauseTotalNs", "StackInuse",
+	"StackSys", "Sys", "TotalAlloc", "RandomValue",
+}
+
+// RuntimeCollector collects Go runtime memory statistics and the RandomValue gauge metric.
+type RuntimeCollector struct {
+	monitor *monitors.RuntimeMonitor
+	random  *utils.Random
+}
+
+// NewRuntimeCollector is constructor for creating a new RuntimeCollector.
+func NewRuntimeCollector(monitor *monitors.RuntimeMonitor, random *utils.Random) *RuntimeCollector {
+	return &RuntimeCollector{monitor: monitor, random: random}
+}
+
+// Name returns the collector name.
+func (c *RuntimeCollector) Name() string {
+	return RuntimeCollectorName
+}
+
+// Describe returns the metrics the collector can update.
+func (c *RuntimeCollector) Describe() []metrics.Descriptor {
+	descriptors := make([]metrics.Descriptor, 0, len(runtimeGaugeMetrics))
+	for _, name := range runtimeGaugeMetrics {
+		descriptors = append(descriptors, metrics.NewGaugeDescriptor(name))
+	}
+	return descriptors
+}
+
+// Collect reads runtime memory statistics.
+func (c *RuntimeCollector) Collect(ctx context.Context) ([]metrics.Metrics, error) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	c.monitor.Update(&ms)
+
+	c.monitor.Lock()
+	m := c.monitor
+	result := []metrics.Metrics{
+		metrics.NewGaugeMetrics("Alloc", m.Alloc),
+		metrics.NewGaugeMetrics("BuckHashSys", m.BuckHashSys),
+		metrics.NewGaugeMetrics("Frees", m.Frees),
+		metrics.NewGaugeMetrics("GCCPUFraction", m.GCCPUFraction),
+		metrics.NewGaugeMetrics("GCSys", m.GCSys),
+		metrics.NewGaugeMetrics("HeapAlloc", m.HeapAlloc),
+		metrics.NewGaugeMetrics("HeapIdle", m.HeapIdle),
+		metrics.NewGaugeMetrics("HeapInuse", m.HeapInuse),
+		metrics.NewGaugeMetrics("HeapObjects", m.HeapObjects),
+		metrics.NewGaugeMetrics("HeapReleased", m.HeapReleased),
+		metrics.NewGaugeMetrics("HeapSys", m.HeapSys),
+		metrics.NewGaugeMetrics("LastGC", m.LastGC),
+		metrics.NewGaugeMetrics("Lookups", m.Lookups),
+		metrics.NewGaugeMetrics("MCacheInuse", m.MCacheInuse),
+		metrics.NewGaugeMetrics("MCacheSys", m.MCacheSys),
+		metrics.NewGaugeMetrics("MSpanInuse", m.MSpanInuse),
+		metrics.NewGaugeMetrics("MSpanSys", m.MSpanSys),
+		metrics.NewGaugeMetrics("Mallocs", m.Mallocs),
+		metrics.NewGaugeMetrics("NextGC", m.NextGC),
+		metrics.NewGaugeMetrics("NumForcedGC", m.NumForcedGC),
+		metrics.NewGaugeMetrics("NumGC", m.NumGC),
+		metrics.NewGaugeMetrics("OtherSys", m.OtherSys),
+		metrics.NewGaugeMetrics("PauseTotalNs", m.PauseTotalNs),
+		metrics.NewGaugeMetrics("StackInuse", m.StackInuse),
+		metrics.NewGaugeMetrics("StackSys", m.StackSys),
+		metrics.NewGaugeMetrics("Sys", m.Sys),
+		metrics.NewGaugeMetrics("TotalAlloc", m.TotalAlloc),
+	}
+	c.monitor.Unlock()
+
+	randomValue, err := c.random.Float(0.1, 99.99)
+	if err != nil {
+		return result, err
+	}
+
+	return append(result, metrics.NewGaugeMetrics("RandomValue", randomValue)), nil
+}
@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/monitors"
+)
+
+// UtilCollectorName is the name of the system utilization metrics collector.
+const UtilCollectorName = "util"
+
+// utilGaugeMetrics is a list of host gauge metrics reported at each poll.
+var utilGaugeMetrics = []string{
+	"TotalMemory", "FreeMemory", "AvailableMemory", "BuffersMemory", "CachedMemory",
+	"SwapTotal", "SwapUsed", "Load1", "Load5", "Load15", "Uptime",
+}
+
+// UtilCollector collects host memory, swap, load average, uptime, context switches and CPU
+// utilization statistics. CPU utilization is reported per logical CPU as CPUutilization1..N
+// and for all CPUs as CPUutilizationTotal, CPUUser, CPUSystem, CPUIowait and CPUSteal in percent
+// of the CPU time between two polls.
+type UtilCollector struct {
+	monitor *monitors.UtilMonitor
+}
+
+// NewUtilCollector is constructor for creating a new UtilCollector.
+func NewUtilCollector(monitor *monitors.UtilMonitor) *UtilCollector {
+	return &UtilCollector{monitor: monitor}
+}
+
+// Name returns the collector name.
+func (c *UtilCollector) Name() string {
+	return UtilCollectorName
+}
+
+// Describe returns the metrics the collector can update.
+func (c *UtilCollector) Describe() []metrics.Descriptor {
+	descriptors := make([]metrics.Descriptor, 0, len(utilGaugeMetrics)+6)
+	for _, name := range utilGaugeMetrics {
+		descriptors = append(descriptors, metrics.NewGaugeDescriptor(name))
+	}
+	return append(descriptors,
+		metrics.NewCounterDescriptor("ContextSwitches"),
+		metrics.NewPrefixDescriptor("CPUutilization", metrics.Gauge),
+		metrics.NewGaugeDescriptor("CPUUser"),
+		metrics.NewGaugeDescriptor("CPUSystem"),
+		metrics.NewGaugeDescriptor("CPUIowait"),
+		metrics.NewGaugeDescriptor("CPUSteal"),
+	)
+}
+
+// Collect reads host utilization statistics. Statistics unsupported by the platform are
+// skipped, their errors are returned with the collected metrics. CPU utilization and
+// context switches are returned starting from the second poll.
+func (c *UtilCollector) Collect(ctx context.Context) ([]metrics.Metrics, error) {
+	stats, err := readUtilStats(ctx)
+	if stats.Memory == nil {
+		return nil, err
+	}
+
+	c.monitor.Update(stats)
+
+	c.monitor.Lock()
+	defer c.monitor.Unlock()
+
+	m := c.monitor
+	values := []float64{
+		m.TotalMemory, m.FreeMemory, m.AvailableMemory, m.BuffersMemory, m.CachedMemory,
+		m.SwapTotal, m.SwapUsed, m.Load1, m.Load5, m.Load15, m.Uptime,
+	}
+	result := make([]metrics.Metrics, 0, len(values)+len(m.CPUutilization)+6)
+	for i, name := range utilGaugeMetrics {
+		result = append(result, metrics.NewGaugeMetrics(name, values[i]))
+	}
+
+	if m.ContextReady {
+		result = append(result, metrics.NewCounterMetrics("ContextSwitches", m.ContextSwitches))
+	}
+
+	if m.CPUReady {
+		for i, utilization := range m.CPUutilization {
+			result = append(result, metrics.NewGaugeMetrics(fmt.Sprintf("CPUutilization%d", i+1), utilization))
+		}
+		result = append(result,
+			metrics.NewGaugeMetrics("CPUutilizationTotal", m.CPUutilizationTotal),
+			metrics.NewGaugeMetrics("CPUUser", m.CPUUser),
+			metrics.NewGaugeMetrics("CPUSystem", m.CPUSystem),
+			metrics.NewGaugeMetrics("CPUIowait", m.CPUIowait),
+			metrics.NewGaugeMetrics("CPUSteal", m.CPUSteal),
+		)
+	}
+
+	return result, err
+}
+
+// readUtilStats reads host statistics. Statistics that can't be read are left nil
+// and their errors are joined.
+func readUtilStats(ctx context.Context) (monitors.UtilStats, error) {
+	var stats monitors.UtilStats
+	var errs []error
+
+	memory, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return stats, err
+	}
+	stats.Memory = memory
+
+	if swap, err := mem.SwapMemoryWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("read swap memory: %w", err))
+	} else {
+		stats.Swap = swap
+	}
+
+	if avg, err := load.AvgWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("read load average: %w", err))
+	} else {
+		stats.Load = avg
+	}
+
+	if misc, err := load.MiscWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("read context switches: %w", err))
+	} else {
+		stats.Misc = misc
+	}
+
+	if uptime, err := host.UptimeWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("read uptime: %w", err))
+	} else {
+		stats.Uptime = &uptime
+	}
+
+	perCPUTimes, err := cpu.TimesWithContext(ctx, true)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("read cpu times: %w", err))
+		return stats, errors.Join(errs...)
+	}
+	totalTimes, err := cpu.TimesWithContext(ctx, false)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("read total cpu times: %w", err))
+		return stats, errors.Join(errs...)
+	}
+	if len(totalTimes) == 0 {
+		errs = append(errs, errors.New("read total cpu times: no cpu times"))
+		return stats, errors.Join(errs...)
+	}
+	stats.PerCPUTimes = perCPUTimes
+	stats.TotalTimes = &totalTimes[0]
+
+	return stats, errors.Join(errs...)
+}
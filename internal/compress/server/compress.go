@@ -3,17 +3,20 @@ package server
 import (
 	"compress/gzip"
 	"io"
+	"mime"
 	"net/http"
 
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
 )
 
 // compressedContentTypes holds the content types that should be compressed.
-var compressedContentTypes = []string{"application/json", "text/html"}
+var compressedContentTypes = []string{"application/json", "text/html", "application/x-protobuf",
+	"application/x-ndjson", "text/csv", "text/plain"}
 
 type compressWriter struct {
-	w  http.ResponseWriter
-	zw *gzip.Writer
+	w          http.ResponseWriter
+	zw         *gzip.Writer
+	compressed bool
 }
 
 // NewCompressWriter is constructor for creating a new compressWriter wrapping http.ResponseWriter
@@ -32,9 +35,8 @@ func (c *compressWriter) Header() http.Header {
 
 // Write writes the data to response body.
 func (c *compressWriter) Write(body []byte) (int, error) {
-	contentType := c.Header().Values("Content-type")
-	needCompress := utils.Contains(contentType, compressedContentTypes...)
-	if needCompress {
+	if c.compressed || c.needCompress() {
+		c.compressed = true
 		c.w.Header().Set("Content-Encoding", "gzip")
 		return c.zw.Write(body)
 	}
@@ -42,15 +44,27 @@ func (c *compressWriter) Write(body []byte) (int, error) {
 }
 
 // WriteHeader write response headers and set response status code.
+// The response is compressed if its content type is set before the headers are written.
 func (c *compressWriter) WriteHeader(statusCode int) {
+	if c.needCompress() {
+		c.compressed = true
+		c.w.Header().Set("Content-Encoding", "gzip")
+	}
 	c.w.WriteHeader(statusCode)
 }
 
-// Close closes the gzip.Writer.
+// Close closes the gzip.Writer if the response body was compressed.
 func (c *compressWriter) Close() error {
+	if !c.compressed {
+		return nil
+	}
 	return c.zw.Close()
 }
 
+func (c *compressWriter) needCompress() bool {
+	return isCompressedContentType(c.Header().Values("Content-type"))
+}
+
 type compressReader struct {
 	r  io.ReadCloser
 	zr *gzip.Reader
@@ -88,8 +102,7 @@ func GzipMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		contentEncoding := r.Header.Values("Content-Encoding")
 		sendsGzip := utils.Contains(contentEncoding, "gzip")
-		contentType := r.Header.Values("Content-type")
-		needUncompressed := utils.Contains(compressedContentTypes, contentType...)
+		needUncompressed := isCompressedContentType(r.Header.Values("Content-type"))
 
 		if sendsGzip && needUncompressed {
 			cr, err := NewCompressReader(r.Body)
@@ -113,3 +126,18 @@ func GzipMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// isCompressedContentType checks if any of the content types is compressed. Media type
+// parameters, such as charset, are ignored.
+func isCompressedContentType(contentTypes []string) bool {
+	for _, contentType := range contentTypes {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			continue
+		}
+		if utils.Contains(compressedContentTypes, mediaType) {
+			return true
+		}
+	}
+	return false
+}
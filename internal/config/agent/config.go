@@ -1,17 +1,187 @@
 package agent
 
+// Backpressure policies applied when the metrics channel is full.
+const (
+	BackpressureBlock      = "block"       // Wait until the send metrics workers take a batch
+	BackpressureDropOldest = "drop-oldest" // Drop the oldest queued batch
+	BackpressureDropNewest = "drop-newest" // Drop the new batch
+	BackpressureCoalesce   = "coalesce"    // Merge the oldest queued batch into the new batch
+)
+
+// Aggregate functions of the gauge metric samples collected between reports.
+const (
+	AggregateMin   = "min"   // The minimum sample value
+	AggregateMax   = "max"   // The maximum sample value
+	AggregateAvg   = "avg"   // The average sample value
+	AggregateCount = "count" // The number of samples
+)
+
+// Relabel rule actions applied to the names of the collected metrics before sending.
+const (
+	RelabelKeep      = "keep"       // Keep only the metrics with names matching the regex
+	RelabelDrop      = "drop"       // Drop the metrics with names matching the regex
+	RelabelReplace   = "replace"    // Replace the names matching the regex with the expanded replacement
+	RelabelPrefix    = "prefix"     // Add the prefix to the names matching the regex
+	RelabelAddLabels = "add-labels" // Add the static labels to the metrics with names matching the regex
+)
+
 // AgentConfig holds the configuration for the agent.
 type AgentConfig struct {
-	SendMetricsURL        string `env:"ADDRESS"` // The URL to send metrics statistics to
-	SendMetricsEndPoint   string // The endpoint for sending metrics statistics to
-	UpdateMetricsInterval int    `env:"POLL_INTERVAL"`   // The interval for updating metrics statistics in seconds
-	SendMetricsInterval   int    `env:"REPORT_INTERVAL"` // The interval for sending metrics statistics in seconds
-	MetricsBufferSize     int    // The buffer size for metrics channel
-	RateLimit             int    `env:"RATE_LIMIT"` // The size of sending metrics statistics worker pool
-	GRPC                  bool   `env:"GRPC"`       // The grpc usage flag
-	SecretKey             string `env:"KEY"`        // The secret key for authentication
-	CryptoKeyPath         string `env:"CRYPTO_KEY"` // The path to secret public key for asymmetric encryption
-	TLSCertPath           string `env:"TLS_CERT"`   // The path to TLS certificate
-	ConfigFile            string `env:"CONFIG"`     //The path to json config file
-	LoggerLvl             string // The logging level
+	SendMetricsURL        string                     `env:"ADDRESS"` // The URL to send metrics statistics to
+	SendMetricsEndPoint   string                     // The endpoint for sending metrics statistics to
+	UpdateMetricsInterval int                        `env:"POLL_INTERVAL"`   // The interval for updating metrics statistics in seconds
+	SendMetricsInterval   int                        `env:"REPORT_INTERVAL"` // The interval for sending metrics statistics in seconds
+	ScheduleAlign         bool                       `env:"SCHEDULE_ALIGN"`  // The flag aligning the agent tasks runs to the wall clock multiples of their intervals
+	ScheduleJitter        int                        `env:"SCHEDULE_JITTER"` // The maximum random delay of the agent tasks first runs in seconds
+	MetricsBufferSize     int                        // The buffer size for metrics channel
+	BackpressurePolicy    string                     `env:"BACKPRESSURE_POLICY"`   // The policy applied when the metrics channel is full
+	RateLimit             int                        `env:"RATE_LIMIT"`            // The size of sending metrics statistics worker pool
+	BatchMaxItems         int                        `env:"BATCH_MAX_ITEMS"`       // The maximum number of metrics in a sent batch, batches aren't split by items when it's zero
+	BatchMaxBytes         int                        `env:"BATCH_MAX_BYTES"`       // The maximum JSON-encoded size of a sent batch in bytes, batches aren't split by size when it's zero
+	GRPC                  bool                       `env:"GRPC"`                  // The grpc usage flag
+	SecretKey             string                     `env:"KEY"`                   // The secret key for authentication
+	CryptoKeyPath         string                     `env:"CRYPTO_KEY"`            // The path to secret public key for asymmetric encryption
+	TLSCertPath           string                     `env:"TLS_CERT"`              // The path to TLS certificate
+	ConfigFile            string                     `env:"CONFIG"`                //The path to json config file
+	ConfigWatchInterval   int                        `env:"CONFIG_WATCH_INTERVAL"` // The interval for checking the json config file changes in seconds, the file isn't watched when it's zero
+	LoggerLvl             string                     // The logging level
+	PushAddress           string                     `env:"PUSH_ADDRESS"`   // The localhost address for accepting metrics from local applications
+	PushSocket            string                     `env:"PUSH_SOCKET"`    // The path to Unix socket for accepting metrics from local applications
+	AgentID               string                     `env:"AGENT_ID"`       // The agent identity used in metrics batch keys, generated when isn't set
+	SpoolDir              string                     `env:"SPOOL_DIR"`      // The directory for spooling metrics batches that failed to be sent
+	SpoolMaxSize          int64                      `env:"SPOOL_MAX_SIZE"` // The maximum size of the spool in bytes
+	SpoolMaxAge           int                        `env:"SPOOL_MAX_AGE"`  // The maximum age of spooled metrics batches in seconds
+	SpoolSegmentSize      int64                      // The maximum size of a spool segment file in bytes
+	Destinations          []DestinationConfig        // The servers to send metrics statistics to, defaults to the single server of the settings above
+	Collectors            map[string]CollectorConfig // The metrics collectors configuration by collector name
+	Disk                  DiskCollectorConfig        // The disk metrics collector configuration
+	Network               NetworkCollectorConfig     // The network metrics collector configuration
+	Processes             []ProcessConfig            // The processes watched by the process metrics collector
+	Exec                  []ExecConfig               // The commands run by the exec metrics collector
+	Probes                []ProbeConfig              // The endpoints checked by the probe metrics collector
+	Logs                  []LogConfig                // The log files tailed by the log metrics collector
+	Aggregations          []AggregationConfig        // The aggregations of the gauge metric samples collected between reports
+	Relabel               []RelabelConfig            // The rules applied in order to the names of the collected metrics before sending
+}
+
+// DestinationConfig holds the configuration for a server the agent sends metrics statistics to.
+// Each destination has its own worker pool, retries and spool.
+type DestinationConfig struct {
+	Name                string // The destination identity used in logs
+	SendMetricsURL      string // The URL to send metrics statistics to
+	SendMetricsEndPoint string // The endpoint for sending metrics statistics to
+	GRPC                bool   // The grpc usage flag
+	SecretKey           string // The secret key for authentication
+	CryptoKeyPath       string // The path to secret public key for asymmetric encryption
+	TLSCertPath         string // The path to TLS certificate
+	RateLimit           int    // The size of sending metrics statistics worker pool
+	BackpressurePolicy  string // The policy applied when the metrics channel is full
+	BatchMaxItems       int    // The maximum number of metrics in a sent batch
+	BatchMaxBytes       int    // The maximum JSON-encoded size of a sent batch in bytes
+	SpoolDir            string // The directory for spooling metrics batches that failed to be sent
+}
+
+// AggregationConfig holds the configuration for aggregating the samples of the gauge metrics collected
+// between reports. The gauge metric keeps reporting the last sample value, the aggregates are reported
+// as the metric series with the agg label, for example Alloc{agg=max}.
+type AggregationConfig struct {
+	Pattern   string   // The glob pattern of the gauge metric names, * matches any characters
+	Functions []string // The aggregate functions: min, max, avg and count, all of them when it's empty
+}
+
+// RelabelConfig holds the configuration for a rule filtering or changing the names of the collected metrics
+// before sending. The regex matches the whole metric name without the labels, for example DiskUsed
+// for DiskUsed{mountpoint=/}, and the labels are kept when the name is changed.
+type RelabelConfig struct {
+	Action      string            // The rule action: keep, drop, replace, prefix or add-labels
+	Regex       string            // The regular expression matching the metric names, matches all names when it's empty
+	Replacement string            // The new name for the replace action, $1 refers to the regex submatches
+	Prefix      string            // The prefix for the prefix action
+	Labels      map[string]string // The static labels for the add-labels action, they override the metric labels
+}
+
+// CollectorConfig holds the configuration for a metrics collector.
+type CollectorConfig struct {
+	Enabled      *bool // The collector enable flag, collectors are enabled when the flag isn't set
+	PollInterval int   // The interval for collecting metrics statistics in seconds, defaults to UpdateMetricsInterval
+	Timeout      int   // The timeout of collecting metrics statistics in seconds, collecting isn't limited when it's zero
+}
+
+// FilterConfig selects values by glob patterns. A value is selected when it matches any
+// of the Include patterns, or the Include patterns are empty, and doesn't match any of the Exclude patterns.
+type FilterConfig struct {
+	Include []string // The patterns of selected values
+	Exclude []string // The patterns of excluded values
+}
+
+// DiskCollectorConfig holds the configuration for the disk metrics collector.
+type DiskCollectorConfig struct {
+	FSTypes     FilterConfig // The filter for filesystem types
+	MountPoints FilterConfig // The filter for mount paths
+}
+
+// NetworkCollectorConfig holds the configuration for the network metrics collector.
+type NetworkCollectorConfig struct {
+	Interfaces FilterConfig // The filter for network interface names
+}
+
+// ProcessConfig selects processes watched by the process metrics collector.
+// Processes are selected by one of ProcessName, PIDFile or CmdlineRegex.
+type ProcessConfig struct {
+	Name         string // The process identity used in metric names
+	ProcessName  string // The executable name of the processes
+	PIDFile      string // The path to the file with the process ID
+	CmdlineRegex string // The regular expression matching the process command line
+}
+
+// ExecConfig holds the configuration for a command run by the exec metrics collector.
+type ExecConfig struct {
+	Name    string   // The command identity used in metric names
+	Command string   // The path to the executable
+	Args    []string // The command arguments
+	Timeout int      // The command timeout in seconds
+}
+
+// ProbeConfig holds the configuration for an endpoint checked by the probe metrics collector.
+type ProbeConfig struct {
+	Name               string // The probe identity used in metric names
+	Type               string // The probe type: http or tcp
+	Target             string // The URL for http probes or the host:port for tcp probes
+	Timeout            int    // The probe timeout in seconds
+	InsecureSkipVerify bool   // The flag disabling TLS certificate verification for https probes
+}
+
+// LogConfig holds the configuration for a log file tailed by the log metrics collector.
+type LogConfig struct {
+	Name  string          // The log identity used in metric names
+	Path  string          // The path to the log file
+	Rules []LogRuleConfig // The rules applied to each new line of the log file
+}
+
+// LogRuleConfig holds the configuration for a rule matching log lines.
+type LogRuleConfig struct {
+	Name    string    // The rule identity used in metric names
+	Regex   string    // The regular expression matching log lines, the "value" named group captures a number
+	Buckets []float64 // The upper bounds of the captured values buckets
+}
+
+// IsCollectorEnabled reports whether the collector with the specified name is enabled.
+func (c *AgentConfig) IsCollectorEnabled(name string) bool {
+	cfg, exists := c.Collectors[name]
+	return !exists || cfg.Enabled == nil || *cfg.Enabled
+}
+
+// CollectorPollInterval returns the interval for collecting metrics statistics by the collector
+// with the specified name in seconds.
+func (c *AgentConfig) CollectorPollInterval(name string) int {
+	if cfg, exists := c.Collectors[name]; exists && cfg.PollInterval > 0 {
+		return cfg.PollInterval
+	}
+	return c.UpdateMetricsInterval
+}
+
+// CollectorTimeout returns the timeout of collecting metrics statistics by the collector
+// with the specified name in seconds. Zero timeout doesn't limit collecting.
+func (c *AgentConfig) CollectorTimeout(name string) int {
+	return c.Collectors[name].Timeout
 }
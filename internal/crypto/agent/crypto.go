@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -35,3 +36,26 @@ func GetRSAPublicKey(fPath string) (*rsa.PublicKey, error) {
 
 	return rsaPublicKey, nil
 }
+
+// pkcs1v15Overhead is the size of the PKCS #1 v1.5 padding of an encrypted message.
+const pkcs1v15Overhead = 11
+
+// EncryptPKCS1v15Blocks encrypts the message of any size with RSA and PKCS #1 v1.5 padding.
+//
+// The message is split into the blocks of the key size minus the padding size, and each block is encrypted
+// separately. The encrypted blocks have the key size and are concatenated in the order of the message blocks,
+// so a message shorter than the block is encrypted the same way as by rsa.EncryptPKCS1v15.
+func EncryptPKCS1v15Blocks(publicKey *rsa.PublicKey, msg []byte) ([]byte, error) {
+	blockSize := publicKey.Size() - pkcs1v15Overhead
+	encrypted := make([]byte, 0, (len(msg)/blockSize+1)*publicKey.Size())
+	for start := 0; start < len(msg); start += blockSize {
+		end := min(start+blockSize, len(msg))
+		block, err := rsa.EncryptPKCS1v15(rand.Reader, publicKey, msg[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("encrypt message block: %w", err)
+		}
+		encrypted = append(encrypted, block...)
+	}
+
+	return encrypted, nil
+}
@@ -103,3 +103,40 @@ func readNonRSAPublicKeyPEM() (string, error) {
 	}
 	return string(pemBlock), nil
 }
+
+func TestEncryptPKCS1v15Blocks(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "unexpected error when generate private rsa key")
+	blockSize := privateKey.Size() - pkcs1v15Overhead
+
+	tests := []struct {
+		name       string
+		msgSize    int
+		wantBlocks int
+	}{
+		{name: "should encrypt empty message to no blocks", msgSize: 0, wantBlocks: 0},
+		{name: "should encrypt short message to one block", msgSize: 10, wantBlocks: 1},
+		{name: "should encrypt message of block size to one block", msgSize: blockSize, wantBlocks: 1},
+		{name: "should encrypt long message to several blocks", msgSize: 2*blockSize + 1, wantBlocks: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := make([]byte, tt.msgSize)
+			_, err := rand.Read(msg)
+			require.NoError(t, err)
+
+			encrypted, err := EncryptPKCS1v15Blocks(&privateKey.PublicKey, msg)
+			require.NoError(t, err)
+			require.Len(t, encrypted, tt.wantBlocks*privateKey.Size())
+
+			decrypted := make([]byte, 0, len(msg))
+			for start := 0; start < len(encrypted); start += privateKey.Size() {
+				block, err := rsa.DecryptPKCS1v15(rand.Reader, privateKey, encrypted[start:start+privateKey.Size()])
+				require.NoError(t, err)
+				decrypted = append(decrypted, block...)
+			}
+			assert.Equal(t, msg, decrypted)
+		})
+	}
+}
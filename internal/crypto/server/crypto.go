@@ -1,6 +1,7 @@
 package server
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -30,3 +31,24 @@ func GetRSAPrivateKey(fPath string) (*rsa.PrivateKey, error) {
 
 	return rsaPrivateKey, nil
 }
+
+// DecryptPKCS1v15Blocks decrypts the message encrypted with RSA and PKCS #1 v1.5 padding in the blocks
+// of the key size. The decrypted blocks are concatenated in their order. It returns an error
+// if the message size isn't a multiple of the key size or a block can't be decrypted.
+func DecryptPKCS1v15Blocks(privateKey *rsa.PrivateKey, ciphertext []byte) ([]byte, error) {
+	blockSize := privateKey.Size()
+	if len(ciphertext)%blockSize != 0 {
+		return nil, fmt.Errorf("decrypt message: size %d isn't a multiple of the key size %d", len(ciphertext), blockSize)
+	}
+
+	decrypted := make([]byte, 0, len(ciphertext))
+	for start := 0; start < len(ciphertext); start += blockSize {
+		block, err := rsa.DecryptPKCS1v15(rand.Reader, privateKey, ciphertext[start:start+blockSize])
+		if err != nil {
+			return nil, fmt.Errorf("decrypt message block: %w", err)
+		}
+		decrypted = append(decrypted, block...)
+	}
+
+	return decrypted, nil
+}
@@ -72,3 +72,29 @@ func readInvalidPEM() (string, error) {
 	}
 	return string(pemBlock), nil
 }
+
+func TestDecryptPKCS1v15Blocks(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "unexpected error when generate private rsa key")
+
+	first, err := rsa.EncryptPKCS1v15(rand.Reader, &privateKey.PublicKey, []byte("The Ultimate Question "))
+	require.NoError(t, err)
+	second, err := rsa.EncryptPKCS1v15(rand.Reader, &privateKey.PublicKey, []byte("of Life"))
+	require.NoError(t, err)
+
+	t.Run("should decrypt blocks in order", func(t *testing.T) {
+		decrypted, err := DecryptPKCS1v15Blocks(privateKey, append(append([]byte{}, first...), second...))
+		require.NoError(t, err)
+		assert.Equal(t, "The Ultimate Question of Life", string(decrypted))
+	})
+
+	t.Run("should return error when message size isn't multiple of key size", func(t *testing.T) {
+		_, err := DecryptPKCS1v15Blocks(privateKey, first[1:])
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error when block can't be decrypted", func(t *testing.T) {
+		_, err := DecryptPKCS1v15Blocks(privateKey, make([]byte, privateKey.Size()))
+		assert.Error(t, err)
+	})
+}
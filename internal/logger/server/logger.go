@@ -56,6 +56,11 @@ func (r *loggingResponseWriter) WriteHeader(statusCode int) {
 	r.responseData.status = statusCode
 }
 
+// Unwrap returns the original http.ResponseWriter, so http.ResponseController can flush streamed responses.
+func (r *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // LoggerMiddleware is an HTTP middleware fot logging the request details:
 // request uri, request method, process duration, response status, response body size
 func (logger *ServerLogger) LoggerMiddleware(next http.Handler) http.Handler {
@@ -97,3 +102,13 @@ func LoggerInterceptor(logger interceptor.Logger) grpc.UnaryServerInterceptor {
 	}
 	return logging.UnaryServerInterceptor(interceptor.NewInterceptorLogger(logger), opts...)
 }
+
+// LoggerStreamInterceptor returns a new StreamServerInterceptor that logs the details of each gRPC stream.
+// It uses the provided logger to log events related to the gRPC streams. The interceptor logs
+// only when the stream finishes.
+func LoggerStreamInterceptor(logger interceptor.Logger) grpc.StreamServerInterceptor {
+	opts := []logging.Option{
+		logging.WithLogOnEvents(logging.FinishCall),
+	}
+	return logging.StreamServerInterceptor(interceptor.NewInterceptorLogger(logger), opts...)
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// BatchKey identifies a metrics batch sent by the agent. The server applies a batch
+// with the same key only once, so the agent can safely retry sending it.
+type BatchKey struct {
+	AgentID  string // The identity of the agent sending the batch
+	Sequence uint64 // The monotonically increasing batch sequence number of the agent
+}
+
+// IsZero reports whether the batch key isn't set.
+func (k BatchKey) IsZero() bool {
+	return len(k.AgentID) == 0 || k.Sequence == 0
+}
+
+// ParseBatchKey parses a batch key from the agent ID and the string batch sequence number.
+// It returns the zero key if the agent ID or the sequence number is empty.
+func ParseBatchKey(agentID, sequence string) (BatchKey, error) {
+	if len(agentID) == 0 || len(sequence) == 0 {
+		return BatchKey{}, nil
+	}
+
+	seq, err := strconv.ParseUint(sequence, 10, 64)
+	if err != nil || seq == 0 {
+		return BatchKey{}, fmt.Errorf("invalid batch sequence: %s", sequence)
+	}
+
+	return BatchKey{AgentID: agentID, Sequence: seq}, nil
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import "strings"
+
+// Descriptor declares a metric or a family of dynamically named metrics.
+type Descriptor struct {
+	Name   string     // The name of the metric or the name prefix of the metrics family
+	Type   MetricType // The type of the metric (gauge or counter)
+	Prefix bool       // Prefix is set when Name is a prefix of dynamically named metrics
+}
+
+// NewGaugeDescriptor is constructor for creating a new Descriptor of the gauge metric.
+func NewGaugeDescriptor(name string) Descriptor {
+	return Descriptor{Name: name, Type: Gauge}
+}
+
+// NewCounterDescriptor is constructor for creating a new Descriptor of the counter metric.
+func NewCounterDescriptor(name string) Descriptor {
+	return Descriptor{Name: name, Type: Counter}
+}
+
+// NewPrefixDescriptor is constructor for creating a new Descriptor of the metrics family
+// with names starting with the prefix.
+func NewPrefixDescriptor(prefix string, mType MetricType) Descriptor {
+	return Descriptor{Name: prefix, Type: mType, Prefix: true}
+}
+
+// Match reports whether the metric with the specified name and type is declared by the Descriptor.
+func (d Descriptor) Match(name string, mType MetricType) bool {
+	if d.Type != mType {
+		return false
+	}
+	if d.Prefix {
+		return strings.HasPrefix(name, d.Name)
+	}
+	return d.Name == name
+}
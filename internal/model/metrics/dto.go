@@ -77,6 +77,16 @@ func MetricsToCounterMetric(m Metrics) CounterMetric {
 	return NewCounter(m.ID, *m.Delta)
 }
 
+// NewGaugeMetrics is constructor for creating a new Metrics of the gauge metric with the specified name and value.
+func NewGaugeMetrics(name string, value float64) Metrics {
+	return Metrics{ID: name, MType: string(Gauge), Value: &value}
+}
+
+// NewCounterMetrics is constructor for creating a new Metrics of the counter metric with the specified name and delta.
+func NewCounterMetrics(name string, delta int64) Metrics {
+	return Metrics{ID: name, MType: string(Counter), Delta: &delta}
+}
+
 // GaugeMetricToMetrics maps a GaugeMetric to a Metrics.
 func GaugeMetricToMetrics(m GaugeMetric) Metrics {
 	name := m.Name
@@ -101,6 +101,15 @@ var SupportedGaugeMetrics = map[string]GaugeMetric{
 	"TotalMemory":     NewGauge("TotalMemory", 0),
 	"FreeMemory":      NewGauge("FreeMemory", 0),
 	"CPUutilization1": NewGauge("CPUutilization1", 0),
+	"AvailableMemory": NewGauge("AvailableMemory", 0),
+	"BuffersMemory":   NewGauge("BuffersMemory", 0),
+	"CachedMemory":    NewGauge("CachedMemory", 0),
+	"SwapTotal":       NewGauge("SwapTotal", 0),
+	"SwapUsed":        NewGauge("SwapUsed", 0),
+	"Load1":           NewGauge("Load1", 0),
+	"Load5":           NewGauge("Load5", 0),
+	"Load15":          NewGauge("Load15", 0),
+	"Uptime":          NewGauge("Uptime", 0),
 }
 
 // SupportedCounterMetrics is a predefined map of supported counter metrics.
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// labelReservedChars are the characters that can't be used in label keys and values.
+const labelReservedChars = " \t{},="
+
+// SeriesName encodes the labels into the metric name in format: name{key1=value1,key2=value2}.
+// The labels are sorted by key, the name without labels is returned as is.
+func SeriesName(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+
+	return fmt.Sprintf("%s{%s}", name, strings.Join(pairs, ","))
+}
+
+// ParseSeriesName splits the metric name in format name{key1=value1,key2=value2}
+// into the name and the labels. The pairs without a key are skipped.
+func ParseSeriesName(id string) (string, map[string]string) {
+	labels := make(map[string]string)
+
+	start := strings.Index(id, "{")
+	if start < 0 || !strings.HasSuffix(id, "}") {
+		return id, labels
+	}
+
+	for _, pair := range strings.Split(id[start+1:len(id)-1], ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || len(key) == 0 {
+			continue
+		}
+		labels[key] = value
+	}
+
+	return id[:start], labels
+}
+
+// IsValidLabelValue reports whether the value can be used as a label key or value in metric names.
+func IsValidLabelValue(value string) bool {
+	return len(value) > 0 && !strings.ContainsAny(value, labelReservedChars)
+}
+
+// SanitizeLabelValue replaces the characters that can't be used in label values with underscores.
+// The empty value is replaced with the underscore.
+func SanitizeLabelValue(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(labelReservedChars, r) {
+			return '_'
+		}
+		return r
+	}, value)
+	if len(value) == 0 {
+		return "_"
+	}
+	return value
+}
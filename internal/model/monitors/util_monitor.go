@@ -4,29 +4,156 @@ import (
 	"sync"
 
 	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
 	"github.com/shirou/gopsutil/v3/mem"
 )
 
+// UtilStats holds host statistics read at one poll. Statistics that couldn't be read are nil.
+type UtilStats struct {
+	Memory      *mem.VirtualMemoryStat
+	Swap        *mem.SwapMemoryStat
+	Load        *load.AvgStat
+	Misc        *load.MiscStat
+	Uptime      *uint64         // Uptime is the host uptime in seconds
+	PerCPUTimes []cpu.TimesStat // PerCPUTimes holds times of each logical CPU
+	TotalTimes  *cpu.TimesStat  // TotalTimes holds times of all CPUs
+}
+
 // UtilMonitor holds system utilization metrics statistics.
+// CPU utilization is computed as a percentage of the CPU time spent between two updates,
+// so CPU statistics are available starting from the second update. Context switches
+// are the increment since the previous update.
 type UtilMonitor struct {
-	mu              sync.Mutex
-	TotalMemory     float64
-	FreeMemory      float64
-	CPUutilization1 float64
+	mu                  sync.Mutex
+	TotalMemory         float64
+	FreeMemory          float64
+	AvailableMemory     float64
+	BuffersMemory       float64
+	CachedMemory        float64
+	SwapTotal           float64
+	SwapUsed            float64
+	Load1               float64
+	Load5               float64
+	Load15              float64
+	Uptime              float64
+	ContextSwitches     int64
+	CPUutilization      []float64 // CPUutilization holds the utilization of each logical CPU
+	CPUutilizationTotal float64
+	CPUUser             float64
+	CPUSystem           float64
+	CPUIowait           float64
+	CPUSteal            float64
+	CPUReady            bool // CPUReady is set when CPU statistics were computed from two updates
+	ContextReady        bool // ContextReady is set when context switches were computed from two updates
+	prevPerCPUTimes     []cpu.TimesStat
+	prevTotalTimes      *cpu.TimesStat
+	prevContextSwitches *int
 }
 
-// Update updates the UtilMonitor with the latest statistics.
-func (m *UtilMonitor) Update(ms *mem.VirtualMemoryStat) error {
+// Update updates the UtilMonitor with the latest statistics. Statistics missing in the stats
+// keep their previous values.
+func (m *UtilMonitor) Update(stats UtilStats) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.TotalMemory = float64(ms.Total)
-	m.FreeMemory = float64(ms.Free)
-	cpuNumber, err := cpu.Counts(false)
-	if err != nil {
-		return err
+	if ms := stats.Memory; ms != nil {
+		m.TotalMemory = float64(ms.Total)
+		m.FreeMemory = float64(ms.Free)
+		m.AvailableMemory = float64(ms.Available)
+		m.BuffersMemory = float64(ms.Buffers)
+		m.CachedMemory = float64(ms.Cached)
+	}
+	if swap := stats.Swap; swap != nil {
+		m.SwapTotal = float64(swap.Total)
+		m.SwapUsed = float64(swap.Used)
+	}
+	if avg := stats.Load; avg != nil {
+		m.Load1 = avg.Load1
+		m.Load5 = avg.Load5
+		m.Load15 = avg.Load15
 	}
-	m.CPUutilization1 = float64(cpuNumber)
+	if stats.Uptime != nil {
+		m.Uptime = float64(*stats.Uptime)
+	}
+
+	m.updateContextSwitches(stats.Misc)
+	m.updateCPU(stats.PerCPUTimes, stats.TotalTimes)
+}
+
+func (m *UtilMonitor) updateContextSwitches(misc *load.MiscStat) {
+	if misc == nil {
+		m.ContextReady = false
+		return
+	}
+
+	m.ContextReady = m.prevContextSwitches != nil
+	if m.ContextReady {
+		prev := *m.prevContextSwitches
+		if misc.Ctxt >= prev {
+			m.ContextSwitches = int64(misc.Ctxt - prev)
+		} else {
+			m.ContextSwitches = int64(misc.Ctxt)
+		}
+	}
+	ctxt := misc.Ctxt
+	m.prevContextSwitches = &ctxt
+}
+
+func (m *UtilMonitor) updateCPU(perCPUTimes []cpu.TimesStat, totalTimes *cpu.TimesStat) {
+	if totalTimes == nil {
+		m.CPUReady = false
+		return
+	}
+
+	if m.prevTotalTimes != nil && len(m.prevPerCPUTimes) == len(perCPUTimes) {
+		utilization := make([]float64, len(perCPUTimes))
+		for i, times := range perCPUTimes {
+			utilization[i] = cpuBusyPercent(m.prevPerCPUTimes[i], times)
+		}
+		m.CPUutilization = utilization
 
-	return nil
+		prev, cur := *m.prevTotalTimes, *totalTimes
+		elapsed := cpuTotalTime(cur) - cpuTotalTime(prev)
+		m.CPUutilizationTotal = cpuBusyPercent(prev, cur)
+		m.CPUUser = cpuPercent(cur.User-prev.User, elapsed)
+		m.CPUSystem = cpuPercent(cur.System-prev.System, elapsed)
+		m.CPUIowait = cpuPercent(cur.Iowait-prev.Iowait, elapsed)
+		m.CPUSteal = cpuPercent(cur.Steal-prev.Steal, elapsed)
+		m.CPUReady = true
+	} else {
+		m.CPUReady = false
+	}
+
+	m.prevPerCPUTimes = append(m.prevPerCPUTimes[:0], perCPUTimes...)
+	total := *totalTimes
+	m.prevTotalTimes = &total
+}
+
+// Lock locks mutex of the UtilMonitor.
+func (m *UtilMonitor) Lock() {
+	m.mu.Lock()
+}
+
+// Unlock unlocks mutex of the UtilMonitor.
+func (m *UtilMonitor) Unlock() {
+	m.mu.Unlock()
+}
+
+// cpuTotalTime returns the total CPU time. Guest time is already accounted in user time.
+func cpuTotalTime(t cpu.TimesStat) float64 {
+	return t.User + t.System + t.Idle + t.Nice + t.Iowait + t.Irq + t.Softirq + t.Steal
+}
+
+// cpuBusyPercent returns the percentage of non-idle CPU time between two samples.
+func cpuBusyPercent(prev, cur cpu.TimesStat) float64 {
+	elapsed := cpuTotalTime(cur) - cpuTotalTime(prev)
+	idle := (cur.Idle + cur.Iowait) - (prev.Idle + prev.Iowait)
+	return cpuPercent(elapsed-idle, elapsed)
+}
+
+func cpuPercent(delta, elapsed float64) float64 {
+	if elapsed <= 0 || delta <= 0 {
+		return 0
+	}
+	return min(delta/elapsed*100, 100)
 }
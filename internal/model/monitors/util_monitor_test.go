@@ -0,0 +1,87 @@
+package monitors
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUtilMonitorUpdate(t *testing.T) {
+	uptime := uint64(3600)
+	stats := UtilStats{
+		Memory:      &mem.VirtualMemoryStat{Total: 1024, Free: 512, Available: 768, Buffers: 64, Cached: 128},
+		Swap:        &mem.SwapMemoryStat{Total: 2048, Used: 256},
+		Load:        &load.AvgStat{Load1: 0.5, Load5: 0.25, Load15: 0.1},
+		Misc:        &load.MiscStat{Ctxt: 1000},
+		Uptime:      &uptime,
+		PerCPUTimes: []cpu.TimesStat{{User: 10, Idle: 90}, {User: 50, Idle: 50}},
+		TotalTimes:  &cpu.TimesStat{User: 60, Idle: 140},
+	}
+	monitor := UtilMonitor{}
+
+	monitor.Update(stats)
+
+	assert.Equal(t, float64(1024), monitor.TotalMemory)
+	assert.Equal(t, float64(512), monitor.FreeMemory)
+	assert.Equal(t, float64(768), monitor.AvailableMemory)
+	assert.Equal(t, float64(64), monitor.BuffersMemory)
+	assert.Equal(t, float64(128), monitor.CachedMemory)
+	assert.Equal(t, float64(2048), monitor.SwapTotal)
+	assert.Equal(t, float64(256), monitor.SwapUsed)
+	assert.Equal(t, 0.5, monitor.Load1)
+	assert.Equal(t, 0.25, monitor.Load5)
+	assert.Equal(t, 0.1, monitor.Load15)
+	assert.Equal(t, float64(3600), monitor.Uptime)
+	assert.False(t, monitor.CPUReady, "should not compute cpu utilization from the first update")
+	assert.False(t, monitor.ContextReady, "should not compute context switches from the first update")
+
+	stats.Misc = &load.MiscStat{Ctxt: 1600}
+	stats.PerCPUTimes = []cpu.TimesStat{{User: 30, Idle: 170}, {User: 80, System: 10, Iowait: 10, Idle: 50}}
+	stats.TotalTimes = &cpu.TimesStat{User: 110, System: 10, Iowait: 10, Steal: 10, Idle: 220}
+	monitor.Update(stats)
+
+	assert.True(t, monitor.ContextReady, "should compute context switches from the second update")
+	assert.Equal(t, int64(600), monitor.ContextSwitches)
+	assert.True(t, monitor.CPUReady, "should compute cpu utilization from the second update")
+	assert.InDeltaSlice(t, []float64{20, 80}, monitor.CPUutilization, 1e-9, "should compute per cpu utilization")
+	assert.InDelta(t, 100*70/160.0, monitor.CPUutilizationTotal, 1e-9, "should compute total cpu utilization")
+	assert.InDelta(t, 100*50/160.0, monitor.CPUUser, 1e-9)
+	assert.InDelta(t, 100*10/160.0, monitor.CPUSystem, 1e-9)
+	assert.InDelta(t, 100*10/160.0, monitor.CPUIowait, 1e-9)
+	assert.InDelta(t, 100*10/160.0, monitor.CPUSteal, 1e-9)
+}
+
+func TestUtilMonitorUpdateWithMissingStats(t *testing.T) {
+	monitor := UtilMonitor{}
+
+	monitor.Update(UtilStats{
+		Memory:     &mem.VirtualMemoryStat{Total: 1024},
+		Load:       &load.AvgStat{Load1: 1},
+		Misc:       &load.MiscStat{Ctxt: 10},
+		TotalTimes: &cpu.TimesStat{User: 10, Idle: 90},
+	})
+	monitor.Update(UtilStats{Memory: &mem.VirtualMemoryStat{Total: 2048}})
+
+	assert.Equal(t, float64(2048), monitor.TotalMemory)
+	assert.Equal(t, float64(1), monitor.Load1, "should keep previous load average")
+	assert.False(t, monitor.ContextReady, "should skip context switches when they are missing")
+	assert.False(t, monitor.CPUReady, "should skip cpu utilization when cpu times are missing")
+}
+
+func TestUtilMonitorUpdateWithChangedCPUCount(t *testing.T) {
+	monitor := UtilMonitor{}
+
+	monitor.Update(UtilStats{
+		PerCPUTimes: []cpu.TimesStat{{User: 10, Idle: 90}},
+		TotalTimes:  &cpu.TimesStat{User: 10, Idle: 90},
+	})
+	monitor.Update(UtilStats{
+		PerCPUTimes: []cpu.TimesStat{{User: 20, Idle: 180}, {User: 10, Idle: 10}},
+		TotalTimes:  &cpu.TimesStat{User: 30, Idle: 190},
+	})
+
+	assert.False(t, monitor.CPUReady, "should skip cpu utilization when the cpu count changes")
+}
@@ -1,71 +1,51 @@
 package agent
 
 import (
-	"crypto/rsa"
-	"time"
-
-	"github.com/cenkalti/backoff/v4"
-	"gopkg.in/h2non/gentleman.v2"
-
+	collector "github.com/Stern-Ritter/metrics-and-alerting-service/internal/collector/agent"
 	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
 	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
-	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
-	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/monitors"
 	cache "github.com/Stern-Ritter/metrics-and-alerting-service/internal/storage/agent"
-	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
-	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
 )
 
-// Agent is monitoring agent that collects and sends metrics statistics to the server.
+// Agent is monitoring agent that collects and sends metrics statistics to the servers.
 type Agent struct {
-	HTTPClient                     *gentleman.Client
-	GRPCClient                     pb.MetricsV1ServiceClient
-	Cache                          cache.AgentCache
-	RuntimeMonitor                 *monitors.RuntimeMonitor
-	UtilMonitor                    *monitors.UtilMonitor
-	Random                         *utils.Random
-	Config                         *config.AgentConfig
-	metricsCh                      chan []metrics.Metrics
-	doneCh                         chan struct{}
-	sendMetricsBatchRetryIntervals *backoff.ExponentialBackOff
-	rsaPublicKey                   *rsa.PublicKey
-	Logger                         *logger.AgentLogger
+	Cache        cache.AgentCache
+	Collectors   *collector.Registry
+	Config       *config.AgentConfig
+	Destinations []*Destination
+	buildVersion string
+	relabelRules *RelabelRules
+	telemetry    *agentTelemetry
+	series       *collectedSeries
+	pushed       *pushedMetrics
+	Logger       *logger.AgentLogger
 }
 
 // NewAgent is constructor for creating a new Agent.
-func NewAgent(cache cache.AgentCache, runtimeMonitor *monitors.RuntimeMonitor,
-	utilMonitor *monitors.UtilMonitor, random *utils.Random, config *config.AgentConfig, rsaPublicKey *rsa.PublicKey,
+func NewAgent(cache cache.AgentCache, collectors *collector.Registry, config *config.AgentConfig,
 	logger *logger.AgentLogger) *Agent {
-
-	sendMetricsBatchRetryIntervals := backoff.NewExponentialBackOff(
-		backoff.WithInitialInterval(1*time.Second),
-		backoff.WithRandomizationFactor(0),
-		backoff.WithMultiplier(3),
-		backoff.WithMaxInterval(5*time.Second),
-		backoff.WithMaxElapsedTime(10*time.Second))
-
-	metricsCh := make(chan []metrics.Metrics, config.MetricsBufferSize)
-	doneCh := make(chan struct{})
-
 	return &Agent{
-		Cache:                          cache,
-		RuntimeMonitor:                 runtimeMonitor,
-		UtilMonitor:                    utilMonitor,
-		Random:                         random,
-		Config:                         config,
-		metricsCh:                      metricsCh,
-		doneCh:                         doneCh,
-		sendMetricsBatchRetryIntervals: sendMetricsBatchRetryIntervals,
-		rsaPublicKey:                   rsaPublicKey,
-		Logger:                         logger}
+		Cache:      cache,
+		Collectors: collectors,
+		Config:     config,
+		telemetry:  newAgentTelemetry(),
+		series:     newCollectedSeries(),
+		pushed:     newPushedMetrics(),
+		Logger:     logger}
+}
+
+// AddDestination adds the server the Agent sends metrics statistics to.
+func (a *Agent) AddDestination(destination *Destination) {
+	a.Destinations = append(a.Destinations, destination)
 }
 
-// SetHTTPClient sets the HTTP client for the Agent.
-func (a *Agent) SetHTTPClient(client *gentleman.Client) {
-	a.HTTPClient = client
+// SetBuildVersion sets the build version reported by the Agent in the self-telemetry metrics.
+func (a *Agent) SetBuildVersion(buildVersion string) {
+	a.buildVersion = buildVersion
 }
 
-// SetGRPCClient sets the gRPC client for the Agent.
-func (a *Agent) SetGRPCClient(grpcClient pb.MetricsV1ServiceClient) {
-	a.GRPCClient = grpcClient
+// SetRelabelRules sets the rules applied by the Agent to the collected metrics before sending.
+// The agent self-metrics aren't relabelled.
+func (a *Agent) SetRelabelRules(rules *RelabelRules) {
+	a.relabelRules = rules
 }
@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"go.uber.org/zap"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// Names of the agent self-metrics reporting the metrics channel backpressure.
+const (
+	droppedBatchesMetric   = "AgentDroppedBatches"
+	coalescedBatchesMetric = "AgentCoalescedBatches"
+)
+
+// reportMetricsBatch passes the reported metrics batch to the destination enqueue goroutine. When the previous
+// report is still waiting to be enqueued, the block policy waits until it's taken by the enqueue goroutine,
+// and the other policies merge it into the new one without waiting. The batch reported with the block policy
+// after the enqueuing is stopped is dropped.
+func (d *Destination) reportMetricsBatch(metricsBatch []metrics.Metrics) {
+	d.pendingMu.Lock()
+	for d.pendingBatch != nil && d.blocksOnFullChannel() {
+		d.pendingMu.Unlock()
+		select {
+		case <-d.pendingTakenCh:
+		case <-d.enqueueDoneCh:
+			d.droppedBatches.Add(1)
+			d.Logger.Warn("Destination is stopped, metrics batch dropped",
+				zap.String("event", "report metrics batch"))
+			return
+		}
+		d.pendingMu.Lock()
+	}
+	if d.pendingBatch != nil {
+		metricsBatch = coalesceMetricsBatches(d.pendingBatch, metricsBatch)
+		d.coalescedBatches.Add(1)
+		d.Logger.Debug("Destination is busy, waiting metrics batch coalesced",
+			zap.String("event", "report metrics batch"))
+	}
+	d.pendingBatch = metricsBatch
+	d.pendingMu.Unlock()
+
+	select {
+	case d.pendingCh <- struct{}{}:
+	default:
+	}
+}
+
+// blocksOnFullChannel reports whether the destination backpressure policy waits for the full metrics channel.
+func (d *Destination) blocksOnFullChannel() bool {
+	policy := d.CurrentConfig().BackpressurePolicy
+	return policy == "" || policy == config.BackpressureBlock
+}
+
+// runEnqueue splits the reported metrics batches by the destination batch limits and puts them
+// to the metrics channel until the enqueuing is stopped. The waiting batch is enqueued
+// before the metrics channel is closed.
+func (d *Destination) runEnqueue() {
+	defer close(d.enqueueDoneCh)
+
+	for {
+		select {
+		case <-d.pendingCh:
+			d.enqueuePendingBatch()
+		case <-d.stopEnqueueCh:
+			d.enqueuePendingBatch()
+			close(d.metricsCh)
+			return
+		}
+	}
+}
+
+func (d *Destination) enqueuePendingBatch() {
+	d.pendingMu.Lock()
+	metricsBatch := d.pendingBatch
+	d.pendingBatch = nil
+	d.pendingMu.Unlock()
+
+	select {
+	case d.pendingTakenCh <- struct{}{}:
+	default:
+	}
+
+	if metricsBatch == nil {
+		return
+	}
+
+	maxItems, maxBytes := d.batchLimits()
+	for _, batch := range splitMetricsBatch(metricsBatch, maxItems, maxBytes) {
+		d.enqueueMetricsBatch(batch)
+	}
+}
+
+// enqueueMetricsBatch puts the metrics batch to the metrics channel. When the channel is full,
+// the backpressure policy decides whether to wait for the send metrics workers, drop the oldest
+// or the new batch, or merge the oldest queued batch into the new one. The oldest batch is dropped
+// when the merged batch would exceed the destination batch limits.
+func (d *Destination) enqueueMetricsBatch(metricsBatch []metrics.Metrics) {
+	switch d.CurrentConfig().BackpressurePolicy {
+	case config.BackpressureDropNewest:
+		select {
+		case d.metricsCh <- metricsBatch:
+		default:
+			d.droppedBatches.Add(1)
+			d.Logger.Warn("Metrics channel is full, new metrics batch dropped",
+				zap.String("event", "enqueue metrics batch"))
+		}
+
+	case config.BackpressureDropOldest:
+		for {
+			select {
+			case d.metricsCh <- metricsBatch:
+				return
+			default:
+			}
+
+			select {
+			case <-d.metricsCh:
+				d.droppedBatches.Add(1)
+				d.Logger.Warn("Metrics channel is full, oldest metrics batch dropped",
+					zap.String("event", "enqueue metrics batch"))
+			default:
+			}
+		}
+
+	case config.BackpressureCoalesce:
+		for {
+			select {
+			case d.metricsCh <- metricsBatch:
+				return
+			default:
+			}
+
+			select {
+			case queuedBatch := <-d.metricsCh:
+				coalescedBatch := coalesceMetricsBatches(queuedBatch, metricsBatch)
+				if maxItems, maxBytes := d.batchLimits(); !fitsBatchLimits(coalescedBatch, maxItems, maxBytes) {
+					d.droppedBatches.Add(1)
+					d.Logger.Warn("Metrics channel is full, oldest metrics batch exceeding batch limits when coalesced dropped",
+						zap.String("event", "enqueue metrics batch"))
+					continue
+				}
+				metricsBatch = coalescedBatch
+				d.coalescedBatches.Add(1)
+				d.Logger.Debug("Metrics channel is full, oldest metrics batch coalesced",
+					zap.String("event", "enqueue metrics batch"))
+			default:
+			}
+		}
+
+	default:
+		d.metricsCh <- metricsBatch
+	}
+}
+
+// backpressureMetrics returns the counter metrics of the batches dropped and coalesced
+// since the previous call.
+func (d *Destination) backpressureMetrics() []metrics.Metrics {
+	dropped := int64(d.droppedBatches.Swap(0))
+	coalesced := int64(d.coalescedBatches.Swap(0))
+
+	return []metrics.Metrics{
+		{ID: droppedBatchesMetric, MType: string(metrics.Counter), Delta: &dropped},
+		{ID: coalescedBatchesMetric, MType: string(metrics.Counter), Delta: &coalesced},
+	}
+}
+
+// coalesceMetricsBatches merges the older metrics batch into the newer one. Deltas of the same
+// counter metrics are summed and the latest values of the same gauge metrics are kept.
+func coalesceMetricsBatches(older, newer []metrics.Metrics) []metrics.Metrics {
+	merged := make([]metrics.Metrics, 0, len(older)+len(newer))
+	index := make(map[string]int, len(older)+len(newer))
+
+	for _, batch := range [][]metrics.Metrics{older, newer} {
+		for _, m := range batch {
+			key := m.MType + ":" + m.ID
+			i, exists := index[key]
+			if !exists {
+				index[key] = len(merged)
+				merged = append(merged, m)
+				continue
+			}
+
+			if metrics.MetricType(m.MType) == metrics.Counter && merged[i].Delta != nil && m.Delta != nil {
+				delta := *merged[i].Delta + *m.Delta
+				merged[i].Delta = &delta
+				continue
+			}
+			merged[i] = m
+		}
+	}
+
+	return merged
+}
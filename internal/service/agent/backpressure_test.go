@@ -0,0 +1,143 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func newBackpressureTestBatch(gauge float64, counter int64) []metrics.Metrics {
+	return []metrics.Metrics{
+		{ID: "Alloc", MType: string(metrics.Gauge), Value: &gauge},
+		{ID: "PollCount", MType: string(metrics.Counter), Delta: &counter},
+	}
+}
+
+func TestEnqueueMetricsBatch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		policy        string
+		wantGauge     float64
+		wantCounter   int64
+		wantDropped   int64
+		wantCoalesced int64
+	}{
+		{
+			name:        "should drop the new batch with drop-newest policy",
+			policy:      config.BackpressureDropNewest,
+			wantGauge:   1,
+			wantCounter: 1,
+			wantDropped: 1,
+		},
+		{
+			name:        "should drop the oldest batch with drop-oldest policy",
+			policy:      config.BackpressureDropOldest,
+			wantGauge:   2,
+			wantCounter: 2,
+			wantDropped: 1,
+		},
+		{
+			name:          "should merge the oldest batch into the new batch with coalesce policy",
+			policy:        config.BackpressureCoalesce,
+			wantGauge:     2,
+			wantCounter:   3,
+			wantCoalesced: 1,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			destination := &Destination{
+				Config:    &config.DestinationConfig{BackpressurePolicy: tt.policy},
+				Logger:    &logger.AgentLogger{Logger: zap.NewNop()},
+				metricsCh: make(chan []metrics.Metrics, 1),
+			}
+
+			destination.enqueueMetricsBatch(newBackpressureTestBatch(1, 1))
+			destination.enqueueMetricsBatch(newBackpressureTestBatch(2, 2))
+
+			require.Len(t, destination.metricsCh, 1, "should keep the channel size")
+			got := <-destination.metricsCh
+			require.Len(t, got, 2)
+			assert.Equal(t, tt.wantGauge, *got[0].Value, "unexpected gauge value in the queued batch")
+			assert.Equal(t, tt.wantCounter, *got[1].Delta, "unexpected counter delta in the queued batch")
+
+			self := destination.backpressureMetrics()
+			assert.Equal(t, tt.wantDropped, *self[0].Delta, "unexpected dropped batches count")
+			assert.Equal(t, tt.wantCoalesced, *self[1].Delta, "unexpected coalesced batches count")
+
+			self = destination.backpressureMetrics()
+			assert.Equal(t, int64(0), *self[0].Delta, "should reset dropped batches count after report")
+			assert.Equal(t, int64(0), *self[1].Delta, "should reset coalesced batches count after report")
+		})
+	}
+}
+
+func TestEnqueueMetricsBatchBlock(t *testing.T) {
+	destination := &Destination{
+		Config:    &config.DestinationConfig{BackpressurePolicy: config.BackpressureBlock},
+		Logger:    &logger.AgentLogger{Logger: zap.NewNop()},
+		metricsCh: make(chan []metrics.Metrics, 1),
+	}
+	destination.enqueueMetricsBatch(newBackpressureTestBatch(1, 1))
+
+	done := make(chan struct{})
+	go func() {
+		destination.enqueueMetricsBatch(newBackpressureTestBatch(2, 2))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("should wait until the queued batch is taken with block policy")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	<-destination.metricsCh
+	<-done
+	got := <-destination.metricsCh
+	assert.Equal(t, 2.0, *got[0].Value, "should enqueue the new batch after the queued batch is taken")
+}
+
+func TestEnqueueMetricsBatchCoalesceBatchLimits(t *testing.T) {
+	destination := &Destination{
+		Config:    &config.DestinationConfig{BackpressurePolicy: config.BackpressureCoalesce, BatchMaxItems: 2},
+		Logger:    &logger.AgentLogger{Logger: zap.NewNop()},
+		metricsCh: make(chan []metrics.Metrics, 1),
+	}
+	value := 3.0
+	destination.enqueueMetricsBatch([]metrics.Metrics{
+		{ID: "HeapInuse", MType: string(metrics.Gauge), Value: &value},
+	})
+	destination.enqueueMetricsBatch(newBackpressureTestBatch(2, 2))
+
+	got := <-destination.metricsCh
+	assert.Equal(t, newBackpressureTestBatch(2, 2), got, "should drop the oldest batch exceeding batch limits when coalesced")
+
+	self := destination.backpressureMetrics()
+	assert.Equal(t, int64(1), *self[0].Delta, "unexpected dropped batches count")
+	assert.Equal(t, int64(0), *self[1].Delta, "unexpected coalesced batches count")
+}
+
+func TestCoalesceMetricsBatches(t *testing.T) {
+	older := newBackpressureTestBatch(1, 10)
+	total := 5.0
+	newer := append(newBackpressureTestBatch(2, 20), metrics.Metrics{ID: "TotalMemory", MType: string(metrics.Gauge), Value: &total})
+
+	got := coalesceMetricsBatches(older, newer)
+
+	require.Len(t, got, 3)
+	assert.Equal(t, "Alloc", got[0].ID)
+	assert.Equal(t, 2.0, *got[0].Value, "should keep the latest gauge value")
+	assert.Equal(t, "PollCount", got[1].ID)
+	assert.Equal(t, int64(30), *got[1].Delta, "should sum counter deltas")
+	assert.Equal(t, "TotalMemory", got[2].ID)
+	assert.Equal(t, int64(10), *older[1].Delta, "shouldn't change the older batch")
+}
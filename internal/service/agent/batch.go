@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// batchLimits returns the maximum number of metrics and the maximum JSON-encoded size of the batches
+// sent to the destination, zero means no limit. The encrypted HTTP requests aren't limited by the RSA key size,
+// because their bodies are encrypted in blocks.
+func (d *Destination) batchLimits() (maxItems, maxBytes int) {
+	cfg := d.CurrentConfig()
+	return cfg.BatchMaxItems, cfg.BatchMaxBytes
+}
+
+// splitMetricsBatch splits the metrics batch into the batches of at most maxItems metrics and at most
+// maxBytes JSON-encoded bytes. The metrics are sorted by type and name, so the batches of consecutive
+// reports hold the same metrics and can be coalesced. A metric exceeding maxBytes alone is sent in its own batch.
+func splitMetricsBatch(metricsBatch []metrics.Metrics, maxItems, maxBytes int) [][]metrics.Metrics {
+	if fitsBatchLimits(metricsBatch, maxItems, maxBytes) {
+		return [][]metrics.Metrics{metricsBatch}
+	}
+
+	sorted := make([]metrics.Metrics, len(metricsBatch))
+	copy(sorted, metricsBatch)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].MType != sorted[j].MType {
+			return sorted[i].MType < sorted[j].MType
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	batches := make([][]metrics.Metrics, 0)
+	start, size := 0, 0
+	for i, m := range sorted {
+		metricSize := encodedMetricSize(m)
+		full := maxItems > 0 && i-start == maxItems ||
+			maxBytes > 0 && i > start && size+metricSize+1 > maxBytes
+		if full {
+			batches = append(batches, sorted[start:i:i])
+			start, size = i, 0
+		}
+
+		if i == start {
+			size = metricSize + 2
+		} else {
+			size += metricSize + 1
+		}
+	}
+
+	return append(batches, sorted[start:])
+}
+
+// fitsBatchLimits reports whether the metrics batch has at most maxItems metrics
+// and at most maxBytes JSON-encoded bytes, zero means no limit.
+func fitsBatchLimits(metricsBatch []metrics.Metrics, maxItems, maxBytes int) bool {
+	if maxItems > 0 && len(metricsBatch) > maxItems {
+		return false
+	}
+	if maxBytes > 0 && encodedBatchSize(metricsBatch) > maxBytes {
+		return false
+	}
+	return true
+}
+
+// encodedBatchSize returns the size of the metrics batch encoded to the JSON array.
+func encodedBatchSize(metricsBatch []metrics.Metrics) int {
+	size := 2
+	for i, m := range metricsBatch {
+		if i > 0 {
+			size++
+		}
+		size += encodedMetricSize(m)
+	}
+	return size
+}
+
+// encodedMetricSize returns the size of the metric encoded to JSON.
+func encodedMetricSize(m metrics.Metrics) int {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func newBatchTestMetrics(ids ...string) []metrics.Metrics {
+	batch := make([]metrics.Metrics, 0, len(ids))
+	for _, id := range ids {
+		value := 1.0
+		batch = append(batch, metrics.Metrics{ID: id, MType: string(metrics.Gauge), Value: &value})
+	}
+	return batch
+}
+
+func batchIDs(batches [][]metrics.Metrics) [][]string {
+	ids := make([][]string, 0, len(batches))
+	for _, batch := range batches {
+		batchIDs := make([]string, 0, len(batch))
+		for _, m := range batch {
+			batchIDs = append(batchIDs, m.ID)
+		}
+		ids = append(ids, batchIDs)
+	}
+	return ids
+}
+
+func TestSplitMetricsBatch(t *testing.T) {
+	metricSize := encodedMetricSize(newBatchTestMetrics("A")[0])
+
+	testCases := []struct {
+		name     string
+		batch    []metrics.Metrics
+		maxItems int
+		maxBytes int
+		expected [][]string
+	}{
+		{
+			name:     "should keep the batch without limits",
+			batch:    newBatchTestMetrics("C", "A", "B"),
+			expected: [][]string{{"C", "A", "B"}},
+		},
+		{
+			name:     "should keep the batch fitting the limits",
+			batch:    newBatchTestMetrics("C", "A", "B"),
+			maxItems: 3,
+			maxBytes: 3*metricSize + 4,
+			expected: [][]string{{"C", "A", "B"}},
+		},
+		{
+			name:     "should split the sorted batch by items",
+			batch:    newBatchTestMetrics("E", "C", "A", "D", "B"),
+			maxItems: 2,
+			expected: [][]string{{"A", "B"}, {"C", "D"}, {"E"}},
+		},
+		{
+			name:     "should split the sorted batch by encoded size",
+			batch:    newBatchTestMetrics("C", "A", "B"),
+			maxBytes: 2*metricSize + 3,
+			expected: [][]string{{"A", "B"}, {"C"}},
+		},
+		{
+			name:     "should send the metric exceeding the encoded size alone",
+			batch:    newBatchTestMetrics("B", "A"),
+			maxBytes: metricSize,
+			expected: [][]string{{"A"}, {"B"}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := splitMetricsBatch(tt.batch, tt.maxItems, tt.maxBytes)
+			assert.Equal(t, tt.expected, batchIDs(batches))
+			for _, batch := range batches {
+				if len(batch) > 1 {
+					assert.True(t, fitsBatchLimits(batch, tt.maxItems, tt.maxBytes), "should fit the batch limits")
+				}
+			}
+		})
+	}
+}
+
+func TestBatchLimits(t *testing.T) {
+	rsaPrivateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		cfg          config.DestinationConfig
+		rsaPublicKey *rsa.PublicKey
+		wantItems    int
+		wantBytes    int
+	}{
+		{
+			name:      "should return the configured limits",
+			cfg:       config.DestinationConfig{BatchMaxItems: 10, BatchMaxBytes: 1000},
+			wantItems: 10,
+			wantBytes: 1000,
+		},
+		{
+			name:         "should not limit the size to the RSA key size with encryption",
+			cfg:          config.DestinationConfig{BatchMaxItems: 10},
+			rsaPublicKey: &rsaPrivateKey.PublicKey,
+			wantItems:    10,
+		},
+		{
+			name:         "should return the configured limits with encryption",
+			cfg:          config.DestinationConfig{BatchMaxBytes: 50},
+			rsaPublicKey: &rsaPrivateKey.PublicKey,
+			wantBytes:    50,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			destination := &Destination{Config: &tt.cfg, rsaPublicKey: tt.rsaPublicKey}
+			maxItems, maxBytes := destination.batchLimits()
+			assert.Equal(t, tt.wantItems, maxItems, "unexpected max items")
+			assert.Equal(t, tt.wantBytes, maxBytes, "unexpected max bytes")
+		})
+	}
+}
+
+func TestBatchLimitsEncryptedReport(t *testing.T) {
+	rsaPrivateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	destination := &Destination{Config: &config.DestinationConfig{}, rsaPublicKey: &rsaPrivateKey.PublicKey}
+
+	report := make([]metrics.Metrics, 0, len(metrics.SupportedGaugeMetrics)+1)
+	for name := range metrics.SupportedGaugeMetrics {
+		report = append(report, metrics.NewGaugeMetrics(name, 123456.789))
+	}
+	report = append(report, metrics.NewCounterMetrics("PollCount", 5))
+	require.Greater(t, encodedBatchSize(report), rsaPrivateKey.Size(), "report should be longer than the RSA key")
+
+	maxItems, maxBytes := destination.batchLimits()
+	batches := splitMetricsBatch(report, maxItems, maxBytes)
+	assert.Len(t, batches, 1, "should send the encrypted report in one request")
+}
@@ -2,7 +2,6 @@ package agent
 
 import (
 	"bytes"
-	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
 	"io"
@@ -33,11 +32,13 @@ func GetRSAPublicKey(path string) (*rsa.PublicKey, error) {
 
 // EncryptMiddleware is a middleware that encrypts the request body using RSA encryption.
 //
-// If the RSA public key is set in the Agent, this middleware reads the request body, encrypts it
-// using the public key, and replaces the original request body with the encrypted body. The
-// encrypted body is then passed along the middleware chain.
-func (a *Agent) EncryptMiddleware(ctx *context.Context, h context.Handler) {
-	isEncryptionEnabled := a.rsaPublicKey != nil
+// If the RSA public key is set in the Destination, this middleware reads the request body, encrypts it
+// using the public key, and replaces the original request body with the encrypted body. The body longer
+// than the key can encrypt at once is encrypted in blocks, so the size of the batches isn't limited
+// by the key size. The encrypted body is then passed along the middleware chain.
+func (d *Destination) EncryptMiddleware(ctx *context.Context, h context.Handler) {
+	rsaPublicKey := d.publicKey()
+	isEncryptionEnabled := rsaPublicKey != nil
 	if isEncryptionEnabled {
 		body, err := io.ReadAll(ctx.Request.Body)
 		if err != nil {
@@ -47,7 +48,7 @@ func (a *Agent) EncryptMiddleware(ctx *context.Context, h context.Handler) {
 		}
 
 		if len(body) > 0 {
-			encryptedBody, err := rsa.EncryptPKCS1v15(rand.Reader, a.rsaPublicKey, body)
+			encryptedBody, err := crypto.EncryptPKCS1v15Blocks(rsaPublicKey, body)
 			if err != nil {
 				ctx.Error = fmt.Errorf("middleware body encryption error: %w", err)
 				h.Next(ctx)
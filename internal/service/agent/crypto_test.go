@@ -76,14 +76,14 @@ func TestEncryptMiddleware(t *testing.T) {
 				rsaPublicKey = publicKey
 			}
 
-			agent := &Agent{
+			destination := &Destination{
 				rsaPublicKey: rsaPublicKey,
 			}
 
 			mockHandler := &EncryptMockHandler{}
 			mockHandler.On("Next", ctx).Once()
 
-			agent.EncryptMiddleware(ctx, mockHandler)
+			destination.EncryptMiddleware(ctx, mockHandler)
 
 			body, err := io.ReadAll(ctx.Request.Body)
 			require.NoError(t, err, "unexpected error when read request body")
@@ -0,0 +1,155 @@
+package agent
+
+import (
+	"crypto/rsa"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"go.uber.org/zap"
+	"gopkg.in/h2non/gentleman.v2"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	cache "github.com/Stern-Ritter/metrics-and-alerting-service/internal/storage/agent"
+	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
+)
+
+// Destination is a server the agent sends metrics statistics to. Each destination has its own
+// metrics channel, worker pool, retries and spool, so a slow destination doesn't hold back the others.
+// The config, the clients and the RSA public key of the destination can be replaced on config reload.
+type Destination struct {
+	mu                             sync.RWMutex
+	HTTPClient                     *gentleman.Client
+	GRPCClient                     pb.MetricsV1ServiceClient
+	Config                         *config.DestinationConfig
+	Spool                          *cache.Spool
+	agentID                        string
+	metricsCh                      chan []metrics.Metrics
+	pendingMu                      sync.Mutex
+	pendingBatch                   []metrics.Metrics
+	pendingCh                      chan struct{}
+	pendingTakenCh                 chan struct{}
+	stopEnqueueCh                  chan struct{}
+	enqueueDoneCh                  chan struct{}
+	sendMetricsBatchRetryIntervals *backoff.ExponentialBackOff
+	sendMetricsTimeout             time.Duration
+	batchSequence                  atomic.Uint64
+	droppedBatches                 atomic.Uint64
+	coalescedBatches               atomic.Uint64
+	sentBatches                    atomic.Uint64
+	failedBatches                  atomic.Uint64
+	retriedBatches                 atomic.Uint64
+	sendLatency                    atomic.Int64
+	sendLatencyCount               atomic.Int64
+	rsaPublicKey                   *rsa.PublicKey
+	pool                           sendMetricsWorkerPool
+	Logger                         *logger.AgentLogger
+}
+
+// sendMetricsTimeout is the time a metrics batch sent over the gRPC stream should be acknowledged in.
+const sendMetricsTimeout = 10 * time.Second
+
+// NewDestination is constructor for creating a new Destination. The agent identity and the metrics buffer size
+// are taken from the agent config. The destination enqueues the reported metrics batches in its own goroutine
+// until the send metrics worker pool is stopped.
+func NewDestination(cfg *config.DestinationConfig, agentCfg *config.AgentConfig,
+	rsaPublicKey *rsa.PublicKey, l *logger.AgentLogger) *Destination {
+
+	sendMetricsBatchRetryIntervals := backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(1*time.Second),
+		backoff.WithRandomizationFactor(0),
+		backoff.WithMultiplier(3),
+		backoff.WithMaxInterval(5*time.Second),
+		backoff.WithMaxElapsedTime(10*time.Second))
+
+	metricsCh := make(chan []metrics.Metrics, agentCfg.MetricsBufferSize)
+
+	destination := &Destination{
+		Config:                         cfg,
+		agentID:                        agentCfg.AgentID,
+		metricsCh:                      metricsCh,
+		pendingCh:                      make(chan struct{}, 1),
+		pendingTakenCh:                 make(chan struct{}, 1),
+		stopEnqueueCh:                  make(chan struct{}),
+		enqueueDoneCh:                  make(chan struct{}),
+		sendMetricsBatchRetryIntervals: sendMetricsBatchRetryIntervals,
+		sendMetricsTimeout:             sendMetricsTimeout,
+		rsaPublicKey:                   rsaPublicKey,
+		Logger:                         &logger.AgentLogger{Logger: l.With(zap.String("destination", cfg.Name))}}
+
+	// The batch sequence starts from the current time, so it keeps increasing across agent restarts.
+	destination.batchSequence.Store(uint64(time.Now().UnixNano()))
+
+	go destination.runEnqueue()
+
+	return destination
+}
+
+// nextBatchKey returns the key of the next metrics batch sent to the destination.
+func (d *Destination) nextBatchKey() metrics.BatchKey {
+	return metrics.BatchKey{AgentID: d.agentID, Sequence: d.batchSequence.Add(1)}
+}
+
+// SetHTTPClient sets the HTTP client for the Destination.
+func (d *Destination) SetHTTPClient(client *gentleman.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.HTTPClient = client
+}
+
+// SetSpool sets the spool for metrics batches that failed to be sent to the Destination.
+func (d *Destination) SetSpool(spool *cache.Spool) {
+	d.Spool = spool
+}
+
+// SetGRPCClient sets the gRPC client for the Destination.
+func (d *Destination) SetGRPCClient(grpcClient pb.MetricsV1ServiceClient) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.GRPCClient = grpcClient
+}
+
+// Update replaces the config, the RSA public key and the clients of the Destination. A nil client
+// keeps the current one, so the workers of the replaced protocol can finish sending their batches.
+// The batches being sent are retried with the new clients when they fail.
+func (d *Destination) Update(cfg *config.DestinationConfig, rsaPublicKey *rsa.PublicKey,
+	httpClient *gentleman.Client, grpcClient pb.MetricsV1ServiceClient) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.Config = cfg
+	d.rsaPublicKey = rsaPublicKey
+	if httpClient != nil {
+		d.HTTPClient = httpClient
+	}
+	if grpcClient != nil {
+		d.GRPCClient = grpcClient
+	}
+}
+
+// CurrentConfig returns the current config of the Destination.
+func (d *Destination) CurrentConfig() *config.DestinationConfig {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.Config
+}
+
+func (d *Destination) httpClient() *gentleman.Client {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.HTTPClient
+}
+
+func (d *Destination) grpcClient() pb.MetricsV1ServiceClient {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.GRPCClient
+}
+
+func (d *Destination) publicKey() *rsa.PublicKey {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.rsaPublicKey
+}
@@ -1,11 +1,8 @@
 package agent
 
 import (
-	"context"
 	"fmt"
 	"net"
-	"sync"
-	"time"
 
 	"gopkg.in/h2non/gentleman.v2"
 	"gopkg.in/h2non/gentleman.v2/plugins/body"
@@ -42,22 +39,3 @@ func getIPAddr() (string, error) {
 	}
 	return "", fmt.Errorf("no ip address found")
 }
-
-// SetInterval schedules a task at a specified interval until the context is done.
-// It uses a WaitGroup to manage the lifecycle of the task.
-func SetInterval(ctx context.Context, wg *sync.WaitGroup, task func(), interval time.Duration) {
-	wg.Add(1)
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				wg.Done()
-				return
-			default:
-				task()
-				time.Sleep(interval)
-			}
-		}
-	}()
-}
@@ -0,0 +1,177 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	er "github.com/Stern-Ritter/metrics-and-alerting-service/internal/errors"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+const (
+	pushMaxBodySize = 1024 * 1024
+	pushMaxMetrics  = 1000
+	// agentMetricsPrefix is the prefix of the agent self-telemetry metric names, reserved for the agent.
+	agentMetricsPrefix = "Agent"
+)
+
+// pushedMetrics holds the names and types of the metrics accepted from local applications.
+type pushedMetrics struct {
+	mu    sync.Mutex
+	names map[string]metrics.MetricType
+}
+
+// newPushedMetrics is constructor for creating a new pushedMetrics.
+func newPushedMetrics() *pushedMetrics {
+	return &pushedMetrics{names: make(map[string]metrics.MetricType)}
+}
+
+// PushMetricHandler accepts a metric from a local application in the JSON format of the server's
+// /update endpoint. Gauge metrics replace the cached values, counter metrics are added to them.
+// The metric is sent to the server with the next metrics batch.
+func (a *Agent) PushMetricHandler(res http.ResponseWriter, req *http.Request) {
+	var m metrics.Metrics
+	err := json.NewDecoder(http.MaxBytesReader(res, req.Body, pushMaxBodySize)).Decode(&m)
+	if err != nil {
+		http.Error(res, "Error decode request JSON body", http.StatusBadRequest)
+		return
+	}
+
+	a.pushMetrics(res, []metrics.Metrics{m})
+}
+
+// PushMetricsBatchHandler accepts a batch of metrics from a local application in the JSON format
+// of the server's /updates endpoint. The batch is rejected when any of the metrics is invalid.
+func (a *Agent) PushMetricsBatchHandler(res http.ResponseWriter, req *http.Request) {
+	ms := make([]metrics.Metrics, 0)
+	err := json.NewDecoder(http.MaxBytesReader(res, req.Body, pushMaxBodySize)).Decode(&ms)
+	if err != nil {
+		http.Error(res, "Error decode request JSON body", http.StatusBadRequest)
+		return
+	}
+
+	a.pushMetrics(res, ms)
+}
+
+func (a *Agent) pushMetrics(res http.ResponseWriter, ms []metrics.Metrics) {
+	for _, m := range ms {
+		if err := validatePushedMetrics(m); err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := a.acceptPushedNames(ms); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, m := range ms {
+		var err error
+		switch metrics.MetricType(m.MType) {
+		case metrics.Gauge:
+			a.Cache.Declare(metrics.NewGaugeDescriptor(m.ID))
+			_, err = a.Cache.UpdateGaugeMetric(metrics.NewGauge(m.ID, *m.Value))
+		case metrics.Counter:
+			a.Cache.Declare(metrics.NewCounterDescriptor(m.ID))
+			_, err = a.Cache.UpdateCounterMetric(metrics.NewCounter(m.ID, *m.Delta))
+		}
+		if err != nil {
+			a.Logger.Error(err.Error(), zap.String("event", "push metric"), zap.String("metric name", m.ID))
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	res.WriteHeader(http.StatusOK)
+}
+
+// acceptPushedNames records the names of the pushed metrics. It returns an error when a name
+// is reserved for the metrics of the agent, was pushed before with another type or when the count
+// of the distinct pushed names would exceed the limit. Nothing is recorded when an error is returned.
+func (a *Agent) acceptPushedNames(ms []metrics.Metrics) error {
+	a.pushed.mu.Lock()
+	defer a.pushed.mu.Unlock()
+
+	added := make(map[string]metrics.MetricType)
+	for _, m := range ms {
+		mType := metrics.MetricType(m.MType)
+		if pushedType, exists := a.pushed.names[m.ID]; exists {
+			if pushedType != mType {
+				return er.NewInvalidMetricType(fmt.Sprintf("The %s metric was pushed with the %s type", m.ID, pushedType), nil)
+			}
+			continue
+		}
+		if addedType, exists := added[m.ID]; exists {
+			if addedType != mType {
+				return er.NewInvalidMetricType(fmt.Sprintf("The %s metric is pushed with different types", m.ID), nil)
+			}
+			continue
+		}
+		if a.isAgentMetricName(m.ID) {
+			return er.NewInvalidMetricName(fmt.Sprintf("The %s metric name is reserved for the agent metrics", m.ID), nil)
+		}
+		added[m.ID] = mType
+	}
+
+	if len(a.pushed.names)+len(added) > pushMaxMetrics {
+		return er.NewInvalidMetricName(fmt.Sprintf("The count of the pushed metrics exceeds the limit of %d", pushMaxMetrics), nil)
+	}
+	for name, mType := range added {
+		a.pushed.names[name] = mType
+	}
+
+	return nil
+}
+
+// isAgentMetricName reports whether the metric name is used by the agent self-telemetry,
+// declared by the registered collectors or already held in the cache.
+func (a *Agent) isAgentMetricName(name string) bool {
+	if strings.HasPrefix(name, agentMetricsPrefix) {
+		return true
+	}
+
+	if a.Collectors != nil {
+		for _, c := range a.Collectors.Collectors() {
+			for _, d := range c.Describe() {
+				if d.Match(name, metrics.Gauge) || d.Match(name, metrics.Counter) {
+					return true
+				}
+			}
+		}
+	}
+
+	gauges, counters := a.Cache.GetMetrics()
+	_, isGauge := gauges[name]
+	_, isCounter := counters[name]
+	return isGauge || isCounter
+}
+
+func validatePushedMetrics(m metrics.Metrics) error {
+	if len(strings.TrimSpace(m.ID)) == 0 {
+		return er.NewInvalidMetricName("Metric name is empty", nil)
+	}
+
+	switch metrics.MetricType(m.MType) {
+	case metrics.Gauge:
+		if m.Value == nil {
+			return er.NewInvalidMetricValue(fmt.Sprintf("The value for the %s metric is missing", metrics.Gauge), nil)
+		}
+	case metrics.Counter:
+		if m.Delta == nil {
+			return er.NewInvalidMetricValue(fmt.Sprintf("The delta for the %s metric is missing", metrics.Counter), nil)
+		}
+		if *m.Delta < 0 {
+			return er.NewInvalidMetricValue(fmt.Sprintf("The delta for the %s metric can't be negative", metrics.Counter), nil)
+		}
+	default:
+		return er.NewInvalidMetricType(fmt.Sprintf("Invalid metric type: %s", m.MType), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	cache "github.com/Stern-Ritter/metrics-and-alerting-service/internal/storage/agent"
+)
+
+func TestPushMetricHandlers(t *testing.T) {
+	type want struct {
+		status   int
+		gauges   map[string]float64
+		counters map[string]int64
+	}
+
+	tests := []struct {
+		name    string
+		handler func(a *Agent) http.HandlerFunc
+		bodies  []string
+		want    want
+	}{
+		{
+			name:    "should add pushed gauge metric",
+			handler: func(a *Agent) http.HandlerFunc { return a.PushMetricHandler },
+			bodies:  []string{`{"id":"QueueSize","type":"gauge","value":12.5}`},
+			want:    want{status: http.StatusOK, gauges: map[string]float64{"QueueSize": 12.5}},
+		},
+		{
+			name:    "should aggregate pushed counter metric",
+			handler: func(a *Agent) http.HandlerFunc { return a.PushMetricHandler },
+			bodies: []string{
+				`{"id":"Requests","type":"counter","delta":3}`,
+				`{"id":"Requests","type":"counter","delta":4}`,
+			},
+			want: want{status: http.StatusOK, counters: map[string]int64{"Requests": 7}},
+		},
+		{
+			name:    "should add pushed metrics batch",
+			handler: func(a *Agent) http.HandlerFunc { return a.PushMetricsBatchHandler },
+			bodies: []string{`[{"id":"QueueSize","type":"gauge","value":1},` +
+				`{"id":"Requests","type":"counter","delta":2}]`},
+			want: want{status: http.StatusOK, gauges: map[string]float64{"QueueSize": 1},
+				counters: map[string]int64{"Requests": 2}},
+		},
+		{
+			name:    "should reject metric without value",
+			handler: func(a *Agent) http.HandlerFunc { return a.PushMetricHandler },
+			bodies:  []string{`{"id":"QueueSize","type":"gauge"}`},
+			want:    want{status: http.StatusBadRequest},
+		},
+		{
+			name:    "should reject counter metric with negative delta",
+			handler: func(a *Agent) http.HandlerFunc { return a.PushMetricHandler },
+			bodies:  []string{`{"id":"Requests","type":"counter","delta":-1}`},
+			want:    want{status: http.StatusBadRequest},
+		},
+		{
+			name:    "should reject whole batch with invalid metric",
+			handler: func(a *Agent) http.HandlerFunc { return a.PushMetricsBatchHandler },
+			bodies: []string{`[{"id":"QueueSize","type":"gauge","value":1},` +
+				`{"id":"Requests","type":"histogram","delta":2}]`},
+			want: want{status: http.StatusBadRequest},
+		},
+		{
+			name:    "should reject metric name reserved for agent metrics",
+			handler: func(a *Agent) http.HandlerFunc { return a.PushMetricHandler },
+			bodies:  []string{`{"id":"AgentUptime","type":"gauge","value":1}`},
+			want:    want{status: http.StatusBadRequest},
+		},
+		{
+			name:    "should reject metric pushed before with another type",
+			handler: func(a *Agent) http.HandlerFunc { return a.PushMetricHandler },
+			bodies: []string{
+				`{"id":"Requests","type":"counter","delta":3}`,
+				`{"id":"Requests","type":"gauge","value":4}`,
+			},
+			want: want{status: http.StatusBadRequest, counters: map[string]int64{"Requests": 3}},
+		},
+		{
+			name:    "should reject invalid json",
+			handler: func(a *Agent) http.HandlerFunc { return a.PushMetricHandler },
+			bodies:  []string{`{"id":`},
+			want:    want{status: http.StatusBadRequest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aLogger, err := logger.Initialize("info")
+			require.NoError(t, err, "Error init logger")
+			memCache := cache.NewAgentMemCache(make(map[string]metrics.GaugeMetric),
+				make(map[string]metrics.CounterMetric), aLogger)
+			agent := NewAgent(&memCache, nil, &config.AgentConfig{}, aLogger)
+
+			var status int
+			for _, body := range tt.bodies {
+				req := httptest.NewRequest(http.MethodPost, "/update", strings.NewReader(body))
+				rec := httptest.NewRecorder()
+				tt.handler(agent)(rec, req)
+				status = rec.Code
+			}
+
+			assert.Equal(t, tt.want.status, status)
+			gauges, counters := memCache.GetMetrics()
+			assert.Len(t, gauges, len(tt.want.gauges), "should not add metrics of rejected requests")
+			assert.Len(t, counters, len(tt.want.counters), "should not add metrics of rejected requests")
+			for name, value := range tt.want.gauges {
+				assert.Equal(t, value, gauges[name].Value)
+			}
+			for name, value := range tt.want.counters {
+				assert.Equal(t, value, counters[name].Value)
+			}
+		})
+	}
+}
+
+func TestPushMetricHandlersRejectAgentMetrics(t *testing.T) {
+	aLogger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	memCache := cache.NewAgentMemCache(make(map[string]metrics.GaugeMetric),
+		map[string]metrics.CounterMetric{"PollCount": metrics.NewCounter("PollCount", 0)}, aLogger)
+	agent := NewAgent(&memCache, nil, &config.AgentConfig{}, aLogger)
+
+	push := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/update", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		agent.PushMetricHandler(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, push(`{"id":"PollCount","type":"counter","delta":100}`),
+		"should reject metric held in the cache by the agent")
+
+	for i := 0; i < pushMaxMetrics; i++ {
+		require.Equal(t, http.StatusOK, push(fmt.Sprintf(`{"id":"Queue%d","type":"gauge","value":1}`, i)))
+	}
+	assert.Equal(t, http.StatusBadRequest, push(`{"id":"QueueOverLimit","type":"gauge","value":1}`),
+		"should reject new metric over the limit")
+	assert.Equal(t, http.StatusOK, push(`{"id":"Queue0","type":"gauge","value":2}`),
+		"should accept metric pushed before")
+
+	_, counters := memCache.GetMetrics()
+	assert.Equal(t, int64(0), counters["PollCount"].Value)
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"crypto/rsa"
+	"errors"
+	"net/http"
+
+	"github.com/cenkalti/backoff/v4"
+	"google.golang.org/grpc/codes"
+)
+
+// rejectedBatchError is the error of the metrics batch that can't be accepted by the server, so sending it
+// again fails the same way. The rejected batches are neither retried nor spooled.
+type rejectedBatchError struct {
+	err error
+}
+
+func (e rejectedBatchError) Error() string {
+	return e.err.Error()
+}
+
+func (e rejectedBatchError) Unwrap() error {
+	return e.err
+}
+
+// rejectBatch returns the permanent error of the rejected metrics batch.
+func rejectBatch(err error) error {
+	return backoff.Permanent(rejectedBatchError{err: err})
+}
+
+// isRejectedBatch reports whether the send error is the error of the rejected metrics batch.
+func isRejectedBatch(err error) bool {
+	return errors.As(err, &rejectedBatchError{})
+}
+
+// isRejectedHTTPStatus reports whether the server rejects the request content with the status code.
+// The authentication, the timeout and the rate limiting errors don't depend on the request content.
+func isRejectedHTTPStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError
+}
+
+// isRejectedGRPCCode reports whether the server rejects the request content with the gRPC status code.
+func isRejectedGRPCCode(code codes.Code) bool {
+	return code == codes.InvalidArgument || code == codes.OutOfRange || code == codes.ResourceExhausted
+}
+
+// isRejectedSendError reports whether the request failed to be sent because of its content,
+// like the body too large to be encrypted with the RSA key.
+func isRejectedSendError(err error) bool {
+	return errors.Is(err, rsa.ErrMessageTooLong)
+}
@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// relabelRule is the compiled relabel rule config.
+type relabelRule struct {
+	action      string
+	regex       *regexp.Regexp
+	replacement string
+	prefix      string
+	labels      map[string]string
+}
+
+// RelabelRules filter and change the names of the collected metrics before sending.
+type RelabelRules struct {
+	rules []relabelRule
+}
+
+// NewRelabelRules is constructor for creating new RelabelRules from the rule configs applied in their order.
+// It returns an error if a rule has an unknown action, an invalid regex or an invalid label.
+func NewRelabelRules(cfgs []config.RelabelConfig) (*RelabelRules, error) {
+	rules := make([]relabelRule, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		switch cfg.Action {
+		case config.RelabelKeep, config.RelabelDrop, config.RelabelReplace, config.RelabelPrefix, config.RelabelAddLabels:
+		default:
+			return nil, fmt.Errorf("invalid relabel action: %s", cfg.Action)
+		}
+
+		expr := cfg.Regex
+		if len(expr) == 0 {
+			expr = ".*"
+		}
+		regex, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", expr))
+		if err != nil {
+			return nil, fmt.Errorf("relabel %s: invalid regex %s: %w", cfg.Action, cfg.Regex, err)
+		}
+
+		for key, value := range cfg.Labels {
+			if !metrics.IsValidLabelValue(key) || !metrics.IsValidLabelValue(value) {
+				return nil, fmt.Errorf("relabel %s %s: invalid label %s=%s", cfg.Action, cfg.Regex, key, value)
+			}
+		}
+
+		rules = append(rules, relabelRule{action: cfg.Action, regex: regex, replacement: cfg.Replacement,
+			prefix: cfg.Prefix, labels: cfg.Labels})
+	}
+
+	return &RelabelRules{rules: rules}, nil
+}
+
+// Apply applies the rules to the metrics batch in order and returns the kept metrics with the new names
+// and the names of the metrics several metrics of the batch were renamed to. Such metrics are merged
+// into one: the counter deltas are added up, the gauge keeps the value of the last metric.
+// The batch is changed in place.
+func (r *RelabelRules) Apply(metricsBatch []metrics.Metrics) ([]metrics.Metrics, []string) {
+	if r == nil || len(r.rules) == 0 {
+		return metricsBatch, nil
+	}
+
+	relabelled := metricsBatch[:0]
+	type seriesKey struct{ id, mType string }
+	positions := make(map[seriesKey]int, len(metricsBatch))
+	merged := make([]string, 0)
+	for _, m := range metricsBatch {
+		id, keep := r.relabel(m.ID)
+		if !keep {
+			continue
+		}
+		m.ID = id
+
+		key := seriesKey{id: m.ID, mType: m.MType}
+		i, exists := positions[key]
+		if !exists {
+			positions[key] = len(relabelled)
+			relabelled = append(relabelled, m)
+			continue
+		}
+
+		if metrics.MetricType(m.MType) == metrics.Counter && relabelled[i].Delta != nil && m.Delta != nil {
+			delta := *relabelled[i].Delta + *m.Delta
+			m.Delta = &delta
+		}
+		relabelled[i] = m
+		merged = append(merged, m.ID)
+	}
+
+	return relabelled, merged
+}
+
+// relabel applies the rules to the metric name and returns the new name
+// and whether the metric is kept. The metric replaced with the empty name is dropped.
+func (r *RelabelRules) relabel(id string) (string, bool) {
+	name, labels := metrics.ParseSeriesName(id)
+
+	for _, rule := range r.rules {
+		matched := rule.regex.MatchString(name)
+		switch rule.action {
+		case config.RelabelKeep:
+			if !matched {
+				return "", false
+			}
+		case config.RelabelDrop:
+			if matched {
+				return "", false
+			}
+		case config.RelabelReplace:
+			if matched {
+				name = rule.regex.ReplaceAllString(name, rule.replacement)
+			}
+		case config.RelabelPrefix:
+			if matched {
+				name = rule.prefix + name
+			}
+		case config.RelabelAddLabels:
+			if matched {
+				for key, value := range rule.labels {
+					labels[key] = value
+				}
+			}
+		}
+	}
+
+	if len(name) == 0 {
+		return "", false
+	}
+
+	return metrics.SeriesName(name, labels), true
+}
@@ -0,0 +1,164 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	cache "github.com/Stern-Ritter/metrics-and-alerting-service/internal/storage/agent"
+)
+
+func metricsIDs(batch []metrics.Metrics) []string {
+	ids := make([]string, 0, len(batch))
+	for _, m := range batch {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestRelabelRulesApply(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rules    []config.RelabelConfig
+		ids      []string
+		expected []string
+	}{
+		{
+			name:     "should keep the batch without rules",
+			ids:      []string{"Alloc", "Lookups"},
+			expected: []string{"Alloc", "Lookups"},
+		},
+		{
+			name:     "should drop the metrics matching the regex",
+			rules:    []config.RelabelConfig{{Action: config.RelabelDrop, Regex: "Lookups|BuckHashSys"}},
+			ids:      []string{"Alloc", "Lookups", "BuckHashSys", "LookupsTotal"},
+			expected: []string{"Alloc", "LookupsTotal"},
+		},
+		{
+			name:     "should keep only the metrics matching the regex",
+			rules:    []config.RelabelConfig{{Action: config.RelabelKeep, Regex: "Disk.*"}},
+			ids:      []string{"Alloc", "DiskUsed{mountpoint=/}", "DiskFree{mountpoint=/}"},
+			expected: []string{"DiskUsed{mountpoint=/}", "DiskFree{mountpoint=/}"},
+		},
+		{
+			name: "should replace the names matching the regex keeping the labels",
+			rules: []config.RelabelConfig{
+				{Action: config.RelabelReplace, Regex: "Disk(.*)", Replacement: "disk_${1}_bytes"},
+			},
+			ids:      []string{"Alloc", "DiskUsed{mountpoint=/}"},
+			expected: []string{"Alloc", "disk_Used_bytes{mountpoint=/}"},
+		},
+		{
+			name:     "should add the prefix to all names with empty regex",
+			rules:    []config.RelabelConfig{{Action: config.RelabelPrefix, Prefix: "agent."}},
+			ids:      []string{"Alloc", "DiskUsed{mountpoint=/}"},
+			expected: []string{"agent.Alloc", "agent.DiskUsed{mountpoint=/}"},
+		},
+		{
+			name: "should add the static labels overriding the metric labels",
+			rules: []config.RelabelConfig{
+				{Action: config.RelabelAddLabels, Regex: "Disk.*", Labels: map[string]string{"env": "prod", "mountpoint": "root"}},
+			},
+			ids:      []string{"Alloc", "DiskUsed{mountpoint=/}"},
+			expected: []string{"Alloc", "DiskUsed{env=prod,mountpoint=root}"},
+		},
+		{
+			name: "should drop the metrics replaced with the empty name",
+			rules: []config.RelabelConfig{
+				{Action: config.RelabelReplace, Regex: "Disk(.*)", Replacement: "${2}"},
+			},
+			ids:      []string{"Alloc", "DiskUsed{mountpoint=/}"},
+			expected: []string{"Alloc"},
+		},
+		{
+			name: "should apply the rules in order",
+			rules: []config.RelabelConfig{
+				{Action: config.RelabelPrefix, Prefix: "agent."},
+				{Action: config.RelabelDrop, Regex: `agent\.Lookups`},
+				{Action: config.RelabelDrop, Regex: "Alloc"},
+			},
+			ids:      []string{"Alloc", "Lookups"},
+			expected: []string{"agent.Alloc"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := NewRelabelRules(tt.rules)
+			require.NoError(t, err)
+
+			batch := newBatchTestMetrics(tt.ids...)
+			relabelled, merged := rules.Apply(batch)
+			assert.Equal(t, tt.expected, metricsIDs(relabelled))
+			assert.Empty(t, merged, "shouldn't merge metrics with different names")
+		})
+	}
+}
+
+func TestRelabelRulesApplyMergesSameNames(t *testing.T) {
+	rules, err := NewRelabelRules([]config.RelabelConfig{
+		{Action: config.RelabelReplace, Regex: "(Alloc|TotalAlloc)", Replacement: "alloc"},
+		{Action: config.RelabelReplace, Regex: "(Requests|Responses)", Replacement: "http"},
+	})
+	require.NoError(t, err)
+
+	first, second := 1.0, 2.0
+	requests, responses := int64(3), int64(4)
+	batch := []metrics.Metrics{
+		{ID: "Alloc", MType: string(metrics.Gauge), Value: &first},
+		{ID: "Requests", MType: string(metrics.Counter), Delta: &requests},
+		{ID: "TotalAlloc", MType: string(metrics.Gauge), Value: &second},
+		{ID: "Responses", MType: string(metrics.Counter), Delta: &responses},
+	}
+
+	relabelled, merged := rules.Apply(batch)
+	require.Equal(t, []string{"alloc", "http"}, metricsIDs(relabelled), "should send one metric for each name")
+	assert.Equal(t, 2.0, *relabelled[0].Value, "should keep value of the last gauge")
+	assert.Equal(t, int64(7), *relabelled[1].Delta, "should add up counter deltas")
+	assert.Equal(t, []string{"alloc", "http"}, merged)
+	assert.Equal(t, int64(3), requests, "shouldn't change deltas of the batch metrics")
+}
+
+func TestNewRelabelRulesError(t *testing.T) {
+	_, err := NewRelabelRules([]config.RelabelConfig{{Action: "rename", Regex: "Alloc"}})
+	assert.Error(t, err, "should return error for unknown action")
+
+	_, err = NewRelabelRules([]config.RelabelConfig{{Action: config.RelabelDrop, Regex: "Alloc("}})
+	assert.Error(t, err, "should return error for invalid regex")
+
+	for _, labels := range []map[string]string{{"env": "a,b"}, {"env": "a=b"}, {"env}": "prod"}, {"env": ""}} {
+		_, err = NewRelabelRules([]config.RelabelConfig{{Action: config.RelabelAddLabels, Labels: labels}})
+		assert.Error(t, err, "should return error for invalid label %v", labels)
+	}
+}
+
+func TestSendMetricsRelabel(t *testing.T) {
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+	memCache := cache.NewAgentMemCache(make(map[string]metrics.GaugeMetric), metrics.SupportedCounterMetrics, aLogger)
+	memCache.Declare(metrics.NewGaugeDescriptor("Lookups"))
+
+	cfg := config.AgentConfig{MetricsBufferSize: 1}
+	agent := NewAgent(&memCache, nil, &cfg, aLogger)
+	destination := NewDestination(&config.DestinationConfig{}, &cfg, nil, aLogger)
+	agent.AddDestination(destination)
+	rules, err := NewRelabelRules([]config.RelabelConfig{
+		{Action: config.RelabelKeep, Regex: "PollCount"},
+		{Action: config.RelabelPrefix, Prefix: "agent."},
+	})
+	require.NoError(t, err)
+	agent.SetRelabelRules(rules)
+
+	agent.SendMetrics()
+
+	ids := metricsIDs(<-destination.metricsCh)
+	assert.Contains(t, ids, "agent.PollCount", "should relabel the collected metrics")
+	assert.NotContains(t, ids, "PollCount", "should relabel the collected metrics")
+	assert.NotContains(t, ids, "Lookups", "should drop the collected metrics not kept by the rules")
+	assert.NotContains(t, ids, "agent.Lookups", "should drop the collected metrics not kept by the rules")
+	assert.Contains(t, ids, batchesSentMetric, "shouldn't relabel the agent self-metrics")
+}
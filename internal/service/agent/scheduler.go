@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// TaskSchedule describes when a periodic agent task runs.
+type TaskSchedule struct {
+	Interval time.Duration // The period of the task runs, it should be positive
+	Align    bool          // The flag aligning the task runs to the wall clock multiples of the interval
+	Jitter   time.Duration // The maximum random delay of the first task run
+	Timeout  time.Duration // The timeout of a task run, the runs aren't limited when it's zero
+}
+
+// ScheduleTask runs the task at a fixed rate by the schedule until the context is done. The runs don't
+// drift by the task duration: a run starts every interval after the first one, and the runs
+// missed while the task is running are skipped. The first run is delayed until the next wall clock
+// multiple of the interval, if the schedule is aligned, and by a random jitter, so the agents started
+// together don't send requests to the server at the same time. The task context is canceled when
+// the run times out or the context is done. It uses a WaitGroup to manage the lifecycle of the task.
+func ScheduleTask(ctx context.Context, wg *sync.WaitGroup, task func(ctx context.Context), schedule TaskSchedule) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		timer := time.NewTimer(firstRunDelay(time.Now(), schedule, rand.Int63n))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		ticker := time.NewTicker(schedule.Interval)
+		defer ticker.Stop()
+
+		for {
+			runTask(ctx, task, schedule.Timeout)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runTask runs the task with the context canceled after the timeout, if it's set.
+func runTask(ctx context.Context, task func(ctx context.Context), timeout time.Duration) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	task(ctx)
+}
+
+// firstRunDelay returns the delay of the first task run by the schedule at the current time.
+// The random jitter is taken from [0, Jitter) with the randInt63n function.
+func firstRunDelay(now time.Time, schedule TaskSchedule, randInt63n func(n int64) int64) time.Duration {
+	var delay time.Duration
+	if schedule.Align && schedule.Interval > 0 {
+		next := now.Truncate(schedule.Interval)
+		if next.Before(now) {
+			next = next.Add(schedule.Interval)
+		}
+		delay = next.Sub(now)
+	}
+
+	if schedule.Jitter > 0 {
+		delay += time.Duration(randInt63n(int64(schedule.Jitter)))
+	}
+
+	return delay
+}
@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstRunDelay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 7, 0, time.UTC)
+	halfJitter := func(n int64) int64 { return n / 2 }
+
+	testCases := []struct {
+		name     string
+		now      time.Time
+		schedule TaskSchedule
+		want     time.Duration
+	}{
+		{
+			name:     "should run immediately without alignment and jitter",
+			now:      now,
+			schedule: TaskSchedule{Interval: 10 * time.Second},
+			want:     0,
+		},
+		{
+			name:     "should delay until the next wall clock multiple of the interval",
+			now:      now,
+			schedule: TaskSchedule{Interval: 10 * time.Second, Align: true},
+			want:     3 * time.Second,
+		},
+		{
+			name:     "should run immediately at the wall clock multiple of the interval",
+			now:      now.Add(3 * time.Second),
+			schedule: TaskSchedule{Interval: 10 * time.Second, Align: true},
+			want:     0,
+		},
+		{
+			name:     "should add jitter to the delay",
+			now:      now,
+			schedule: TaskSchedule{Interval: 10 * time.Second, Align: true, Jitter: 4 * time.Second},
+			want:     5 * time.Second,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, firstRunDelay(tt.now, tt.schedule, halfJitter))
+		})
+	}
+}
+
+func TestScheduleTask(t *testing.T) {
+	interval := 20 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+
+	runs := atomic.Int64{}
+	task := func(ctx context.Context) {
+		runs.Add(1)
+		time.Sleep(interval / 2)
+	}
+	ScheduleTask(ctx, &wg, task, TaskSchedule{Interval: interval})
+
+	time.Sleep(10*interval + interval/4)
+	cancel()
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, runs.Load(), int64(9), "should run the task at the fixed rate regardless of its duration")
+	assert.LessOrEqual(t, runs.Load(), int64(11), "should run the task at the fixed rate regardless of its duration")
+}
+
+func TestScheduleTaskTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wg := sync.WaitGroup{}
+
+	done := make(chan error, 1)
+	task := func(ctx context.Context) {
+		<-ctx.Done()
+		select {
+		case done <- ctx.Err():
+		default:
+		}
+	}
+	ScheduleTask(ctx, &wg, task, TaskSchedule{Interval: time.Hour, Timeout: 10 * time.Millisecond})
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "should cancel the task run after the timeout")
+	case <-time.After(time.Second):
+		t.Fatal("should cancel the task run after the timeout")
+	}
+
+	cancel()
+	wg.Wait()
+}
+
+func TestScheduleTaskCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+
+	runs := atomic.Int64{}
+	ScheduleTask(ctx, &wg, func(context.Context) { runs.Add(1) }, TaskSchedule{Interval: time.Hour})
+	ScheduleTask(ctx, &wg, func(context.Context) { runs.Add(1) }, TaskSchedule{Interval: time.Hour, Jitter: time.Hour})
+	time.Sleep(10 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		cancel()
+		wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("should stop waiting tasks immediately when the context is done")
+	}
+	assert.LessOrEqual(t, runs.Load(), int64(1), "shouldn't run the task delayed by the jitter")
+}
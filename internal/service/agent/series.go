@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"sync"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// collectedSeries holds the metrics returned by each collector on its last run.
+type collectedSeries struct {
+	mu         sync.Mutex
+	collectors map[string]map[string]metrics.MetricType
+}
+
+// newCollectedSeries is constructor for creating a new collectedSeries.
+func newCollectedSeries() *collectedSeries {
+	return &collectedSeries{collectors: make(map[string]map[string]metrics.MetricType)}
+}
+
+// update records the metrics returned by the collector run and returns the metrics returned by the previous
+// runs that the collector no longer returns. When the run failed, the metrics are added to the recorded ones
+// and nothing is returned, so the series of a temporarily failing source aren't removed.
+func (s *collectedSeries) update(collectorName string, ms []metrics.Metrics, failed bool) []metrics.Metrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev := s.collectors[collectorName]
+	cur := make(map[string]metrics.MetricType, len(ms))
+	for _, m := range ms {
+		cur[m.ID] = metrics.MetricType(m.MType)
+	}
+
+	if failed {
+		for id, mType := range prev {
+			if _, exists := cur[id]; !exists {
+				cur[id] = mType
+			}
+		}
+		s.collectors[collectorName] = cur
+		return nil
+	}
+	s.collectors[collectorName] = cur
+
+	stale := make([]metrics.Metrics, 0)
+	for id, mType := range prev {
+		if curType, exists := cur[id]; !exists || curType != mType {
+			stale = append(stale, metrics.Metrics{ID: id, MType: string(mType)})
+		}
+	}
+	return stale
+}
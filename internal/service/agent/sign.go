@@ -15,16 +15,19 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	gcontext "gopkg.in/h2non/gentleman.v2/context"
 )
 
 const (
-	signKey = "HashSHA256"
+	signKey   = "HashSHA256"
+	signField = "sign"
 )
 
 // SignMiddleware is a middleware that signs the request body with HMAC SHA256 if a secret key is configured.
-func (a *Agent) SignMiddleware(ctx *gcontext.Context, h gcontext.Handler) {
-	needSignResponseBody := len(strings.TrimSpace(a.Config.SecretKey)) > 0
+func (d *Destination) SignMiddleware(ctx *gcontext.Context, h gcontext.Handler) {
+	secretKey := d.CurrentConfig().SecretKey
+	needSignResponseBody := len(strings.TrimSpace(secretKey)) > 0
 	if needSignResponseBody {
 		body, err := io.ReadAll(ctx.Request.Body)
 		if err != nil {
@@ -35,7 +38,7 @@ func (a *Agent) SignMiddleware(ctx *gcontext.Context, h gcontext.Handler) {
 		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
 
 		if len(body) > 0 {
-			sign := getSign(body, a.Config.SecretKey)
+			sign := getSign(body, secretKey)
 			ctx.Request.Header.Add(signKey, sign)
 		}
 	}
@@ -44,9 +47,10 @@ func (a *Agent) SignMiddleware(ctx *gcontext.Context, h gcontext.Handler) {
 }
 
 // SignInterceptor is a gRPC client interceptor that signs the request with a secret key.
-func (a *Agent) SignInterceptor(ctx context.Context, method string, req interface{}, reply interface{},
+func (d *Destination) SignInterceptor(ctx context.Context, method string, req interface{}, reply interface{},
 	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-	needSignRequest := len(strings.TrimSpace(a.Config.SecretKey)) > 0
+	secretKey := d.CurrentConfig().SecretKey
+	needSignRequest := len(strings.TrimSpace(secretKey)) > 0
 	if needSignRequest {
 		message, ok := req.(proto.Message)
 		if !ok {
@@ -59,7 +63,7 @@ func (a *Agent) SignInterceptor(ctx context.Context, method string, req interfac
 		}
 
 		if len(body) > 0 {
-			sign := getSign(body, a.Config.SecretKey)
+			sign := getSign(body, secretKey)
 			ctx = metadata.AppendToOutgoingContext(ctx, signKey, sign)
 		}
 	}
@@ -67,6 +71,65 @@ func (a *Agent) SignInterceptor(ctx context.Context, method string, req interfac
 	return invoker(ctx, method, req, reply, cc, opts...)
 }
 
+// signClientStream wraps grpc.ClientStream and signs each message sent over the stream.
+type signClientStream struct {
+	grpc.ClientStream
+	secretKey string
+}
+
+// SendMsg signs the message and sends it over the stream.
+func (s *signClientStream) SendMsg(m interface{}) error {
+	message, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "sign interceptor: request isn't a proto.Message")
+	}
+
+	if err := signMessage(message, s.secretKey); err != nil {
+		return err
+	}
+
+	return s.ClientStream.SendMsg(m)
+}
+
+// SignStreamInterceptor is a gRPC client stream interceptor that signs each message sent over the stream
+// with a secret key. The signature is stored in the sign field of the message.
+func (d *Destination) SignStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+	streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	secretKey := d.CurrentConfig().SecretKey
+	needSignRequest := len(strings.TrimSpace(secretKey)) > 0
+	if needSignRequest {
+		return &signClientStream{ClientStream: stream, secretKey: secretKey}, nil
+	}
+
+	return stream, nil
+}
+
+func signMessage(message proto.Message, secretKey string) error {
+	m := message.ProtoReflect()
+	field := m.Descriptor().Fields().ByName(signField)
+	if field == nil {
+		return status.Errorf(codes.Internal, "sign interceptor: message doesn't support sign")
+	}
+
+	m.Clear(field)
+	body, err := proto.Marshal(message)
+	if err != nil {
+		return status.Errorf(codes.Internal, "sign interceptor: %s", err)
+	}
+
+	if len(body) > 0 {
+		sign := getSign(body, secretKey)
+		m.Set(field, protoreflect.ValueOfString(sign))
+	}
+
+	return nil
+}
+
 func getSign(value []byte, secretKey string) string {
 	h := hmac.New(sha256.New, []byte(secretKey))
 	h.Write(value)
@@ -9,10 +9,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	gcontext "gopkg.in/h2non/gentleman.v2/context"
 
 	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
@@ -72,8 +74,8 @@ func TestSignMiddleware(t *testing.T) {
 				Request: req,
 			}
 
-			agent := &Agent{
-				Config: &config.AgentConfig{
+			destination := &Destination{
+				Config: &config.DestinationConfig{
 					SecretKey: tt.agentSignKey,
 				},
 			}
@@ -81,7 +83,7 @@ func TestSignMiddleware(t *testing.T) {
 			mockHandler := &SignMockHandler{}
 			mockHandler.On("Next", ctx).Once()
 
-			agent.SignMiddleware(ctx, mockHandler)
+			destination.SignMiddleware(ctx, mockHandler)
 
 			gotSign := req.Header.Get(signKey)
 			assert.Equal(t, tt.expectedSign, gotSign, "request body sign should be: %s, got: %s", tt.expectedSign, gotSign)
@@ -135,7 +137,7 @@ func TestSignInterceptor(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			agent := &Agent{Config: &config.AgentConfig{SecretKey: tt.secretKey}}
+			destination := &Destination{Config: &config.DestinationConfig{SecretKey: tt.secretKey}}
 
 			ctx := context.Background()
 
@@ -149,7 +151,7 @@ func TestSignInterceptor(t *testing.T) {
 				return nil
 			}
 
-			err := agent.SignInterceptor(ctx, "method", tt.req, nil, nil, testInvoker)
+			err := destination.SignInterceptor(ctx, "method", tt.req, nil, nil, testInvoker)
 
 			if tt.expectedErr != nil {
 				assert.Equal(t, tt.expectedErr, err, "should return error: %s, but got: %s", tt.expectedErr, err)
@@ -159,3 +161,84 @@ func TestSignInterceptor(t *testing.T) {
 		})
 	}
 }
+
+type testClientStream struct {
+	grpc.ClientStream
+	sent []interface{}
+}
+
+func (s *testClientStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func TestSignStreamInterceptor(t *testing.T) {
+	secretKey := "secret-key"
+	newRequest := func() *pb.MetricsV1ServiceStreamMetricsRequest {
+		return &pb.MetricsV1ServiceStreamMetricsRequest{
+			Sequence: 1,
+			Metrics: []*pb.MetricData{{
+				Name:        "Alloc",
+				Type:        "gauge",
+				MetricValue: &pb.MetricData_Value{Value: 22.22},
+			}},
+		}
+	}
+	unsigned, err := proto.Marshal(newRequest())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		secretKey    string
+		req          interface{}
+		expectedErr  error
+		expectedSign string
+	}{
+		{
+			name:         "Valid stream message",
+			secretKey:    secretKey,
+			req:          newRequest(),
+			expectedErr:  nil,
+			expectedSign: getSign(unsigned, secretKey),
+		},
+		{
+			name:        "Stream message not proto.Message",
+			secretKey:   secretKey,
+			req:         "invalid request",
+			expectedErr: status.Errorf(codes.Internal, "sign interceptor: request isn't a proto.Message"),
+		},
+		{
+			name:         "No secret key in agent config",
+			secretKey:    "",
+			req:          newRequest(),
+			expectedErr:  nil,
+			expectedSign: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destination := &Destination{Config: &config.DestinationConfig{SecretKey: tt.secretKey}}
+			clientStream := &testClientStream{}
+
+			testStreamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+				opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return clientStream, nil
+			}
+
+			stream, err := destination.SignStreamInterceptor(context.Background(), &grpc.StreamDesc{}, nil, "method", testStreamer)
+			require.NoError(t, err, "shouldn`t return error, but got: %s", err)
+
+			err = stream.SendMsg(tt.req)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err, "should return error: %s, but got: %s", tt.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err, "shouldn`t return error, but got: %s", err)
+			require.Len(t, clientStream.sent, 1, "should send message")
+			sent := clientStream.sent[0].(*pb.MetricsV1ServiceStreamMetricsRequest)
+			assert.Equal(t, tt.expectedSign, sent.Sign, "sign does not match")
+		})
+	}
+}
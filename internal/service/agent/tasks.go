@@ -3,204 +3,527 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
-	"runtime"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/cenkalti/backoff/v4"
-	"github.com/shirou/gopsutil/v3/mem"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	collector "github.com/Stern-Ritter/metrics-and-alerting-service/internal/collector/agent"
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/errors"
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	cache "github.com/Stern-Ritter/metrics-and-alerting-service/internal/storage/agent"
 	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
 )
 
 const (
-	ipKey = "X-Real-IP"
+	ipKey            = "X-Real-IP"
+	agentIDKey       = "X-Agent-ID"
+	batchSequenceKey = "X-Batch-Sequence"
 )
 
-// UpdateRuntimeMetrics task that collects runtime metrics statistics and updates the cache.
-func (a *Agent) UpdateRuntimeMetrics() {
-	var ms runtime.MemStats
-	runtime.ReadMemStats(&ms)
-	a.RuntimeMonitor.Update(&ms)
-	a.Cache.UpdateRuntimeMonitorMetrics(a.RuntimeMonitor)
-
-	randomValue, _ := a.Random.Float(0.1, 99.99)
-	_, err := a.Cache.UpdateGaugeMetric(metrics.NewGauge("RandomValue", randomValue))
+// CollectMetrics task that collects metrics statistics with the collector and updates the cache.
+// The series that the collector no longer returns, for example of an unmounted filesystem or a removed
+// network interface, are removed from the cache after a successful run.
+// The duration of the collector run and its errors are reported in the self-telemetry metrics.
+func (a *Agent) CollectMetrics(ctx context.Context, c collector.Collector) {
+	start := time.Now()
+	ms, err := c.Collect(ctx)
+	a.telemetry.observeCollector(c.Name(), time.Since(start), err)
 	if err != nil {
-		a.Logger.Error(err.Error(), zap.String("event", "update RandomValue gauge metric"))
+		a.Logger.Error(err.Error(), zap.String("event", "collect metrics"), zap.String("collector", c.Name()))
+	}
+	if len(ms) > 0 {
+		a.Cache.UpdateMetrics(ms)
+	}
+	if stale := a.series.update(c.Name(), ms, err != nil); len(stale) > 0 {
+		a.Cache.RemoveMetrics(stale)
 	}
 }
 
-// UpdateUtilMetrics task that collects utilization metrics and updates the cache
-func (a *Agent) UpdateUtilMetrics() {
-	ms, err := mem.VirtualMemory()
-	if err != nil {
-		a.Logger.Error(err.Error(), zap.String("event", "update util gauge metrics"))
-		return
+// ValidateCollectors checks that the config doesn't refer to a collector that isn't registered.
+func (a *Agent) ValidateCollectors(cfg *config.AgentConfig) error {
+	for name := range cfg.Collectors {
+		if !a.Collectors.Has(name) {
+			return fmt.Errorf("unknown metrics collector: %s", name)
+		}
 	}
-	err = a.UtilMonitor.Update(ms)
-	if err != nil {
-		a.Logger.Error(err.Error(), zap.String("event", "update util gauge metrics"))
-		return
+	return nil
+}
+
+// StartCollectors declares the metrics of the enabled collectors in the cache and schedules
+// each collector at its poll interval with its timeout. It returns an error if the config refers to
+// a collector that isn't registered.
+func (a *Agent) StartCollectors(ctx context.Context, wg *sync.WaitGroup) error {
+	if err := a.ValidateCollectors(a.Config); err != nil {
+		return err
+	}
+
+	for _, c := range a.Collectors.Collectors() {
+		if !a.Config.IsCollectorEnabled(c.Name()) {
+			a.Logger.Info("Metrics collector is disabled", zap.String("event", "start collectors"),
+				zap.String("collector", c.Name()))
+			continue
+		}
+
+		c := c
+		a.Cache.Declare(c.Describe()...)
+		schedule := a.taskSchedule(a.Config.CollectorPollInterval(c.Name()))
+		schedule.Timeout = time.Duration(a.Config.CollectorTimeout(c.Name())) * time.Second
+		ScheduleTask(ctx, wg, func(ctx context.Context) { a.CollectMetrics(ctx, c) }, schedule)
 	}
-	a.Cache.UpdateUtilMonitorMetrics(a.UtilMonitor)
+
+	return nil
 }
 
-// SendMetrics task that gets all metrics from the cache, resets the PollCount counter metric
-// and sends the metrics statistics to the server.
-func (a *Agent) SendMetrics() {
-	gauges, counters := a.Cache.GetMetrics()
+// StartSendMetrics schedules sending metrics statistics at the report interval. Cancelling the context
+// doesn't interrupt the running SendMetrics task, so the taken counter metrics are always enqueued.
+func (a *Agent) StartSendMetrics(ctx context.Context, wg *sync.WaitGroup) {
+	ScheduleTask(ctx, wg, func(context.Context) { a.SendMetrics() }, a.taskSchedule(a.Config.SendMetricsInterval))
+}
 
-	err := a.Cache.ResetMetricValue(string(metrics.Counter), "PollCount")
-	if err != nil {
-		a.Logger.Error(err.Error(), zap.String("event", "reset PollCount counter metric"))
+// taskSchedule returns the schedule of the agent task run at the interval in seconds.
+func (a *Agent) taskSchedule(interval int) TaskSchedule {
+	return TaskSchedule{
+		Interval: time.Duration(interval) * time.Second,
+		Align:    a.Config.ScheduleAlign,
+		Jitter:   time.Duration(a.Config.ScheduleJitter) * time.Second,
 	}
+}
+
+// SendMetrics task that gets all metrics from the cache, resets the counter metrics, applies the relabel rules
+// and sends the metrics statistics with the agent self-telemetry metrics to each destination.
+// The batch sent to a destination also reports the sending of batches to it and the counts of batches dropped
+// and coalesced by it. The batches are reported to the destinations concurrently, so a destination with the block
+// backpressure policy delays the task, but not the reporting to the others. The batch is split
+// by the destination batch limits, and its parts are sent and retried by the workers independently.
+func (a *Agent) SendMetrics() {
+	gauges, counters := a.Cache.TakeMetrics()
+	aggregated := a.Cache.TakeAggregatedMetrics()
 
-	metricsBatch := make([]metrics.Metrics, 0, len(gauges)+len(counters))
+	metricsBatch := make([]metrics.Metrics, 0, len(gauges)+len(aggregated)+len(counters))
 	for _, gaugeMetric := range gauges {
 		metricsBatch = append(metricsBatch, metrics.GaugeMetricToMetrics(gaugeMetric))
 
 	}
+	for _, gaugeMetric := range aggregated {
+		metricsBatch = append(metricsBatch, metrics.GaugeMetricToMetrics(gaugeMetric))
+	}
 	for _, counterMetric := range counters {
 		metricsBatch = append(metricsBatch, metrics.CounterMetricToMetrics(counterMetric))
 	}
 
-	a.metricsCh <- metricsBatch
+	metricsBatch, merged := a.relabelRules.Apply(metricsBatch)
+	if len(merged) > 0 {
+		a.Logger.Warn("Relabelled metrics have the same names and were merged",
+			zap.String("event", "relabel metrics"), zap.Strings("metric names", merged))
+	}
+	metricsBatch = append(metricsBatch, a.telemetry.metrics(a.buildVersion)...)
+
+	var wg sync.WaitGroup
+	for _, d := range a.Destinations {
+		destinationBatch := append(metricsBatch[:len(metricsBatch):len(metricsBatch)], d.telemetryMetrics()...)
+		destinationBatch = append(destinationBatch, d.backpressureMetrics()...)
+
+		wg.Add(1)
+		go func(d *Destination, destinationBatch []metrics.Metrics) {
+			defer wg.Done()
+			d.reportMetricsBatch(destinationBatch)
+		}(d, destinationBatch)
+	}
+	wg.Wait()
+}
+
+// SendMetricsWorker is a worker function that sends metrics batches from the metrics channel
+// until the channel is closed or the worker is stopped by closing the quit channel.
+type SendMetricsWorker func(id int, metricsCh <-chan []metrics.Metrics, quitCh <-chan struct{}, wg *sync.WaitGroup)
+
+// sendMetricsWorkerPool holds the running send metrics workers of the destination.
+type sendMetricsWorkerPool struct {
+	mu      sync.Mutex
+	wg      *sync.WaitGroup
+	worker  SendMetricsWorker
+	quitChs []chan struct{}
+	lastID  int
 }
 
 // StartSendMetricsWorkerPool starts a pool of workers to send metrics statistics.
-func (a *Agent) StartSendMetricsWorkerPool(wg *sync.WaitGroup,
-	worker func(id int, metricsCh <-chan []metrics.Metrics, wg *sync.WaitGroup)) {
-	sendRateLimit := a.Config.RateLimit
-	if sendRateLimit <= 0 {
-		a.Logger.Error("Rate limit can't be less than or equal to zero",
+func (d *Destination) StartSendMetricsWorkerPool(wg *sync.WaitGroup, worker SendMetricsWorker) {
+	d.pool.mu.Lock()
+	defer d.pool.mu.Unlock()
+
+	d.pool.wg = wg
+	d.pool.worker = worker
+	d.resizeSendMetricsWorkerPool(d.sendMetricsWorkerPoolSize(d.CurrentConfig().RateLimit))
+
+	d.Logger.Debug("Worker pool started",
+		zap.String("event", "starting send metrics worker pool"))
+}
+
+// ResizeSendMetricsWorkerPool changes the number of send metrics workers. The stopped workers
+// finish sending their current batches.
+func (d *Destination) ResizeSendMetricsWorkerPool(size int) {
+	d.pool.mu.Lock()
+	defer d.pool.mu.Unlock()
+
+	d.resizeSendMetricsWorkerPool(d.sendMetricsWorkerPoolSize(size))
+
+	d.Logger.Debug("Worker pool resized", zap.Int("size", len(d.pool.quitChs)),
+		zap.String("event", "resizing send metrics worker pool"))
+}
+
+// ReplaceSendMetricsWorkerPool stops the send metrics workers and starts the same number of new workers.
+// The stopped workers finish sending their current batches.
+func (d *Destination) ReplaceSendMetricsWorkerPool(worker SendMetricsWorker) {
+	d.pool.mu.Lock()
+	defer d.pool.mu.Unlock()
+
+	size := len(d.pool.quitChs)
+	d.resizeSendMetricsWorkerPool(0)
+	d.pool.worker = worker
+	d.resizeSendMetricsWorkerPool(size)
+
+	d.Logger.Debug("Worker pool replaced",
+		zap.String("event", "replacing send metrics worker pool"))
+}
+
+// sendMetricsWorkerPoolSize returns the number of send metrics workers for the rate limit.
+func (d *Destination) sendMetricsWorkerPoolSize(rateLimit int) int {
+	if rateLimit <= 0 {
+		d.Logger.Error("Rate limit can't be less than or equal to zero",
 			zap.String("event", "start send metrics worker pool"))
-		sendRateLimit = 1
+		return 1
 	}
+	return rateLimit
+}
 
-	for w := 1; w <= sendRateLimit; w++ {
-		go worker(w, a.metricsCh, wg)
-		wg.Add(1)
+func (d *Destination) resizeSendMetricsWorkerPool(size int) {
+	for len(d.pool.quitChs) < size {
+		d.pool.lastID++
+		quitCh := make(chan struct{})
+		d.pool.quitChs = append(d.pool.quitChs, quitCh)
+		d.pool.wg.Add(1)
+		go d.pool.worker(d.pool.lastID, d.metricsCh, quitCh, d.pool.wg)
 	}
 
-	a.Logger.Debug("Worker pool started",
-		zap.String("event", "starting send metrics worker pool"))
+	for len(d.pool.quitChs) > size {
+		last := len(d.pool.quitChs) - 1
+		close(d.pool.quitChs[last])
+		d.pool.quitChs = d.pool.quitChs[:last]
+	}
+}
+
+// nextMetricsBatch waits for the next metrics batch for the send metrics worker. It returns false
+// when the metrics channel is closed or the worker is stopped.
+func nextMetricsBatch(metricsCh <-chan []metrics.Metrics, quitCh <-chan struct{}) ([]metrics.Metrics, bool) {
+	select {
+	case <-quitCh:
+		return nil, false
+	case metricsBatch, ok := <-metricsCh:
+		return metricsBatch, ok
+	}
 }
 
 // SendMetricsWithHTTPWorker is a worker function that sends metrics using HTTP.
-func (a *Agent) SendMetricsWithHTTPWorker(id int, metricsCh <-chan []metrics.Metrics, wg *sync.WaitGroup) {
-	a.Logger.Debug("Worker started", zap.Int("worker id", id),
+func (d *Destination) SendMetricsWithHTTPWorker(id int, metricsCh <-chan []metrics.Metrics,
+	quitCh <-chan struct{}, wg *sync.WaitGroup) {
+	d.Logger.Debug("Worker started", zap.Int("worker id", id),
 		zap.String("event", "starting send metrics worker"))
 
-	for metricsBatch := range metricsCh {
+	send := func(key metrics.BatchKey, metricsBatch []metrics.Metrics) backoff.Operation {
 		body, err := json.Marshal(metricsBatch)
 		if err != nil {
-			a.Logger.Error(err.Error(), zap.Int("worker id", id),
-				zap.String("event", "JSON encoding metrics"))
-			continue
+			return func() error { return rejectBatch(err) }
 		}
 
 		ipAddr, err := getIPAddr()
 		if err != nil {
-			a.Logger.Error(err.Error(), zap.Int("worker id", id),
+			d.Logger.Error(err.Error(), zap.Int("worker id", id),
 				zap.String("event", "get ip address"))
 		}
 		headers := map[string]string{
 			"Content-Type": "application/json",
 			ipKey:          ipAddr,
 		}
-		sendMetricsBatch := func() error {
-			resp, err := sendPostRequest(a.HTTPClient, a.Config.SendMetricsEndPoint, headers, body)
+		if !key.IsZero() {
+			headers[agentIDKey] = key.AgentID
+			headers[batchSequenceKey] = strconv.FormatUint(key.Sequence, 10)
+		}
+
+		return func() error {
+			endpoint := d.CurrentConfig().SendMetricsEndPoint
+			resp, err := sendPostRequest(d.httpClient(), endpoint, headers, body)
 			if err == nil && !resp.Ok {
-				return errors.NewUnsuccessRequestProcessing(fmt.Sprintf("unsuccess request sent on url: %s, status code: %d",
-					a.Config.SendMetricsEndPoint, resp.StatusCode), nil)
+				err = errors.NewUnsuccessRequestProcessing(fmt.Sprintf("unsuccess request sent on url: %s, status code: %d",
+					endpoint, resp.StatusCode), nil)
+				if isRejectedHTTPStatus(resp.StatusCode) {
+					return rejectBatch(err)
+				}
+				return err
 			} else if err != nil {
+				if isRejectedSendError(err) {
+					return rejectBatch(err)
+				}
 				return backoff.Permanent(err)
 			}
 
 			return nil
 		}
+	}
 
-		if sendErr := backoff.Retry(sendMetricsBatch, a.sendMetricsBatchRetryIntervals); sendErr != nil {
-			a.Logger.Error(sendErr.Error(), zap.Int("worker id", id),
-				zap.String("event", "sending metrics update"))
-			continue
+	for {
+		metricsBatch, ok := nextMetricsBatch(metricsCh, quitCh)
+		if !ok {
+			break
 		}
-
-		a.Logger.Debug("Success sent metrics update", zap.Int("worker id", id),
-			zap.String("event", "sending metrics update"))
+		d.sendMetricsBatch(id, metricsBatch, send)
 	}
 
-	a.Logger.Debug("Worker stopped", zap.Int("worker id", id),
+	d.Logger.Debug("Worker stopped", zap.Int("worker id", id),
 		zap.String("event", "stopping send metrics worker"))
 	wg.Done()
 }
 
-// SendMetricsWithGrpcWorker is a worker function that sends metrics using gRPC.
-func (a *Agent) SendMetricsWithGrpcWorker(id int, metricsCh <-chan []metrics.Metrics, wg *sync.WaitGroup) {
-	a.Logger.Debug("Worker started", zap.Int("worker id", id),
+// SendMetricsWithGrpcStreamWorker is a worker function that sends metrics over a long-lived
+// bidirectional gRPC stream. Each batch is sent with its batch key sequence number
+// and acknowledged by the server with it.
+// The stream is reopened on the next attempt when sending or receiving fails, the batch isn't acknowledged
+// within the send metrics timeout or the gRPC client of the destination is replaced. The batch acknowledged
+// with an error is retried or dropped by its error code, keeping the stream open.
+func (d *Destination) SendMetricsWithGrpcStreamWorker(id int, metricsCh <-chan []metrics.Metrics,
+	quitCh <-chan struct{}, wg *sync.WaitGroup) {
+	d.Logger.Debug("Worker started", zap.Int("worker id", id),
 		zap.String("event", "starting send metrics worker"))
 
-	for metricsBatch := range metricsCh {
-		metricsData := metrics.MetricsToRepeatedMetricData(metricsBatch)
+	ipAddr, err := getIPAddr()
+	if err != nil {
+		d.Logger.Error(err.Error(), zap.Int("worker id", id),
+			zap.String("event", "get ip address"))
+	}
+	md := metadata.Pairs(ipKey, ipAddr)
+	if len(d.agentID) > 0 {
+		md.Append(agentIDKey, d.agentID)
+	}
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(context.Background(), md))
+	defer cancel()
 
-		ipAddr, err := getIPAddr()
-		if err != nil {
-			a.Logger.Error(err.Error(), zap.Int("worker id", id),
-				zap.String("event", "get ip address"))
+	var stream pb.MetricsV1Service_StreamMetricsClient
+	var streamClient pb.MetricsV1ServiceClient
+	var cancelStream context.CancelFunc
+
+	closeStream := func() {
+		if stream == nil {
+			return
+		}
+		if err := stream.CloseSend(); err != nil {
+			d.Logger.Error(err.Error(), zap.Int("worker id", id),
+				zap.String("event", "closing metrics stream"))
 		}
-		md := metadata.Pairs(ipKey, ipAddr)
-		ctx := metadata.NewOutgoingContext(context.Background(), md)
+		cancelStream()
+		stream = nil
+	}
 
-		sendMetricData := func() error {
-			updateMetricsBatchRequest := &pb.MetricsV1ServiceUpdateMetricsBatchRequest{
-				Metrics: metricsData,
+	send := func(key metrics.BatchKey, metricsBatch []metrics.Metrics) backoff.Operation {
+		streamMetricsRequest := &pb.MetricsV1ServiceStreamMetricsRequest{
+			Sequence: key.Sequence,
+			Metrics:  metrics.MetricsToRepeatedMetricData(metricsBatch),
+		}
+
+		return func() error {
+			client := d.grpcClient()
+			if stream != nil && streamClient != client {
+				closeStream()
+			}
+			if stream == nil {
+				streamCtx, streamCancel := context.WithCancel(ctx)
+				s, err := client.StreamMetrics(streamCtx)
+				if err != nil {
+					streamCancel()
+					return d.grpcSendError(err)
+				}
+				stream = s
+				streamClient = client
+				cancelStream = streamCancel
+			}
+
+			// The stream is cancelled when the batch isn't acknowledged in time, which unblocks Send and Recv.
+			// The timer cancels the stream the batch is sent over, even if it fires after the stream is replaced.
+			var expired atomic.Bool
+			if d.sendMetricsTimeout > 0 {
+				cancelBatchStream := cancelStream
+				timer := time.AfterFunc(d.sendMetricsTimeout, func() {
+					expired.Store(true)
+					cancelBatchStream()
+				})
+				defer timer.Stop()
+			}
+
+			var resp *pb.MetricsV1ServiceStreamMetricsResponse
+			err := stream.Send(streamMetricsRequest)
+			if err == nil {
+				resp, err = stream.Recv()
+				if err == nil && resp.Sequence != streamMetricsRequest.Sequence {
+					err = status.Errorf(codes.Unavailable, "unexpected ack sequence: %d, expected: %d",
+						resp.Sequence, streamMetricsRequest.Sequence)
+				}
 			}
-			_, err := a.GRPCClient.UpdateMetricsBatch(ctx, updateMetricsBatchRequest)
 			if err != nil {
-				if e, ok := status.FromError(err); ok {
-					if e.Code() == codes.Unavailable || e.Code() == codes.DeadlineExceeded {
-						return errors.NewUnsuccessRequestProcessing(
-							fmt.Sprintf("unsuccess request sent on url: %s, status code: %d",
-								a.Config.SendMetricsEndPoint, e.Code()), nil)
-					} else {
-						return backoff.Permanent(errors.NewUnsuccessRequestProcessing(
-							fmt.Sprintf("unsuccess request sent on url: %s, status code: %d",
-								a.Config.SendMetricsEndPoint, e.Code()), nil))
-					}
+				if expired.Load() {
+					err = status.Errorf(codes.DeadlineExceeded, "metrics batch isn't acknowledged in %s",
+						d.sendMetricsTimeout)
 				}
-				return errors.NewUnsuccessRequestProcessing(
-					fmt.Sprintf("unsuccess request sent on url: %s, error parsing status code: %s",
-						a.Config.SendMetricsEndPoint, err), nil)
+				closeStream()
+				return d.grpcSendError(err)
+			}
+
+			if codes.Code(resp.Code) != codes.OK {
+				return d.grpcSendError(status.Error(codes.Code(resp.Code), resp.Error))
 			}
 
 			return nil
 		}
+	}
 
-		if sendErr := backoff.Retry(sendMetricData, a.sendMetricsBatchRetryIntervals); sendErr != nil {
-			a.Logger.Error(sendErr.Error(), zap.Int("worker id", id),
-				zap.String("event", "sending metrics update"))
-			continue
+	for {
+		metricsBatch, ok := nextMetricsBatch(metricsCh, quitCh)
+		if !ok {
+			break
 		}
-
-		a.Logger.Debug("Success sent metrics update", zap.Int("worker id", id),
-			zap.String("event", "sending metrics update"))
+		d.sendMetricsBatch(id, metricsBatch, send)
 	}
 
-	a.Logger.Debug("Worker stopped", zap.Int("worker id", id),
+	closeStream()
+
+	d.Logger.Debug("Worker stopped", zap.Int("worker id", id),
 		zap.String("event", "stopping send metrics worker"))
 	wg.Done()
 }
 
-// StopSendMetricsWorkerPool stops send metrics workers
-func (a *Agent) StopSendMetricsWorkerPool() {
-	a.Logger.Debug("Worker pool stopped",
+// sendMetricsBatch assigns the key to the metrics batch and sends it with the operation returned by the send
+// function, retrying it at the send metrics batch retry intervals. The server applies the batch with the same key
+// only once, so retries don't update counter metrics twice. When the spool is enabled, the spooled batches
+// are replayed with their keys before the batch to keep the order of batches, and the batch is spooled
+// when it fails to be sent. While the spooled batches can't be replayed or are being replayed by another worker,
+// new batches are spooled without sending. The batches rejected by the server are dropped instead of spooling,
+// so they don't hold back the following ones.
+func (d *Destination) sendMetricsBatch(id int, metricsBatch []metrics.Metrics,
+	send func(metrics.BatchKey, []metrics.Metrics) backoff.Operation) {
+	key := d.nextBatchKey()
+
+	if d.Spool != nil {
+		replay := func(spooledKey metrics.BatchKey, spooledBatch []metrics.Metrics) error {
+			err := d.timeSend(send(spooledKey, spooledBatch))()
+			if err != nil && isRejectedBatch(err) {
+				d.Logger.Error(err.Error(), zap.Int("worker id", id),
+					zap.String("event", "replaying spooled metrics"), zap.Bool("dropped", true))
+				d.failedBatches.Add(1)
+				return nil
+			}
+			return err
+		}
+		if err := d.Spool.Replay(replay); err != nil {
+			if stderrors.Is(err, cache.ErrSpoolReplaying) {
+				d.spoolMetricsBatch(id, key, metricsBatch)
+				return
+			}
+			d.Logger.Error(err.Error(), zap.Int("worker id", id),
+				zap.String("event", "replaying spooled metrics"))
+			d.failedBatches.Add(1)
+			d.spoolMetricsBatch(id, key, metricsBatch)
+			return
+		}
+	}
+
+	retried := false
+	notify := func(err error, _ time.Duration) {
+		retried = true
+	}
+	sendErr := backoff.RetryNotify(d.timeSend(send(key, metricsBatch)), d.sendMetricsBatchRetryIntervals, notify)
+	if retried {
+		d.retriedBatches.Add(1)
+	}
+	if sendErr != nil {
+		rejected := isRejectedBatch(sendErr)
+		d.Logger.Error(sendErr.Error(), zap.Int("worker id", id),
+			zap.String("event", "sending metrics update"), zap.Bool("dropped", rejected))
+		d.failedBatches.Add(1)
+		if !rejected {
+			d.spoolMetricsBatch(id, key, metricsBatch)
+		}
+		return
+	}
+
+	d.Logger.Debug("Success sent metrics update", zap.Int("worker id", id),
+		zap.String("event", "sending metrics update"))
+}
+
+// timeSend wraps the send operation to count the sent batches and record the latency of successful sends.
+func (d *Destination) timeSend(operation backoff.Operation) backoff.Operation {
+	return func() error {
+		start := time.Now()
+		err := operation()
+		if err == nil {
+			d.sentBatches.Add(1)
+			d.observeSendLatency(time.Since(start))
+		}
+		return err
+	}
+}
+
+// spoolMetricsBatch writes the metrics batch that failed to be sent to the spool, if it's enabled.
+func (d *Destination) spoolMetricsBatch(id int, key metrics.BatchKey, metricsBatch []metrics.Metrics) {
+	if d.Spool == nil {
+		return
+	}
+
+	if err := d.Spool.Write(key, metricsBatch); err != nil {
+		d.Logger.Error(err.Error(), zap.Int("worker id", id),
+			zap.String("event", "spooling metrics update"))
+		return
+	}
+
+	d.Logger.Debug("Metrics update spooled", zap.Int("worker id", id),
+		zap.String("event", "spooling metrics update"))
+}
+
+// grpcSendError converts a gRPC error to a send metrics error. Unavailable and DeadlineExceeded
+// errors are retried, the batches rejected as invalid aren't spooled, other status codes are returned
+// as permanent errors.
+func (d *Destination) grpcSendError(err error) error {
+	endpoint := d.CurrentConfig().SendMetricsEndPoint
+	if e, ok := status.FromError(err); ok {
+		if isRejectedGRPCCode(e.Code()) {
+			return rejectBatch(errors.NewUnsuccessRequestProcessing(
+				fmt.Sprintf("unsuccess request sent on url: %s, status code: %d",
+					endpoint, e.Code()), nil))
+		}
+		if e.Code() == codes.Unavailable || e.Code() == codes.DeadlineExceeded {
+			return errors.NewUnsuccessRequestProcessing(
+				fmt.Sprintf("unsuccess request sent on url: %s, status code: %d",
+					endpoint, e.Code()), nil)
+		} else {
+			return backoff.Permanent(errors.NewUnsuccessRequestProcessing(
+				fmt.Sprintf("unsuccess request sent on url: %s, status code: %d",
+					endpoint, e.Code()), nil))
+		}
+	}
+	return errors.NewUnsuccessRequestProcessing(
+		fmt.Sprintf("unsuccess request sent on url: %s, error parsing status code: %s",
+			endpoint, err), nil)
+}
+
+// StopSendMetricsWorkerPool stops send metrics workers after they send the enqueued batches
+func (d *Destination) StopSendMetricsWorkerPool() {
+	close(d.stopEnqueueCh)
+	<-d.enqueueDoneCh
+	d.Logger.Debug("Worker pool stopped",
 		zap.String("event", "starting send metrics worker pool"))
-	close(a.metricsCh)
 }
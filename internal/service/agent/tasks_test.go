@@ -1,10 +1,14 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -22,12 +26,11 @@ import (
 	"google.golang.org/grpc/status"
 	"gopkg.in/h2non/gentleman.v2"
 
+	collector "github.com/Stern-Ritter/metrics-and-alerting-service/internal/collector/agent"
 	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
 	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
-	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/monitors"
 	cache "github.com/Stern-Ritter/metrics-and-alerting-service/internal/storage/agent"
-	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
 	mocks "github.com/Stern-Ritter/metrics-and-alerting-service/mocks"
 	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
 )
@@ -37,8 +40,12 @@ type MockAgentMemCache struct {
 	mock.Mock
 }
 
-func (c *MockAgentMemCache) UpdateRuntimeMonitorMetrics(model *monitors.RuntimeMonitor) {
-	c.Called(model)
+func (c *MockAgentMemCache) UpdateMetrics(ms []metrics.Metrics) {
+	c.Called(ms)
+}
+
+func (c *MockAgentMemCache) Declare(descriptors ...metrics.Descriptor) {
+	c.Called(descriptors)
 }
 
 func (c *MockAgentMemCache) UpdateGaugeMetric(metric metrics.GaugeMetric) (metrics.GaugeMetric, error) {
@@ -51,29 +58,146 @@ func (c *MockAgentMemCache) ResetMetricValue(metricType, metricName string) erro
 	return args.Error(0)
 }
 
-func TestUpdateRuntimeMetrics(t *testing.T) {
-	t.Run("should update monitor metrics and 'RandomValue' gauge metric once", func(t *testing.T) {
-		aLogger, err := logger.Initialize("info")
-		require.NoError(t, err, "Error init logger")
-		mockAgentMemCache := MockAgentMemCache{
-			AgentMemCache: cache.NewAgentMemCache(make(map[string]metrics.GaugeMetric),
-				make(map[string]metrics.CounterMetric), aLogger),
+type testCollector struct {
+	name    string
+	metrics []metrics.Metrics
+	err     error
+	calls   atomic.Int64
+}
+
+func (c *testCollector) Name() string {
+	return c.name
+}
+
+func (c *testCollector) Describe() []metrics.Descriptor {
+	descriptors := make([]metrics.Descriptor, 0, len(c.metrics))
+	for _, m := range c.metrics {
+		descriptors = append(descriptors, metrics.Descriptor{Name: m.ID, Type: metrics.MetricType(m.MType)})
+	}
+	return descriptors
+}
+
+func (c *testCollector) Collect(_ context.Context) ([]metrics.Metrics, error) {
+	c.calls.Add(1)
+	return c.metrics, c.err
+}
+
+func TestCollectMetrics(t *testing.T) {
+	value := 1.5
+	ms := []metrics.Metrics{{ID: "TestGauge", MType: string(metrics.Gauge), Value: &value}}
+
+	tests := []struct {
+		name             string
+		collector        *testCollector
+		updateCallsCount int
+		errorLogsCount   int
+	}{
+		{
+			name:             "should update cache with collected metrics",
+			collector:        &testCollector{name: "test", metrics: ms},
+			updateCallsCount: 1,
+			errorLogsCount:   0,
+		},
+		{
+			name:             "should update cache with partially collected metrics and log error",
+			collector:        &testCollector{name: "test", metrics: ms, err: errors.New("collect error")},
+			updateCallsCount: 1,
+			errorLogsCount:   1,
+		},
+		{
+			name:             "should not update cache when collector returns no metrics",
+			collector:        &testCollector{name: "test", err: errors.New("collect error")},
+			updateCallsCount: 0,
+			errorLogsCount:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, recorded := observer.New(zapcore.ErrorLevel)
+			aLogger := &logger.AgentLogger{Logger: zap.New(core)}
+			mockAgentMemCache := MockAgentMemCache{}
+			mockAgentMemCache.On("UpdateMetrics", mock.Anything).Return()
+			agent := NewAgent(&mockAgentMemCache, nil, &config.AgentConfig{}, aLogger)
+
+			agent.CollectMetrics(context.Background(), tt.collector)
+
+			mockAgentMemCache.AssertNumberOfCalls(t, "UpdateMetrics", tt.updateCallsCount)
+			assert.Equal(t, tt.errorLogsCount, recorded.Len(), "should log collect errors")
+		})
+	}
+}
+
+func TestCollectMetricsRemovesStaleSeries(t *testing.T) {
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+	memCache := cache.NewAgentMemCache(make(map[string]metrics.GaugeMetric), make(map[string]metrics.CounterMetric), aLogger)
+	memCache.Declare(metrics.NewPrefixDescriptor("DiskUsed{", metrics.Gauge))
+	agent := NewAgent(&memCache, nil, &config.AgentConfig{}, aLogger)
+
+	value := 40.0
+	root := metrics.Metrics{ID: "DiskUsed{mountpoint=/}", MType: string(metrics.Gauge), Value: &value}
+	mnt := metrics.Metrics{ID: "DiskUsed{mountpoint=/mnt}", MType: string(metrics.Gauge), Value: &value}
+	c := &testCollector{name: "disk", metrics: []metrics.Metrics{root, mnt}}
+
+	agent.CollectMetrics(context.Background(), c)
+	c.metrics, c.err = []metrics.Metrics{root}, errors.New("collect error")
+	agent.CollectMetrics(context.Background(), c)
+	gauges, _ := memCache.GetMetrics()
+	assert.Contains(t, gauges, mnt.ID, "shouldn't remove series not returned by failed collector run")
+
+	c.err = nil
+	agent.CollectMetrics(context.Background(), c)
+	gauges, _ = memCache.GetMetrics()
+	assert.Contains(t, gauges, root.ID, "should keep collected series")
+	assert.NotContains(t, gauges, mnt.ID, "should remove series no longer returned by collector")
+}
+
+func TestStartCollectors(t *testing.T) {
+	aLogger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	value := 1.5
+	ms := []metrics.Metrics{{ID: "TestGauge", MType: string(metrics.Gauge), Value: &value}}
+	disabled := false
+
+	t.Run("should start enabled collectors only", func(t *testing.T) {
+		enabledCollector := &testCollector{name: "enabled", metrics: ms}
+		disabledCollector := &testCollector{name: "disabled", metrics: ms}
+		registry, err := collector.NewRegistry(enabledCollector, disabledCollector)
+		require.NoError(t, err)
+
+		mockAgentMemCache := MockAgentMemCache{}
+		mockAgentMemCache.On("Declare", mock.Anything).Return()
+		mockAgentMemCache.On("UpdateMetrics", mock.Anything).Return()
+		cfg := config.AgentConfig{
+			UpdateMetricsInterval: 1,
+			Collectors:            map[string]config.CollectorConfig{"disabled": {Enabled: &disabled}},
 		}
-		runtimeMonitor := monitors.RuntimeMonitor{}
-		utilMonitor := monitors.UtilMonitor{}
-		mockRandom := utils.NewRandom()
-		cfg := config.AgentConfig{}
-		agent := NewAgent(&mockAgentMemCache, &runtimeMonitor, &utilMonitor, &mockRandom, &cfg, nil, aLogger)
+		agent := NewAgent(&mockAgentMemCache, registry, &cfg, aLogger)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		wg := sync.WaitGroup{}
+		err = agent.StartCollectors(ctx, &wg)
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return enabledCollector.calls.Load() > 0 }, time.Second, 10*time.Millisecond)
+		cancel()
+		wg.Wait()
+
+		assert.Equal(t, int64(0), disabledCollector.calls.Load(), "should not start disabled collector")
+		mockAgentMemCache.AssertCalled(t, "Declare", enabledCollector.Describe())
+		mockAgentMemCache.AssertNumberOfCalls(t, "Declare", 1)
+	})
 
-		mockAgentMemCache.On("UpdateRuntimeMonitorMetrics", &runtimeMonitor).Return(nil)
-		mockAgentMemCache.On("UpdateGaugeMetric", mock.Anything).Return(metrics.GaugeMetric{}, nil)
+	t.Run("should return error for unknown collector in config", func(t *testing.T) {
+		registry, err := collector.NewRegistry(&testCollector{name: "known"})
+		require.NoError(t, err)
 
-		agent.UpdateRuntimeMetrics()
+		cfg := config.AgentConfig{
+			Collectors: map[string]config.CollectorConfig{"unknown": {}},
+		}
+		agent := NewAgent(&MockAgentMemCache{}, registry, &cfg, aLogger)
 
-		assert.True(t, mockAgentMemCache.AssertNumberOfCalls(t, "UpdateRuntimeMonitorMetrics", 1),
-			"should update monitor metrics once")
-		assert.True(t, mockAgentMemCache.AssertNumberOfCalls(t, "UpdateGaugeMetric", 1),
-			"should update 'RandomValue' gauge metric once")
+		err = agent.StartCollectors(context.Background(), &sync.WaitGroup{})
+		assert.Error(t, err)
 	})
 }
 
@@ -81,7 +205,7 @@ func TestStartSendMetricsWorkerPool(t *testing.T) {
 	workersCount := 3
 	workersCounter := atomic.Int64{}
 
-	cfg := config.AgentConfig{
+	cfg := config.DestinationConfig{
 		RateLimit: workersCount,
 	}
 	core, recorded := observer.New(zapcore.DebugLevel)
@@ -89,19 +213,19 @@ func TestStartSendMetricsWorkerPool(t *testing.T) {
 	aLogger := &logger.AgentLogger{Logger: observerLogger}
 	metricsCh := make(chan []metrics.Metrics, 10)
 
-	agent := &Agent{
+	destination := &Destination{
 		Config:    &cfg,
 		Logger:    aLogger,
 		metricsCh: metricsCh,
 	}
 
 	wg := &sync.WaitGroup{}
-	worker := func(id int, metricsCh <-chan []metrics.Metrics, wg *sync.WaitGroup) {
+	worker := func(id int, metricsCh <-chan []metrics.Metrics, quitCh <-chan struct{}, wg *sync.WaitGroup) {
 		workersCounter.Add(1)
 		wg.Done()
 	}
 
-	agent.StartSendMetricsWorkerPool(wg, worker)
+	destination.StartSendMetricsWorkerPool(wg, worker)
 
 	wg.Wait()
 
@@ -113,8 +237,49 @@ func TestStartSendMetricsWorkerPool(t *testing.T) {
 		"counter should be equal: %d, after %d workers have increased it by 1", workersCount, workersCounter.Load())
 }
 
+func TestResizeSendMetricsWorkerPool(t *testing.T) {
+	destination := NewDestination(&config.DestinationConfig{RateLimit: 1}, &config.AgentConfig{}, nil,
+		&logger.AgentLogger{Logger: zap.NewNop()})
+
+	running := atomic.Int64{}
+	replaced := atomic.Int64{}
+	newWorker := func(counter *atomic.Int64) SendMetricsWorker {
+		return func(id int, metricsCh <-chan []metrics.Metrics, quitCh <-chan struct{}, wg *sync.WaitGroup) {
+			defer wg.Done()
+			counter.Add(1)
+			defer counter.Add(-1)
+			for {
+				if _, ok := nextMetricsBatch(metricsCh, quitCh); !ok {
+					return
+				}
+			}
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+	destination.StartSendMetricsWorkerPool(wg, newWorker(&running))
+	assert.Eventually(t, func() bool { return running.Load() == 1 }, time.Second, time.Millisecond,
+		"should start the rate limit workers")
+
+	destination.ResizeSendMetricsWorkerPool(3)
+	assert.Eventually(t, func() bool { return running.Load() == 3 }, time.Second, time.Millisecond,
+		"should start the added workers")
+
+	destination.ResizeSendMetricsWorkerPool(2)
+	assert.Eventually(t, func() bool { return running.Load() == 2 }, time.Second, time.Millisecond,
+		"should stop the removed workers")
+
+	destination.ReplaceSendMetricsWorkerPool(newWorker(&replaced))
+	assert.Eventually(t, func() bool { return running.Load() == 0 && replaced.Load() == 2 }, time.Second,
+		time.Millisecond, "should replace the workers keeping the pool size")
+
+	destination.StopSendMetricsWorkerPool()
+	wg.Wait()
+	assert.Equal(t, int64(0), replaced.Load(), "should stop the workers when the metrics channel is closed")
+}
+
 func TestSendMetricsWithHTTPWorker_OkResponse(t *testing.T) {
-	cfg := config.AgentConfig{
+	cfg := config.DestinationConfig{
 		SendMetricsEndPoint: "/metrics",
 	}
 
@@ -156,7 +321,7 @@ func TestSendMetricsWithHTTPWorker_OkResponse(t *testing.T) {
 	client := gentleman.New()
 	client.URL(ts.URL)
 
-	agent := &Agent{
+	destination := &Destination{
 		Config:                         &cfg,
 		Logger:                         aLogger,
 		metricsCh:                      metricsCh,
@@ -167,7 +332,7 @@ func TestSendMetricsWithHTTPWorker_OkResponse(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	worker := func() {
-		agent.SendMetricsWithHTTPWorker(1, metricsCh, &wg)
+		destination.SendMetricsWithHTTPWorker(1, metricsCh, nil, &wg)
 	}
 
 	go worker()
@@ -194,7 +359,7 @@ func TestSendMetricsWithHTTPWorker_OkResponse(t *testing.T) {
 }
 
 func TestSendMetricsWithHTTPWorker_ErrorResponse(t *testing.T) {
-	cfg := config.AgentConfig{
+	cfg := config.DestinationConfig{
 		SendMetricsEndPoint: "/metrics",
 	}
 
@@ -236,7 +401,7 @@ func TestSendMetricsWithHTTPWorker_ErrorResponse(t *testing.T) {
 	client := gentleman.New()
 	client.URL(ts.URL)
 
-	agent := &Agent{
+	destination := &Destination{
 		Config:                         &cfg,
 		Logger:                         aLogger,
 		metricsCh:                      metricsCh,
@@ -247,7 +412,7 @@ func TestSendMetricsWithHTTPWorker_ErrorResponse(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	worker := func() {
-		agent.SendMetricsWithHTTPWorker(1, metricsCh, &wg)
+		destination.SendMetricsWithHTTPWorker(1, metricsCh, nil, &wg)
 	}
 
 	go worker()
@@ -273,7 +438,129 @@ func TestSendMetricsWithHTTPWorker_ErrorResponse(t *testing.T) {
 		"should log info about the stopping worker")
 }
 
-func TestSendMetricsWithGrpcWorker_OkResponse(t *testing.T) {
+func TestSendMetricsWithHTTPWorker_Spool(t *testing.T) {
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+
+	var requests atomic.Int32
+	var receivedMu sync.Mutex
+	received := make([]string, 0)
+	sequences := make([]string, 0)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "agent", r.Header.Get(agentIDKey), "should send agent id header")
+
+		receivedMu.Lock()
+		sequences = append(sequences, r.Header.Get(batchSequenceKey))
+		receivedMu.Unlock()
+
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		gotMetrics := make([]metrics.Metrics, 0)
+		err := json.NewDecoder(r.Body).Decode(&gotMetrics)
+		assert.NoError(t, err, "unexpected error unmarshalling body")
+
+		receivedMu.Lock()
+		received = append(received, gotMetrics[0].ID)
+		receivedMu.Unlock()
+	}))
+	defer ts.Close()
+
+	client := gentleman.New()
+	client.URL(ts.URL)
+
+	spool, err := cache.NewSpool(t.TempDir(), 1024*1024, time.Hour, 0, aLogger)
+	require.NoError(t, err)
+
+	metricsCh := make(chan []metrics.Metrics, 10)
+	destination := &Destination{
+		Config:                         &config.DestinationConfig{SendMetricsEndPoint: "/metrics"},
+		agentID:                        "agent",
+		Logger:                         aLogger,
+		Spool:                          spool,
+		metricsCh:                      metricsCh,
+		HTTPClient:                     client,
+		sendMetricsBatchRetryIntervals: backoff.NewExponentialBackOff(backoff.WithMaxElapsedTime(1 * time.Millisecond)),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go destination.SendMetricsWithHTTPWorker(1, metricsCh, nil, &wg)
+
+	firstValue, secondValue := 1.0, 2.0
+	metricsCh <- []metrics.Metrics{{ID: "First", MType: "gauge", Value: &firstValue}}
+	metricsCh <- []metrics.Metrics{{ID: "Second", MType: "gauge", Value: &secondValue}}
+	close(metricsCh)
+
+	wg.Wait()
+
+	assert.Equal(t, []string{"First", "Second"}, received,
+		"should replay the spooled batch before the next batch")
+	assert.Equal(t, []string{"1", "1", "2"}, sequences,
+		"should replay the spooled batch with its original batch key")
+	assert.Equal(t, int64(0), spool.Size(), "should remove the replayed batch from the spool")
+}
+
+func TestSendMetricsWithHTTPWorker_RejectedBatch(t *testing.T) {
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+
+	var receivedMu sync.Mutex
+	received := make([]string, 0)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMetrics := make([]metrics.Metrics, 0)
+		err := json.NewDecoder(r.Body).Decode(&gotMetrics)
+		assert.NoError(t, err, "unexpected error unmarshalling body")
+
+		receivedMu.Lock()
+		received = append(received, gotMetrics[0].ID)
+		receivedMu.Unlock()
+
+		if strings.HasPrefix(gotMetrics[0].ID, "Invalid") {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	client := gentleman.New()
+	client.URL(ts.URL)
+
+	spool, err := cache.NewSpool(t.TempDir(), 1024*1024, time.Hour, 0, aLogger)
+	require.NoError(t, err)
+	spooledValue := 0.0
+	err = spool.Write(metrics.BatchKey{AgentID: "agent", Sequence: 1},
+		[]metrics.Metrics{{ID: "InvalidSpooled", MType: "gauge", Value: &spooledValue}})
+	require.NoError(t, err)
+
+	metricsCh := make(chan []metrics.Metrics, 10)
+	destination := &Destination{
+		Config:                         &config.DestinationConfig{SendMetricsEndPoint: "/metrics"},
+		agentID:                        "agent",
+		Logger:                         aLogger,
+		Spool:                          spool,
+		metricsCh:                      metricsCh,
+		HTTPClient:                     client,
+		sendMetricsBatchRetryIntervals: backoff.NewExponentialBackOff(backoff.WithMaxElapsedTime(1 * time.Millisecond)),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go destination.SendMetricsWithHTTPWorker(1, metricsCh, nil, &wg)
+
+	firstValue, secondValue := 1.0, 2.0
+	metricsCh <- []metrics.Metrics{{ID: "Invalid", MType: "gauge", Value: &firstValue}}
+	metricsCh <- []metrics.Metrics{{ID: "Valid", MType: "gauge", Value: &secondValue}}
+	close(metricsCh)
+
+	wg.Wait()
+
+	assert.Equal(t, []string{"InvalidSpooled", "Invalid", "Valid"}, received,
+		"should drop the rejected spooled batch and send the following batches")
+	assert.Equal(t, int64(0), spool.Size(), "should not spool the rejected batch")
+	assert.Equal(t, uint64(2), destination.failedBatches.Load(), "should count the rejected batches as failed")
+}
+
+func TestSendMetricsWithGrpcStreamWorker_OkResponse(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -282,6 +569,7 @@ func TestSendMetricsWithGrpcWorker_OkResponse(t *testing.T) {
 	aLogger := &logger.AgentLogger{Logger: observerLogger}
 
 	mockGRPCClient := mocks.NewMockMetricsV1ServiceClient(ctrl)
+	mockStream := mocks.NewMockMetricsV1Service_StreamMetricsClient(ctrl)
 
 	metricValue := 22.22
 	metricsData := &pb.MetricData{
@@ -289,17 +577,22 @@ func TestSendMetricsWithGrpcWorker_OkResponse(t *testing.T) {
 		Type:        "gauge",
 		MetricValue: &pb.MetricData_Value{Value: metricValue},
 	}
-	updateMetricsBatchRequest := &pb.MetricsV1ServiceUpdateMetricsBatchRequest{
-		Metrics: []*pb.MetricData{metricsData},
-	}
-
-	mockGRPCClient.EXPECT().
-		UpdateMetricsBatch(gomock.Any(), updateMetricsBatchRequest).
-		Return(&pb.MetricsV1ServiceUpdateMetricsBatchResponse{}, nil).
-		Times(1)
 
-	cfg := &config.AgentConfig{}
-	agent := &Agent{
+	mockGRPCClient.EXPECT().StreamMetrics(gomock.Any()).Return(mockStream, nil).Times(1)
+	gomock.InOrder(
+		mockStream.EXPECT().
+			Send(&pb.MetricsV1ServiceStreamMetricsRequest{Sequence: 1, Metrics: []*pb.MetricData{metricsData}}).
+			Return(nil),
+		mockStream.EXPECT().Recv().Return(&pb.MetricsV1ServiceStreamMetricsResponse{Sequence: 1}, nil),
+		mockStream.EXPECT().
+			Send(&pb.MetricsV1ServiceStreamMetricsRequest{Sequence: 2, Metrics: []*pb.MetricData{metricsData}}).
+			Return(nil),
+		mockStream.EXPECT().Recv().Return(&pb.MetricsV1ServiceStreamMetricsResponse{Sequence: 2}, nil),
+		mockStream.EXPECT().CloseSend().Return(nil),
+	)
+
+	cfg := &config.DestinationConfig{}
+	destination := &Destination{
 		Config:                         cfg,
 		Logger:                         aLogger,
 		GRPCClient:                     mockGRPCClient,
@@ -310,11 +603,7 @@ func TestSendMetricsWithGrpcWorker_OkResponse(t *testing.T) {
 
 	var wg sync.WaitGroup
 	wg.Add(1)
-	worker := func() {
-		agent.SendMetricsWithGrpcWorker(1, metricsCh, &wg)
-	}
-
-	go worker()
+	go destination.SendMetricsWithGrpcStreamWorker(1, metricsCh, nil, &wg)
 
 	sentMetrics := []metrics.Metrics{
 		{
@@ -324,27 +613,19 @@ func TestSendMetricsWithGrpcWorker_OkResponse(t *testing.T) {
 		},
 	}
 	metricsCh <- sentMetrics
+	metricsCh <- sentMetrics
 	close(metricsCh)
 
 	wg.Wait()
 
-	logs := recorded.FilterMessage("Worker started").All()
-	assert.Len(t, logs, 1, "should log info about the starting worker")
-	assert.Equal(t, "starting send metrics worker", logs[0].ContextMap()["event"],
-		"should log info about the starting worker")
-
-	logs = recorded.FilterMessage("Success sent metrics update").All()
-	assert.Len(t, logs, 1, "should log info about the successful metrics update")
-	assert.Equal(t, "sending metrics update", logs[0].ContextMap()["event"],
-		"should log info about the successful metrics update")
+	logs := recorded.FilterMessage("Success sent metrics update").All()
+	assert.Len(t, logs, 2, "should log info about each successful metrics update")
 
 	logs = recorded.FilterMessage("Worker stopped").All()
 	assert.Len(t, logs, 1, "should log info about the stopping worker")
-	assert.Equal(t, "stopping send metrics worker", logs[0].ContextMap()["event"],
-		"should log info about the stopping worker")
 }
 
-func TestSendMetricsWithGrpcWorker_Error(t *testing.T) {
+func TestSendMetricsWithGrpcStreamWorker_ReopenStream(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -353,25 +634,123 @@ func TestSendMetricsWithGrpcWorker_Error(t *testing.T) {
 	aLogger := &logger.AgentLogger{Logger: observerLogger}
 
 	mockGRPCClient := mocks.NewMockMetricsV1ServiceClient(ctrl)
+	brokenStream := mocks.NewMockMetricsV1Service_StreamMetricsClient(ctrl)
+	mockStream := mocks.NewMockMetricsV1Service_StreamMetricsClient(ctrl)
+
+	gomock.InOrder(
+		mockGRPCClient.EXPECT().StreamMetrics(gomock.Any()).Return(brokenStream, nil),
+		mockGRPCClient.EXPECT().StreamMetrics(gomock.Any()).Return(mockStream, nil),
+	)
+	brokenStream.EXPECT().Send(gomock.Any()).Return(nil)
+	brokenStream.EXPECT().Recv().Return(nil, status.Error(codes.Unavailable, "service unavailable"))
+	brokenStream.EXPECT().CloseSend().Return(nil)
+	mockStream.EXPECT().Send(gomock.Any()).Return(nil)
+	mockStream.EXPECT().Recv().Return(&pb.MetricsV1ServiceStreamMetricsResponse{Sequence: 1}, nil)
+	mockStream.EXPECT().CloseSend().Return(nil)
+
+	cfg := &config.DestinationConfig{}
+	destination := &Destination{
+		Config:                         cfg,
+		Logger:                         aLogger,
+		GRPCClient:                     mockGRPCClient,
+		sendMetricsBatchRetryIntervals: backoff.NewExponentialBackOff(backoff.WithInitialInterval(1 * time.Millisecond)),
+	}
+
+	metricsCh := make(chan []metrics.Metrics, 10)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go destination.SendMetricsWithGrpcStreamWorker(1, metricsCh, nil, &wg)
 
 	metricValue := 22.22
-	metricsData := &pb.MetricData{
-		Name:        "Alloc",
-		Type:        "gauge",
-		MetricValue: &pb.MetricData_Value{Value: metricValue},
-	}
-	updateMetricsBatchRequest := &pb.MetricsV1ServiceUpdateMetricsBatchRequest{
-		Metrics: []*pb.MetricData{metricsData},
+	metricsCh <- []metrics.Metrics{{ID: "Alloc", MType: "gauge", Value: &metricValue}}
+	close(metricsCh)
+
+	wg.Wait()
+
+	logs := recorded.FilterMessage("Success sent metrics update").All()
+	assert.Len(t, logs, 1, "should send metrics update over the reopened stream")
+}
+
+func TestSendMetricsWithGrpcStreamWorker_Timeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	observerLogger := zap.New(core)
+	aLogger := &logger.AgentLogger{Logger: observerLogger}
+
+	mockGRPCClient := mocks.NewMockMetricsV1ServiceClient(ctrl)
+	stuckStream := mocks.NewMockMetricsV1Service_StreamMetricsClient(ctrl)
+	mockStream := mocks.NewMockMetricsV1Service_StreamMetricsClient(ctrl)
+
+	var stuckCtx context.Context
+	gomock.InOrder(
+		mockGRPCClient.EXPECT().StreamMetrics(gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ ...any) (pb.MetricsV1Service_StreamMetricsClient, error) {
+				stuckCtx = ctx
+				return stuckStream, nil
+			}),
+		mockGRPCClient.EXPECT().StreamMetrics(gomock.Any()).Return(mockStream, nil),
+	)
+	stuckStream.EXPECT().Send(gomock.Any()).Return(nil)
+	stuckStream.EXPECT().Recv().DoAndReturn(func() (*pb.MetricsV1ServiceStreamMetricsResponse, error) {
+		<-stuckCtx.Done()
+		return nil, status.Error(codes.Canceled, "context canceled")
+	})
+	stuckStream.EXPECT().CloseSend().Return(nil)
+	mockStream.EXPECT().Send(gomock.Any()).Return(nil)
+	mockStream.EXPECT().Recv().Return(&pb.MetricsV1ServiceStreamMetricsResponse{Sequence: 1}, nil)
+	mockStream.EXPECT().CloseSend().Return(nil)
+
+	destination := &Destination{
+		Config:                         &config.DestinationConfig{},
+		Logger:                         aLogger,
+		GRPCClient:                     mockGRPCClient,
+		sendMetricsBatchRetryIntervals: backoff.NewExponentialBackOff(backoff.WithInitialInterval(1 * time.Millisecond)),
+		sendMetricsTimeout:             10 * time.Millisecond,
 	}
 
-	mockGRPCClient.EXPECT().
-		UpdateMetricsBatch(gomock.Any(), updateMetricsBatchRequest).
-		Return(nil, status.Error(codes.Unavailable, "service unavailable")).
-		Times(1)
+	metricsCh := make(chan []metrics.Metrics, 10)
 
-	cfg := &config.AgentConfig{}
-	agent := &Agent{
-		Config:                         cfg,
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go destination.SendMetricsWithGrpcStreamWorker(1, metricsCh, nil, &wg)
+
+	metricValue := 22.22
+	metricsCh <- []metrics.Metrics{{ID: "Alloc", MType: "gauge", Value: &metricValue}}
+	close(metricsCh)
+
+	wg.Wait()
+
+	logs := recorded.FilterMessage("Success sent metrics update").All()
+	assert.Len(t, logs, 1, "should resend metrics update over the reopened stream after the timeout")
+	assert.Equal(t, uint64(1), destination.retriedBatches.Load(), "should retry the batch that wasn't acknowledged")
+}
+
+func TestSendMetricsWithGrpcStreamWorker_ErrorAck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	observerLogger := zap.New(core)
+	aLogger := &logger.AgentLogger{Logger: observerLogger}
+
+	mockGRPCClient := mocks.NewMockMetricsV1ServiceClient(ctrl)
+	mockStream := mocks.NewMockMetricsV1Service_StreamMetricsClient(ctrl)
+
+	mockGRPCClient.EXPECT().StreamMetrics(gomock.Any()).Return(mockStream, nil).Times(1)
+	gomock.InOrder(
+		mockStream.EXPECT().Send(gomock.Any()).Return(nil),
+		mockStream.EXPECT().Recv().Return(&pb.MetricsV1ServiceStreamMetricsResponse{
+			Sequence: 1, Code: uint32(codes.InvalidArgument), Error: "invalid metric value"}, nil),
+		mockStream.EXPECT().Send(gomock.Any()).Return(nil),
+		mockStream.EXPECT().Recv().Return(&pb.MetricsV1ServiceStreamMetricsResponse{Sequence: 2}, nil),
+		mockStream.EXPECT().CloseSend().Return(nil),
+	)
+
+	destination := &Destination{
+		Config:                         &config.DestinationConfig{},
 		Logger:                         aLogger,
 		GRPCClient:                     mockGRPCClient,
 		sendMetricsBatchRetryIntervals: backoff.NewExponentialBackOff(backoff.WithMaxElapsedTime(1 * time.Millisecond)),
@@ -381,36 +760,192 @@ func TestSendMetricsWithGrpcWorker_Error(t *testing.T) {
 
 	var wg sync.WaitGroup
 	wg.Add(1)
-	worker := func() {
-		agent.SendMetricsWithGrpcWorker(1, metricsCh, &wg)
+	go destination.SendMetricsWithGrpcStreamWorker(1, metricsCh, nil, &wg)
+
+	metricValue := 22.22
+	metricsCh <- []metrics.Metrics{{ID: "Alloc", MType: "gauge", Value: &metricValue}}
+	metricsCh <- []metrics.Metrics{{ID: "Alloc", MType: "gauge", Value: &metricValue}}
+	close(metricsCh)
+
+	wg.Wait()
+
+	logs := recorded.FilterMessage("Success sent metrics update").All()
+	assert.Len(t, logs, 1, "should send the next batch over the same stream")
+	assert.Equal(t, uint64(1), destination.failedBatches.Load(), "should drop the batch acknowledged with an error")
+}
+
+func TestSendMetricsResetsCounters(t *testing.T) {
+	aLogger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	memCache := cache.NewAgentMemCache(make(map[string]metrics.GaugeMetric), metrics.SupportedCounterMetrics, aLogger)
+	memCache.Declare(metrics.NewPrefixDescriptor("DiskReadBytes{", metrics.Counter))
+	delta := int64(100)
+	memCache.UpdateMetrics([]metrics.Metrics{{ID: "DiskReadBytes{device=sda}", MType: string(metrics.Counter), Delta: &delta}})
+
+	cfg := config.AgentConfig{MetricsBufferSize: 1}
+	agent := NewAgent(&memCache, nil, &cfg, aLogger)
+	destination := NewDestination(&config.DestinationConfig{}, &cfg, nil, aLogger)
+	agent.AddDestination(destination)
+
+	agent.SendMetrics()
+	<-destination.metricsCh
+
+	_, counters := memCache.GetMetrics()
+	assert.Equal(t, int64(0), counters["DiskReadBytes{device=sda}"].Value, "should reset sent counter metric")
+	assert.Equal(t, int64(0), counters["PollCount"].Value, "should reset PollCount counter metric")
+}
+
+func TestSendMetricsFanOut(t *testing.T) {
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+	memCache := cache.NewAgentMemCache(make(map[string]metrics.GaugeMetric), metrics.SupportedCounterMetrics, aLogger)
+
+	cfg := config.AgentConfig{MetricsBufferSize: 1}
+	agent := NewAgent(&memCache, nil, &cfg, aLogger)
+	primary := NewDestination(&config.DestinationConfig{Name: "primary",
+		BackpressurePolicy: config.BackpressureDropNewest}, &cfg, nil, aLogger)
+	dr := NewDestination(&config.DestinationConfig{Name: "dr",
+		BackpressurePolicy: config.BackpressureBlock}, &cfg, nil, aLogger)
+	agent.AddDestination(primary)
+	agent.AddDestination(dr)
+	dr.metricsCh <- newBackpressureTestBatch(1, 1)
+
+	dropped := func(batch []metrics.Metrics) int64 {
+		for _, m := range batch {
+			if m.ID == droppedBatchesMetric {
+				return *m.Delta
+			}
+		}
+		return -1
 	}
 
-	go worker()
+	agent.SendMetrics()
+	require.Eventually(t, func() bool { return len(primary.metricsCh) == 1 }, time.Second, time.Millisecond,
+		"should enqueue the batch to the primary destination")
+	agent.SendMetrics()
+	require.Eventually(t, func() bool { return primary.droppedBatches.Load() == 1 }, time.Second, time.Millisecond,
+		"should drop the new batch of the primary destination with drop-newest policy")
+	assert.Equal(t, int64(0), dropped(<-primary.metricsCh), "should report batches dropped by the primary destination")
+
+	sent := make(chan struct{})
+	go func() {
+		agent.SendMetrics()
+		close(sent)
+	}()
+	assert.Equal(t, int64(1), dropped(<-primary.metricsCh),
+		"should report to the primary destination while the dr destination blocks")
+	select {
+	case <-sent:
+		t.Fatal("should wait for the dr destination with the full metrics channel with block policy")
+	case <-time.After(50 * time.Millisecond):
+	}
 
-	sentMetrics := []metrics.Metrics{
-		{
-			ID:    "Alloc",
-			MType: "gauge",
-			Value: &metricValue,
-		},
+	<-dr.metricsCh
+	assert.Equal(t, int64(0), dropped(<-dr.metricsCh), "should report dropped batches with block policy")
+	<-sent
+}
+
+func TestReportMetricsBatchCoalescesWaitingBatch(t *testing.T) {
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+	cfg := config.AgentConfig{MetricsBufferSize: 1}
+	destination := NewDestination(&config.DestinationConfig{BackpressurePolicy: config.BackpressureCoalesce}, &cfg, nil,
+		aLogger)
+	destination.metricsCh <- newBackpressureTestBatch(0, 0)
+
+	for i := 1; i <= 3; i++ {
+		destination.reportMetricsBatch(newBackpressureTestBatch(float64(i), int64(i)))
 	}
-	metricsCh <- sentMetrics
-	close(metricsCh)
 
-	wg.Wait()
+	go destination.StopSendMetricsWorkerPool()
+	<-destination.metricsCh
+	gauge, counter := 0.0, int64(0)
+	for batch := range destination.metricsCh {
+		gauge = *batch[0].Value
+		counter += *batch[1].Delta
+	}
+	assert.Equal(t, 3.0, gauge, "should keep the latest gauge value of the waiting batches")
+	assert.Equal(t, int64(6), counter, "should keep the counter deltas of the waiting batches")
+}
 
-	logs := recorded.FilterMessage("Worker started").All()
-	assert.Len(t, logs, 1, "should log info about the starting worker")
-	assert.Equal(t, "starting send metrics worker", logs[0].ContextMap()["event"],
-		"should log info about the starting worker")
+func TestReportMetricsBatchBlockWaitsForWaitingBatch(t *testing.T) {
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+	cfg := config.AgentConfig{MetricsBufferSize: 1}
+	destination := NewDestination(&config.DestinationConfig{BackpressurePolicy: config.BackpressureBlock}, &cfg, nil,
+		aLogger)
+	destination.metricsCh <- newBackpressureTestBatch(0, 0)
+
+	destination.reportMetricsBatch(newBackpressureTestBatch(1, 1))
+	require.Eventually(t, func() bool {
+		destination.pendingMu.Lock()
+		defer destination.pendingMu.Unlock()
+		return destination.pendingBatch == nil
+	}, time.Second, time.Millisecond, "should take the waiting batch to enqueue")
+	destination.reportMetricsBatch(newBackpressureTestBatch(2, 2))
+
+	reported := make(chan struct{})
+	go func() {
+		destination.reportMetricsBatch(newBackpressureTestBatch(3, 3))
+		close(reported)
+	}()
+	select {
+	case <-reported:
+		t.Fatal("should wait until the waiting batch is taken with block policy")
+	case <-time.After(50 * time.Millisecond):
+	}
 
-	logs = recorded.FilterMessage("unsuccess request sent on url: , status code: 14").All()
-	assert.Len(t, logs, 1, "should log info about the error in sending metrics update")
-	assert.Equal(t, "sending metrics update", logs[0].ContextMap()["event"],
-		"should log info about the sending metrics update with error")
+	for i := 0; i <= 3; i++ {
+		batch := <-destination.metricsCh
+		assert.Equal(t, float64(i), *batch[0].Value, "should enqueue each reported batch in order")
+	}
+	<-reported
+	assert.Equal(t, uint64(0), destination.coalescedBatches.Load(), "shouldn't coalesce batches with block policy")
+}
 
-	logs = recorded.FilterMessage("Worker stopped").All()
-	assert.Len(t, logs, 1, "should log info about the stopping worker")
-	assert.Equal(t, "stopping send metrics worker", logs[0].ContextMap()["event"],
-		"should log info about the stopping worker")
+func TestSendMetricsSplitsBatch(t *testing.T) {
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+	memCache := cache.NewAgentMemCache(make(map[string]metrics.GaugeMetric), metrics.SupportedCounterMetrics, aLogger)
+
+	cfg := config.AgentConfig{MetricsBufferSize: 100}
+	agent := NewAgent(&memCache, nil, &cfg, aLogger)
+	destination := NewDestination(&config.DestinationConfig{BatchMaxItems: 3}, &cfg, nil, aLogger)
+	agent.AddDestination(destination)
+
+	agent.SendMetrics()
+	destination.StopSendMetricsWorkerPool()
+
+	require.Greater(t, len(destination.metricsCh), 1, "should split the batch exceeding batch limits")
+	sent := 0
+	for batch := range destination.metricsCh {
+		assert.LessOrEqual(t, len(batch), 3, "should send batches of at most batch max items")
+		sent += len(batch)
+	}
+	assert.GreaterOrEqual(t, sent, len(metrics.SupportedCounterMetrics), "should send all metrics of the split batch")
+}
+
+func TestStartSendMetricsCancelKeepsTakenCounters(t *testing.T) {
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+	memCache := cache.NewAgentMemCache(make(map[string]metrics.GaugeMetric),
+		map[string]metrics.CounterMetric{"Requests": metrics.NewCounter("Requests", 5)}, aLogger)
+
+	cfg := config.AgentConfig{SendMetricsInterval: 60, MetricsBufferSize: 1, BackpressurePolicy: config.BackpressureBlock}
+	agent := NewAgent(&memCache, nil, &cfg, aLogger)
+	destination := NewDestination(&config.DestinationConfig{}, &cfg, nil, aLogger)
+	agent.AddDestination(destination)
+	destination.metricsCh <- newBackpressureTestBatch(1, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+	agent.StartSendMetrics(ctx, &wg)
+	require.Eventually(t, func() bool {
+		_, counters := memCache.GetMetrics()
+		return counters["Requests"].Value == 0
+	}, time.Second, 10*time.Millisecond, "should take the counter metrics")
+	cancel()
+
+	<-destination.metricsCh
+	wg.Wait()
+
+	batch := <-destination.metricsCh
+	i := slices.IndexFunc(batch, func(m metrics.Metrics) bool { return m.ID == "Requests" })
+	require.GreaterOrEqual(t, i, 0, "should enqueue the taken counter metrics after the context is canceled")
+	assert.Equal(t, int64(5), *batch[i].Delta)
 }
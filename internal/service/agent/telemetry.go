@@ -0,0 +1,115 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// Names of the agent self-telemetry metrics. The metrics describing the send of batches are reported
+// to each destination about itself, the other ones are the same for all destinations.
+const (
+	batchesSentMetric       = "AgentBatchesSent"
+	batchesFailedMetric     = "AgentBatchesFailed"
+	batchesRetriedMetric    = "AgentBatchesRetried"
+	sendLatencyMetric       = "AgentSendLatency"
+	queueDepthMetric        = "AgentQueueDepth"
+	collectorDurationMetric = "AgentCollectorDuration"
+	collectorErrorsMetric   = "AgentCollectorErrors"
+	buildInfoMetric         = "AgentBuildInfo"
+	uptimeMetric            = "AgentUptime"
+)
+
+// collectorTelemetry holds the statistics of the collector runs since the previous report.
+type collectorTelemetry struct {
+	duration time.Duration
+	errors   int64
+}
+
+// agentTelemetry holds the agent self-telemetry statistics shared by all destinations.
+type agentTelemetry struct {
+	mu         sync.Mutex
+	startTime  time.Time
+	collectors map[string]*collectorTelemetry
+}
+
+// newAgentTelemetry is constructor for creating a new agentTelemetry.
+func newAgentTelemetry() *agentTelemetry {
+	return &agentTelemetry{startTime: time.Now(), collectors: make(map[string]*collectorTelemetry)}
+}
+
+// observeCollector records the duration of the last collector run and counts the collector errors.
+func (t *agentTelemetry) observeCollector(name string, duration time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c, exists := t.collectors[name]
+	if !exists {
+		c = &collectorTelemetry{}
+		t.collectors[name] = c
+	}
+	c.duration = duration
+	if err != nil {
+		c.errors++
+	}
+}
+
+// metrics returns the build version, the uptime in seconds, the last collector run durations
+// in milliseconds and the counts of collector errors since the previous call.
+func (t *agentTelemetry) metrics(buildVersion string) []metrics.Metrics {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ms := make([]metrics.Metrics, 0, 2+2*len(t.collectors))
+	ms = append(ms,
+		metrics.NewGaugeMetrics(labeledName(buildInfoMetric, "version", buildVersion), 1),
+		metrics.NewGaugeMetrics(uptimeMetric, time.Since(t.startTime).Seconds()))
+
+	for name, c := range t.collectors {
+		ms = append(ms,
+			metrics.NewGaugeMetrics(labeledName(collectorDurationMetric, "collector", name),
+				float64(c.duration)/float64(time.Millisecond)),
+			metrics.NewCounterMetrics(labeledName(collectorErrorsMetric, "collector", name), c.errors))
+		c.errors = 0
+	}
+
+	return ms
+}
+
+// telemetryMetrics returns the self-telemetry metrics of sending batches to the destination since
+// the previous call: the counts of sent, failed and retried batches, the average send latency
+// in milliseconds and the current depth of the destination metrics channel. The send latency
+// is reported only when batches were sent.
+func (d *Destination) telemetryMetrics() []metrics.Metrics {
+	sent := int64(d.sentBatches.Swap(0))
+	failed := int64(d.failedBatches.Swap(0))
+	retried := int64(d.retriedBatches.Swap(0))
+	latency := d.sendLatency.Swap(0)
+	latencyCount := d.sendLatencyCount.Swap(0)
+
+	ms := []metrics.Metrics{
+		metrics.NewCounterMetrics(batchesSentMetric, sent),
+		metrics.NewCounterMetrics(batchesFailedMetric, failed),
+		metrics.NewCounterMetrics(batchesRetriedMetric, retried),
+		metrics.NewGaugeMetrics(queueDepthMetric, float64(len(d.metricsCh))),
+	}
+	if latencyCount > 0 {
+		averageLatency := float64(latency) / float64(latencyCount) / float64(time.Millisecond)
+		ms = append(ms, metrics.NewGaugeMetrics(sendLatencyMetric, averageLatency))
+	}
+
+	return ms
+}
+
+// observeSendLatency records the duration of a successful send of a batch to the destination.
+func (d *Destination) observeSendLatency(duration time.Duration) {
+	d.sendLatency.Add(int64(duration))
+	d.sendLatencyCount.Add(1)
+}
+
+// labeledName encodes the label into the metric name in format: name{key=value}. The characters
+// that can't be used in label values are replaced with underscores.
+func labeledName(name, key, value string) string {
+	return metrics.SeriesName(name, map[string]string{key: metrics.SanitizeLabelValue(value)})
+}
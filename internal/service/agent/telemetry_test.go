@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func telemetryByID(ms []metrics.Metrics) map[string]metrics.Metrics {
+	byID := make(map[string]metrics.Metrics, len(ms))
+	for _, m := range ms {
+		byID[m.ID] = m
+	}
+	return byID
+}
+
+func TestAgentTelemetryMetrics(t *testing.T) {
+	telemetry := newAgentTelemetry()
+	telemetry.startTime = time.Now().Add(-time.Minute)
+
+	telemetry.observeCollector("runtime", 2*time.Millisecond, nil)
+	telemetry.observeCollector("disk", 5*time.Millisecond, errors.New("collect error"))
+	telemetry.observeCollector("disk", 3*time.Millisecond, errors.New("collect error"))
+
+	got := telemetryByID(telemetry.metrics("v1.2.3"))
+
+	require.Contains(t, got, "AgentBuildInfo{version=v1.2.3}")
+	assert.Equal(t, 1.0, *got["AgentBuildInfo{version=v1.2.3}"].Value, "should report the build version")
+	assert.GreaterOrEqual(t, *got[uptimeMetric].Value, 60.0, "should report the uptime in seconds")
+	assert.Equal(t, 3.0, *got["AgentCollectorDuration{collector=disk}"].Value,
+		"should report the last collector run duration in milliseconds")
+	assert.Equal(t, int64(2), *got["AgentCollectorErrors{collector=disk}"].Delta, "should count collector errors")
+	assert.Equal(t, int64(0), *got["AgentCollectorErrors{collector=runtime}"].Delta, "should count collector errors")
+
+	got = telemetryByID(telemetry.metrics("v1.2.3"))
+	assert.Equal(t, int64(0), *got["AgentCollectorErrors{collector=disk}"].Delta,
+		"should reset collector errors count after report")
+}
+
+func TestDestinationTelemetryMetrics(t *testing.T) {
+	destination := &Destination{
+		Logger:    &logger.AgentLogger{Logger: zap.NewNop()},
+		metricsCh: make(chan []metrics.Metrics, 2),
+		sendMetricsBatchRetryIntervals: backoff.NewExponentialBackOff(backoff.WithInitialInterval(time.Millisecond),
+			backoff.WithMaxElapsedTime(10*time.Millisecond)),
+	}
+	destination.metricsCh <- []metrics.Metrics{}
+
+	attempts := 0
+	retryOnce := func(metrics.BatchKey, []metrics.Metrics) backoff.Operation {
+		return func() error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("send error")
+			}
+			return nil
+		}
+	}
+	destination.sendMetricsBatch(1, []metrics.Metrics{}, retryOnce)
+
+	fail := func(metrics.BatchKey, []metrics.Metrics) backoff.Operation {
+		return func() error { return backoff.Permanent(errors.New("send error")) }
+	}
+	destination.sendMetricsBatch(1, []metrics.Metrics{}, fail)
+
+	got := telemetryByID(destination.telemetryMetrics())
+
+	assert.Equal(t, int64(1), *got[batchesSentMetric].Delta, "should count sent batches")
+	assert.Equal(t, int64(1), *got[batchesFailedMetric].Delta, "should count failed batches")
+	assert.Equal(t, int64(1), *got[batchesRetriedMetric].Delta, "should count retried batches")
+	assert.Equal(t, 1.0, *got[queueDepthMetric].Value, "should report the metrics channel depth")
+	require.Contains(t, got, sendLatencyMetric, "should report the send latency")
+
+	got = telemetryByID(destination.telemetryMetrics())
+	assert.Equal(t, int64(0), *got[batchesSentMetric].Delta, "should reset sent batches count after report")
+	assert.NotContains(t, got, sendLatencyMetric, "shouldn't report the send latency without sent batches")
+}
+
+func TestLabeledName(t *testing.T) {
+	assert.Equal(t, "AgentBuildInfo{version=N/A}", labeledName(buildInfoMetric, "version", "N/A"))
+	assert.Equal(t, "AgentBuildInfo{version=v1_dev_}", labeledName(buildInfoMetric, "version", "v1 dev}"))
+	assert.Equal(t, "AgentBuildInfo{version=_}", labeledName(buildInfoMetric, "version", ""))
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+const (
+	defaultSubscriptionMaxPending = 1000
+)
+
+// MetricsFilter selects metrics for a subscription by exact names or by name prefix.
+// An empty filter matches all metrics.
+type MetricsFilter struct {
+	Names  []string // Names is a list of metric names to watch
+	Prefix string   // Prefix is a metric name prefix to watch
+}
+
+// Match reports whether the metric name is selected by the filter.
+func (f MetricsFilter) Match(name string) bool {
+	if len(f.Names) == 0 && len(f.Prefix) == 0 {
+		return true
+	}
+
+	for _, n := range f.Names {
+		if n == name {
+			return true
+		}
+	}
+
+	return len(f.Prefix) > 0 && strings.HasPrefix(name, f.Prefix)
+}
+
+// MetricsSubscription holds pending metric updates for one subscriber.
+// Updates of the same metric that weren't read yet are coalesced, so a slow subscriber
+// receives only the latest value of each metric. When the number of pending metrics
+// reaches the limit, updates of new metrics are dropped.
+type MetricsSubscription struct {
+	filter     MetricsFilter
+	maxPending int
+
+	mu      sync.Mutex
+	pending map[string]metrics.Metrics
+	order   []string
+	dropped int64
+	notify  chan struct{}
+}
+
+func newMetricsSubscription(filter MetricsFilter, maxPending int) *MetricsSubscription {
+	return &MetricsSubscription{
+		filter:     filter,
+		maxPending: maxPending,
+		pending:    make(map[string]metrics.Metrics),
+		notify:     make(chan struct{}, 1),
+	}
+}
+
+// Updates returns a channel that receives a value when the subscription has pending updates.
+func (s *MetricsSubscription) Updates() <-chan struct{} {
+	return s.notify
+}
+
+// Next returns pending updates in the order of their first arrival and clears them.
+func (s *MetricsSubscription) Next() []metrics.Metrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch := make([]metrics.Metrics, 0, len(s.order))
+	for _, key := range s.order {
+		batch = append(batch, s.pending[key])
+	}
+	s.pending = make(map[string]metrics.Metrics)
+	s.order = s.order[:0]
+
+	return batch
+}
+
+// Dropped returns the number of updates dropped because the subscriber was too slow.
+func (s *MetricsSubscription) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dropped
+}
+
+func (s *MetricsSubscription) push(ms []metrics.Metrics) {
+	s.mu.Lock()
+	added := false
+	for _, m := range ms {
+		if !s.filter.Match(m.ID) {
+			continue
+		}
+
+		key := m.MType + ":" + m.ID
+		if _, ok := s.pending[key]; !ok {
+			if len(s.order) >= s.maxPending {
+				s.dropped++
+				continue
+			}
+			s.order = append(s.order, key)
+		}
+		s.pending[key] = m
+		added = true
+	}
+	s.mu.Unlock()
+
+	if added {
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// MetricsBroker fans out metric updates to subscribers without blocking the publisher.
+type MetricsBroker struct {
+	mu          sync.RWMutex
+	subscribers map[*MetricsSubscription]struct{}
+	maxPending  int
+}
+
+// NewMetricsBroker is constructor for creating a new MetricsBroker. The maxPending limits
+// the number of distinct metrics buffered for each subscriber.
+func NewMetricsBroker(maxPending int) *MetricsBroker {
+	if maxPending <= 0 {
+		maxPending = defaultSubscriptionMaxPending
+	}
+
+	return &MetricsBroker{
+		subscribers: make(map[*MetricsSubscription]struct{}),
+		maxPending:  maxPending,
+	}
+}
+
+// Subscribe registers a new subscription for metrics selected by the filter.
+func (b *MetricsBroker) Subscribe(filter MetricsFilter) *MetricsSubscription {
+	sub := newMetricsSubscription(filter, b.maxPending)
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe removes the subscription from the broker.
+func (b *MetricsBroker) Unsubscribe(sub *MetricsSubscription) {
+	b.mu.Lock()
+	delete(b.subscribers, sub)
+	b.mu.Unlock()
+}
+
+// Watched reports whether any subscriber watches the metric with the given name.
+func (b *MetricsBroker) Watched(name string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if sub.filter.Match(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Publish sends metric updates to all subscribers watching them.
+func (b *MetricsBroker) Publish(ms []metrics.Metrics) {
+	if len(ms) == 0 {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		sub.push(ms)
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func TestMetricsFilter_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   MetricsFilter
+		metric   string
+		expected bool
+	}{
+		{name: "empty filter matches all metrics", filter: MetricsFilter{}, metric: "Alloc", expected: true},
+		{name: "name in list", filter: MetricsFilter{Names: []string{"Alloc", "PollCount"}}, metric: "PollCount", expected: true},
+		{name: "name not in list", filter: MetricsFilter{Names: []string{"Alloc"}}, metric: "Frees", expected: false},
+		{name: "name with prefix", filter: MetricsFilter{Prefix: "CPU"}, metric: "CPUutilization1", expected: true},
+		{name: "name without prefix", filter: MetricsFilter{Prefix: "CPU"}, metric: "Alloc", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.filter.Match(tt.metric))
+		})
+	}
+}
+
+func TestMetricsBroker_Publish(t *testing.T) {
+	t.Run("should deliver only watched metrics", func(t *testing.T) {
+		b := NewMetricsBroker(10)
+		sub := b.Subscribe(MetricsFilter{Names: []string{"Alloc"}})
+		defer b.Unsubscribe(sub)
+
+		b.Publish([]metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 1), metrics.NewGaugeMetrics("Frees", 2)})
+
+		select {
+		case <-sub.Updates():
+		default:
+			t.Fatal("should notify subscriber about updates")
+		}
+		assert.Equal(t, []metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 1)}, sub.Next())
+		assert.Empty(t, sub.Next(), "should clear pending updates")
+	})
+
+	t.Run("should coalesce pending updates of the same metric", func(t *testing.T) {
+		b := NewMetricsBroker(10)
+		sub := b.Subscribe(MetricsFilter{})
+		defer b.Unsubscribe(sub)
+
+		b.Publish([]metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 1), metrics.NewCounterMetrics("PollCount", 1)})
+		b.Publish([]metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 3)})
+
+		assert.Equal(t, []metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 3), metrics.NewCounterMetrics("PollCount", 1)}, sub.Next())
+		assert.Equal(t, int64(0), sub.Dropped())
+	})
+
+	t.Run("should drop updates of new metrics when subscriber is too slow", func(t *testing.T) {
+		b := NewMetricsBroker(1)
+		sub := b.Subscribe(MetricsFilter{})
+		defer b.Unsubscribe(sub)
+
+		b.Publish([]metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 1), metrics.NewGaugeMetrics("Frees", 2)})
+
+		assert.Equal(t, []metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 1)}, sub.Next())
+		assert.Equal(t, int64(1), sub.Dropped())
+	})
+
+	t.Run("shouldn't deliver updates after unsubscribe", func(t *testing.T) {
+		b := NewMetricsBroker(10)
+		sub := b.Subscribe(MetricsFilter{})
+		b.Unsubscribe(sub)
+
+		b.Publish([]metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 1)})
+
+		assert.False(t, b.Watched("Alloc"))
+		assert.Empty(t, sub.Next())
+	})
+}
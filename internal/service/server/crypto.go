@@ -2,7 +2,6 @@ package server
 
 import (
 	"bytes"
-	"crypto/rand"
 	"crypto/rsa"
 	"io"
 	"net/http"
@@ -32,7 +31,7 @@ func GetRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
 // EncryptMiddleware is a middleware that decrypts the request body using RSA encryption.
 //
 // If the RSA private key is set in the Server, this middleware reads the encrypted request body,
-// decrypts it using the private key, and replaces the original request body with the decrypted body.
+// decrypts it using the private key block by block, and replaces the original request body with the decrypted body.
 // The decrypted body is then passed along the middleware chain.
 func (s *Server) EncryptMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -45,9 +44,10 @@ func (s *Server) EncryptMiddleware(next http.Handler) http.Handler {
 			}
 
 			if len(body) > 0 {
-				decryptedBody, err := rsa.DecryptPKCS1v15(rand.Reader, s.rsaPrivateKey, body)
+				decryptedBody, err := crypto.DecryptPKCS1v15Blocks(s.rsaPrivateKey, body)
 				if err != nil {
 					http.Error(w, "Decrypt request body error", http.StatusBadRequest)
+					return
 				}
 
 				r.Body = io.NopCloser(bytes.NewReader(decryptedBody))
@@ -42,6 +42,18 @@ func TestEncryptMiddleware(t *testing.T) {
 				body:   "The Ultimate Question of Life, the Universe, and Everything",
 			},
 		},
+		{
+			name:          "should return status bad request when private key is defined and request body isn`t encrypted",
+			hasPrivateKey: true,
+			request: request{
+				isBodyEncrypted: false,
+				body:            "The Ultimate Question of Life, the Universe, and Everything",
+			},
+			response: response{
+				status: http.StatusBadRequest,
+				body:   "Decrypt request body error\n",
+			},
+		},
 		{
 			name:          "shouldn`t decrypt request body when private key is defined and request body is empty",
 			hasPrivateKey: true,
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
+	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
+)
+
+const (
+	exportFormatJSON       = "json"
+	exportFormatNDJSON     = "ndjson"
+	exportFormatCSV        = "csv"
+	exportFormatPrometheus = "prometheus"
+
+	prometheusMediaType = "text/plain; version=0.0.4; charset=utf-8"
+
+	exportChunkSize = 100
+)
+
+// exportFormats maps export formats to response content types.
+var exportFormats = map[string]string{
+	exportFormatJSON:       "application/json",
+	exportFormatNDJSON:     ndjsonMediaType,
+	exportFormatCSV:        csvMediaType + "; charset=utf-8",
+	exportFormatPrometheus: prometheusMediaType,
+}
+
+// exportMediaTypes maps Accept header media types to export formats.
+var exportMediaTypes = map[string]string{
+	"application/json": exportFormatJSON,
+	ndjsonMediaType:    exportFormatNDJSON,
+	csvMediaType:       exportFormatCSV,
+	"text/plain":       exportFormatPrometheus,
+}
+
+// ExportMetricsHandler writes all metrics in the format set by the format query parameter
+// (json, ndjson, csv or prometheus) or chosen by the Accept header. JSON is used by default.
+// Metrics are sorted by name and type.
+func (s *Server) ExportMetricsHandler(res http.ResponseWriter, req *http.Request) {
+	format, err := getExportFormat(req)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	gauges, counters, err := s.MetricService.GetMetrics(req.Context())
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ms := sortedMetrics(gauges, counters)
+
+	res.Header().Set("Content-Type", exportFormats[format])
+	if format == exportFormatCSV {
+		res.Header().Set("Content-Disposition", `attachment; filename="metrics.csv"`)
+	}
+	res.WriteHeader(http.StatusOK)
+
+	w := bufio.NewWriter(res)
+	switch format {
+	case exportFormatNDJSON:
+		err = writeNDJSONExport(w, ms)
+	case exportFormatCSV:
+		err = writeCSVExport(w, ms)
+	case exportFormatPrometheus:
+		err = writePrometheusExport(w, ms)
+	default:
+		err = writeJSONExport(w, ms)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		s.Logger.Error(err.Error(), zap.String("event", "export metrics"))
+	}
+}
+
+// ExportMetrics streams all metrics sorted by name and type in chunks.
+func (s *Server) ExportMetrics(in *pb.MetricsV1ServiceExportMetricsRequest, stream pb.MetricsV1Service_ExportMetricsServer) error {
+	gauges, counters, err := s.MetricService.GetMetrics(stream.Context())
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	ms := sortedMetrics(gauges, counters)
+
+	for start := 0; start < len(ms); start += exportChunkSize {
+		end := min(start+exportChunkSize, len(ms))
+		resp := &pb.MetricsV1ServiceExportMetricsResponse{Metrics: metrics.MetricsToRepeatedMetricData(ms[start:end])}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getExportFormat(req *http.Request) (string, error) {
+	if format := req.URL.Query().Get("format"); len(format) > 0 {
+		format = strings.ToLower(format)
+		if _, ok := exportFormats[format]; !ok {
+			return "", fmt.Errorf("unsupported export format: %s", format)
+		}
+		return format, nil
+	}
+
+	for _, accept := range req.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil {
+				continue
+			}
+			if format, ok := exportMediaTypes[mediaType]; ok {
+				return format, nil
+			}
+		}
+	}
+
+	return exportFormatJSON, nil
+}
+
+func sortedMetrics(gauges map[string]metrics.GaugeMetric, counters map[string]metrics.CounterMetric) []metrics.Metrics {
+	ms := make([]metrics.Metrics, 0, len(gauges)+len(counters))
+	for _, gauge := range gauges {
+		ms = append(ms, metrics.GaugeMetricToMetrics(gauge))
+	}
+	for _, counter := range counters {
+		ms = append(ms, metrics.CounterMetricToMetrics(counter))
+	}
+
+	sort.Slice(ms, func(i, j int) bool {
+		if ms[i].ID != ms[j].ID {
+			return ms[i].ID < ms[j].ID
+		}
+		return ms[i].MType < ms[j].MType
+	})
+
+	return ms
+}
+
+func writeJSONExport(w io.Writer, ms []metrics.Metrics) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	for i, m := range ms {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+func writeNDJSONExport(w io.Writer, ms []metrics.Metrics) error {
+	encoder := json.NewEncoder(w)
+	for _, m := range ms {
+		if err := encoder.Encode(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCSVExport writes metrics in the format accepted by the import endpoint.
+func writeCSVExport(w io.Writer, ms []metrics.Metrics) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"name", "type", "value"}); err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if err := cw.Write([]string{m.ID, m.MType, formatMetricsValue(m)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writePrometheusExport writes metrics in the Prometheus text exposition format.
+// Labels encoded in metric names as name{key=value,...} are exported as Prometheus labels.
+func writePrometheusExport(w io.Writer, ms []metrics.Metrics) error {
+	type sample struct {
+		family string
+		labels string
+		metric metrics.Metrics
+	}
+
+	samples := make([]sample, 0, len(ms))
+	for _, m := range ms {
+		name, labels := parseSeriesName(m.ID)
+		samples = append(samples, sample{family: prometheusName(name), labels: prometheusLabels(labels), metric: m})
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].family < samples[j].family
+	})
+
+	family := ""
+	for _, s := range samples {
+		if s.family != family {
+			family = s.family
+			if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", family, s.metric.MType); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", s.family, s.labels, formatMetricsValue(s.metric)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatMetricsValue(m metrics.Metrics) string {
+	if metrics.MetricType(m.MType) == metrics.Counter {
+		return utils.FormatCounterMetricValue(*m.Delta)
+	}
+	return utils.FormatGaugeMetricValue(*m.Value)
+}
+
+// parseSeriesName splits a metric name encoded as name{key=value,...} into the name and labels.
+func parseSeriesName(series string) (string, [][2]string) {
+	start := strings.IndexByte(series, '{')
+	if start < 0 || !strings.HasSuffix(series, "}") {
+		return series, nil
+	}
+
+	labels := make([][2]string, 0)
+	for _, pair := range strings.Split(series[start+1:len(series)-1], ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || len(key) == 0 {
+			continue
+		}
+		labels = append(labels, [2]string{key, value})
+	}
+
+	return series[:start], labels
+}
+
+// prometheusName replaces characters that aren't allowed in Prometheus metric and label names.
+func prometheusName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		isLetter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || r == ':'
+		isDigit := r >= '0' && r <= '9'
+		switch {
+		case isLetter || isDigit && i > 0:
+			b.WriteRune(r)
+		case isDigit:
+			b.WriteByte('_')
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func prometheusLabels(labels [][2]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, prometheusName(label[0]), escaper.Replace(label[1])))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+
+	compress "github.com/Stern-Ritter/metrics-and-alerting-service/internal/compress/server"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/server"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/server"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	storage "github.com/Stern-Ritter/metrics-and-alerting-service/internal/storage/server"
+	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
+)
+
+type testExportMetricsServer struct {
+	grpc.ServerStream
+	sent []*pb.MetricsV1ServiceExportMetricsResponse
+}
+
+func (s *testExportMetricsServer) Context() context.Context {
+	return context.Background()
+}
+
+func (s *testExportMetricsServer) Send(resp *pb.MetricsV1ServiceExportMetricsResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func TestExportMetricsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockStorage(ctrl)
+	config := &server.ServerConfig{}
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	metricService := NewMetricService(mockStorage, logger)
+	s := NewServer(metricService, config, nil, nil, logger)
+
+	gauges := map[string]metrics.GaugeMetric{
+		"Alloc":                    metrics.NewGauge("Alloc", 1.5),
+		"http.latency{route=/a\"}": metrics.NewGauge("http.latency{route=/a\"}", 0.25),
+	}
+	counters := map[string]metrics.CounterMetric{"PollCount": metrics.NewCounter("PollCount", 7)}
+
+	tests := []struct {
+		name                string
+		url                 string
+		accept              string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "should export JSON by default",
+			url:                 "/export",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody: `[{"id":"Alloc","type":"gauge","value":1.5},{"id":"PollCount","type":"counter","delta":7},` +
+				`{"id":"http.latency{route=/a\"}","type":"gauge","value":0.25}]`,
+		},
+		{
+			name:                "should export NDJSON chosen by Accept header",
+			url:                 "/export",
+			accept:              "application/x-ndjson, */*;q=0.1",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: ndjsonMediaType,
+			expectedBody: `{"id":"Alloc","type":"gauge","value":1.5}
+{"id":"PollCount","type":"counter","delta":7}
+{"id":"http.latency{route=/a\"}","type":"gauge","value":0.25}
+`,
+		},
+		{
+			name:                "should export CSV chosen by query parameter",
+			url:                 "/export?format=csv",
+			accept:              "application/json",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: `name,type,value
+Alloc,gauge,1.5
+PollCount,counter,7
+"http.latency{route=/a""}",gauge,0.25
+`,
+		},
+		{
+			name:                "should export Prometheus text format",
+			url:                 "/export?format=prometheus",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: prometheusMediaType,
+			expectedBody: `# TYPE Alloc gauge
+Alloc 1.5
+# TYPE PollCount counter
+PollCount 7
+# TYPE http_latency gauge
+http_latency{route="/a\""} 0.25
+`,
+		},
+		{
+			name:           "should return status code 400 when format is unsupported",
+			url:            "/export?format=xml",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedStatus == http.StatusOK {
+				mockStorage.EXPECT().GetMetrics(gomock.Any()).Return(gauges, counters, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if len(tt.accept) > 0 {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			s.ExportMetricsHandler(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContentType, res.Header.Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+
+	t.Run("should compress export with gzip", func(t *testing.T) {
+		mockStorage.EXPECT().GetMetrics(gomock.Any()).Return(gauges, counters, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/export?format=prometheus", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		compress.GzipMiddleware(http.HandlerFunc(s.ExportMetricsHandler)).ServeHTTP(w, req)
+
+		res := w.Result()
+		defer res.Body.Close()
+		assert.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+
+		zr, err := gzip.NewReader(res.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "PollCount 7\n")
+	})
+}
+
+func TestExportMetrics(t *testing.T) {
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	memoryStorage := storage.NewMemoryStorage(logger)
+	metricService := NewMetricService(memoryStorage, logger)
+	s := NewServer(metricService, &server.ServerConfig{}, nil, nil, logger)
+
+	batch := make([]metrics.Metrics, 0, exportChunkSize+1)
+	for i := 0; i < exportChunkSize+1; i++ {
+		batch = append(batch, metrics.NewCounterMetrics(string(rune('a'+i%26))+string(rune('a'+i/26)), int64(i)))
+	}
+	require.NoError(t, memoryStorage.UpdateMetrics(context.Background(), batch))
+
+	stream := &testExportMetricsServer{}
+	err = s.ExportMetrics(&pb.MetricsV1ServiceExportMetricsRequest{}, stream)
+	require.NoError(t, err, "shouldn't return error, but got: %s", err)
+
+	require.Len(t, stream.sent, 2, "should send metrics in chunks")
+	assert.Len(t, stream.sent[0].Metrics, exportChunkSize)
+	assert.Len(t, stream.sent[1].Metrics, 1)
+	assert.Equal(t, "aa", stream.sent[0].Metrics[0].Name, "should send metrics sorted by name")
+}
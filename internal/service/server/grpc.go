@@ -2,10 +2,12 @@ package server
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	er "github.com/Stern-Ritter/metrics-and-alerting-service/internal/errors"
@@ -37,15 +39,21 @@ func (s *Server) UpdateMetric(ctx context.Context, in *pb.MetricsV1ServiceUpdate
 }
 
 // UpdateMetricsBatch updates multiple metrics based on request.
+// The batch identified by the agent ID and batch sequence metadata is applied only once.
 func (s *Server) UpdateMetricsBatch(ctx context.Context, in *pb.MetricsV1ServiceUpdateMetricsBatchRequest) (*pb.MetricsV1ServiceUpdateMetricsBatchResponse, error) {
 	err := in.Validate()
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	key, err := metrics.ParseBatchKey(metadataValue(ctx, agentIDKey), metadataValue(ctx, batchSequenceKey))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	metrics := metrics.RepeatedMetricDataToMetrics(in.Metrics)
 
-	err = s.MetricService.UpdateMetricsBatchWithBody(ctx, metrics, s.isSyncSaveStorageState(),
+	err = s.MetricService.UpdateMetricsBatchOnce(ctx, key, metrics, s.isSyncSaveStorageState(),
 		s.Config.FileStoragePath)
 
 	if err != nil {
@@ -61,6 +69,74 @@ func (s *Server) UpdateMetricsBatch(ctx context.Context, in *pb.MetricsV1Service
 	return &pb.MetricsV1ServiceUpdateMetricsBatchResponse{}, nil
 }
 
+// StreamMetrics updates metrics batches received over a long-lived bidirectional stream.
+// Each batch is acknowledged with its sequence number. When the stream metadata holds the agent ID,
+// the batch sequence number is used as the batch key and the batch is applied only once. A batch that
+// fails to be applied is acknowledged with the error code and message, so it doesn't close the stream.
+func (s *Server) StreamMetrics(stream pb.MetricsV1Service_StreamMetricsServer) error {
+	agentID := metadataValue(stream.Context(), agentIDKey)
+	for {
+		in, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &pb.MetricsV1ServiceStreamMetricsResponse{Sequence: in.Sequence}
+		if err := s.streamMetricsBatch(stream.Context(), agentID, in); err != nil {
+			resp.Code = uint32(status.Code(err))
+			resp.Error = status.Convert(err).Message()
+		}
+
+		err = stream.Send(resp)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// streamMetricsBatch validates and applies the metrics batch received over the stream.
+func (s *Server) streamMetricsBatch(ctx context.Context, agentID string, in *pb.MetricsV1ServiceStreamMetricsRequest) error {
+	err := in.Validate()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	key := metrics.BatchKey{AgentID: agentID, Sequence: in.Sequence}
+	metrics := metrics.RepeatedMetricDataToMetrics(in.Metrics)
+
+	err = s.MetricService.UpdateMetricsBatchOnce(ctx, key, metrics, s.isSyncSaveStorageState(),
+		s.Config.FileStoragePath)
+
+	if err != nil {
+		var invalidMetricType er.InvalidMetricType
+		var invalidMetricValue er.InvalidMetricValue
+		if errors.As(err, &invalidMetricType) || errors.As(err, &invalidMetricValue) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// metadataValue returns the first value of the key in the incoming request metadata.
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
 // GetMetric retrieves the data of a single metric based on request.
 func (s *Server) GetMetric(ctx context.Context, in *pb.MetricsV1ServiceGetMetricRequest) (*pb.MetricsV1ServiceGetMetricResponse, error) {
 	err := in.Validate()
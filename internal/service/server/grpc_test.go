@@ -2,14 +2,17 @@ package server
 
 import (
 	"context"
+	"io"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/server"
 	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/server"
@@ -179,6 +182,89 @@ func TestUpdateMetricsBatch(t *testing.T) {
 	}
 }
 
+func TestStreamMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockStorage(ctrl)
+	config := &server.ServerConfig{}
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	metricService := NewMetricService(mockStorage, logger)
+	s := NewServer(metricService, config, nil, nil, logger)
+
+	validMetrics := []*pb.MetricData{
+		{
+			Name:        gaugeMetricName,
+			Type:        gaugeMetricType,
+			MetricValue: &pb.MetricData_Value{Value: gaugeMetricValue},
+		},
+	}
+
+	t.Run("should update metrics and acknowledge each batch with its sequence", func(t *testing.T) {
+		mockStorage.
+			EXPECT().
+			UpdateMetrics(gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(2)
+
+		stream := &testStreamMetricsServer{
+			ctx: context.Background(),
+			reqs: []*pb.MetricsV1ServiceStreamMetricsRequest{
+				{Sequence: 1, Metrics: validMetrics},
+				{Sequence: 2, Metrics: validMetrics},
+			},
+		}
+
+		err := s.StreamMetrics(stream)
+		require.NoError(t, err, "shouldn't return error, but got: %s", err)
+		require.Len(t, stream.sent, 2, "should acknowledge each batch")
+		assert.Equal(t, uint64(1), stream.sent[0].Sequence)
+		assert.Equal(t, uint64(2), stream.sent[1].Sequence)
+	})
+
+	t.Run("should acknowledge invalid batch with error and keep receiving batches", func(t *testing.T) {
+		mockStorage.
+			EXPECT().
+			UpdateMetrics(gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		stream := &testStreamMetricsServer{
+			ctx: context.Background(),
+			reqs: []*pb.MetricsV1ServiceStreamMetricsRequest{
+				{Sequence: 1},
+				{Sequence: 2, Metrics: validMetrics},
+			},
+		}
+
+		err := s.StreamMetrics(stream)
+		require.NoError(t, err, "shouldn't return error, but got: %s", err)
+		require.Len(t, stream.sent, 2, "should acknowledge each batch")
+		assert.Equal(t, uint64(1), stream.sent[0].Sequence)
+		assert.Equal(t, uint32(codes.InvalidArgument), stream.sent[0].Code, "should acknowledge invalid batch with InvalidArgument code")
+		assert.NotEmpty(t, stream.sent[0].Error, "should acknowledge invalid batch with error message")
+		assert.Equal(t, uint64(2), stream.sent[1].Sequence)
+		assert.Equal(t, uint32(codes.OK), stream.sent[1].Code)
+	})
+
+	t.Run("should acknowledge batch with error when storage update failed", func(t *testing.T) {
+		mockStorage.
+			EXPECT().
+			UpdateMetrics(gomock.Any(), gomock.Any()).
+			Return(errors.New("storage error"))
+
+		stream := &testStreamMetricsServer{
+			ctx:  context.Background(),
+			reqs: []*pb.MetricsV1ServiceStreamMetricsRequest{{Sequence: 1, Metrics: validMetrics}},
+		}
+
+		err := s.StreamMetrics(stream)
+		require.NoError(t, err, "shouldn't return error, but got: %s", err)
+		require.Len(t, stream.sent, 1, "should acknowledge the batch")
+		assert.Equal(t, uint32(codes.Internal), stream.sent[0].Code, "should acknowledge batch with Internal code")
+	})
+}
+
 func TestGetMetric(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -363,3 +449,36 @@ func TestPing(t *testing.T) {
 		})
 	}
 }
+
+type testStreamMetricsServer struct {
+	grpc.ServerStream
+	ctx  context.Context
+	reqs []*pb.MetricsV1ServiceStreamMetricsRequest
+	sent []*pb.MetricsV1ServiceStreamMetricsResponse
+}
+
+func (s *testStreamMetricsServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testStreamMetricsServer) Send(resp *pb.MetricsV1ServiceStreamMetricsResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func (s *testStreamMetricsServer) Recv() (*pb.MetricsV1ServiceStreamMetricsRequest, error) {
+	req := &pb.MetricsV1ServiceStreamMetricsRequest{}
+	if err := s.RecvMsg(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *testStreamMetricsServer) RecvMsg(m interface{}) error {
+	if len(s.reqs) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.reqs[0])
+	s.reqs = s.reqs[1:]
+	return nil
+}
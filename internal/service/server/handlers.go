@@ -17,6 +17,11 @@ import (
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
 )
 
+const (
+	agentIDKey       = "X-Agent-ID"
+	batchSequenceKey = "X-Batch-Sequence"
+)
+
 // UpdateMetricHandlerWithPathVars updates a metric using request path variables.
 func (s *Server) UpdateMetricHandlerWithPathVars(res http.ResponseWriter, req *http.Request) {
 	mName := chi.URLParam(req, "name")
@@ -68,14 +73,21 @@ func (s *Server) UpdateMetricHandlerWithBody(res http.ResponseWriter, req *http.
 }
 
 // UpdateMetricsBatchHandlerWithBody updates a batch of metrics using the request body.
+// The batch identified by the agent ID and batch sequence headers is applied only once.
 func (s *Server) UpdateMetricsBatchHandlerWithBody(res http.ResponseWriter, req *http.Request) {
+	key, err := metrics.ParseBatchKey(req.Header.Get(agentIDKey), req.Header.Get(batchSequenceKey))
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	metrics, err := decodeMetricsBatch(req.Body)
 	if err != nil {
 		http.Error(res, "Error decode request JSON body", http.StatusBadRequest)
 		return
 	}
 
-	err = s.MetricService.UpdateMetricsBatchWithBody(req.Context(), metrics,
+	err = s.MetricService.UpdateMetricsBatchOnce(req.Context(), key, metrics,
 		s.isSyncSaveStorageState(), s.Config.FileStoragePath)
 
 	if err != nil {
@@ -31,6 +31,12 @@ func (m *ExampleMockStorage) UpdateMetrics(ctx context.Context, metricsBatch []m
 	return args.Error(0)
 }
 
+func (m *ExampleMockStorage) UpdateMetricsOnce(ctx context.Context, key metrics.BatchKey,
+	metricsBatch []metrics.Metrics) (bool, error) {
+	args := m.Called(ctx, key, metricsBatch)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *ExampleMockStorage) GetMetric(ctx context.Context, metric metrics.Metrics) (metrics.Metrics, error) {
 	args := m.Called(ctx, metric)
 	return args.Get(0).(metrics.Metrics), args.Error(1)
@@ -378,6 +378,72 @@ func TestUpdateMetricsBatchHandlerWithBody(t *testing.T) {
 	}
 }
 
+func TestUpdateMetricsBatchHandlerWithBody_BatchKey(t *testing.T) {
+	testCases := []struct {
+		name            string
+		sequence        string
+		useStorage      bool
+		storageApplied  bool
+		wantCode        int
+		wantKeySequence uint64
+	}{
+		{
+			name:            "should apply new batch with key",
+			sequence:        "42",
+			useStorage:      true,
+			storageApplied:  true,
+			wantCode:        http.StatusOK,
+			wantKeySequence: 42,
+		},
+		{
+			name:            "should acknowledge already applied batch with key",
+			sequence:        "42",
+			useStorage:      true,
+			storageApplied:  false,
+			wantCode:        http.StatusOK,
+			wantKeySequence: 42,
+		},
+		{
+			name:       "should return status code 400 when batch sequence is invalid",
+			sequence:   "forty-two",
+			useStorage: false,
+			wantCode:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := NewMockStorage(ctrl)
+			if tt.useStorage {
+				mockStorage.
+					EXPECT().
+					UpdateMetricsOnce(gomock.Any(), metrics.BatchKey{AgentID: "agent", Sequence: tt.wantKeySequence}, gomock.Any()).
+					Return(tt.storageApplied, nil)
+			}
+
+			config := &server.ServerConfig{}
+			logger, err := logger.Initialize("info")
+			require.NoError(t, err, "Error init logger")
+			metricService := NewMetricService(mockStorage, logger)
+			s := NewServer(metricService, config, nil, nil, logger)
+
+			server := httptest.NewServer(http.HandlerFunc(s.UpdateMetricsBatchHandlerWithBody))
+			defer server.Close()
+
+			resp, err := resty.New().R().
+				SetHeader(agentIDKey, "agent").
+				SetHeader(batchSequenceKey, tt.sequence).
+				SetBody(`[{ "id": "PoolCount", "type": "counter", "delta": 10}]`).
+				Post(fmt.Sprintf("%s/updates", server.URL))
+			require.NoError(t, err, "Error making HTTP request")
+			assert.Equal(t, tt.wantCode, resp.StatusCode(), "Response code didn't match expected")
+		})
+	}
+}
+
 func TestGetMetricHandlerWithPathVars(t *testing.T) {
 	type want struct {
 		code int
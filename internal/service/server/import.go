@@ -0,0 +1,295 @@
+package server
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	er "github.com/Stern-Ritter/metrics-and-alerting-service/internal/errors"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+const (
+	ndjsonMediaType = "application/x-ndjson"
+	csvMediaType    = "text/csv"
+
+	importChunkSize       = 500
+	importMaxLineSize     = 1024 * 1024
+	importMaxReportErrors = 1000
+)
+
+// ImportLineError describes an import line that wasn't imported.
+type ImportLineError struct {
+	Line  int    `json:"line"`  // Line is the number of the line in the request body, starting from 1
+	Error string `json:"error"` // Error is the reason the line wasn't imported
+}
+
+// ImportReport is the result of a metrics import.
+type ImportReport struct {
+	Imported        int               `json:"imported"`                  // Imported is the number of imported lines
+	Skipped         int               `json:"skipped"`                   // Skipped is the number of outdated gauge lines
+	Failed          int               `json:"failed"`                    // Failed is the number of lines with errors
+	Errors          []ImportLineError `json:"errors"`                    // Errors holds the first line errors
+	ErrorsTruncated bool              `json:"errorsTruncated,omitempty"` // ErrorsTruncated is set when not all line errors are reported
+}
+
+func (r *ImportReport) addError(line int, err error) {
+	r.Failed++
+	if len(r.Errors) >= importMaxReportErrors {
+		r.ErrorsTruncated = true
+		return
+	}
+	r.Errors = append(r.Errors, ImportLineError{Line: line, Error: err.Error()})
+}
+
+// importLine is a parsed import line.
+type importLine struct {
+	number    int
+	metric    metrics.Metrics
+	timestamp *time.Time
+}
+
+// metricsImporter commits parsed lines to the storage in chunks.
+type metricsImporter struct {
+	s               *Server
+	report          *ImportReport
+	chunk           []importLine
+	gaugeTimestamps map[string]time.Time
+}
+
+func (i *metricsImporter) add(req *http.Request, line importLine) error {
+	if line.timestamp != nil && metrics.MetricType(line.metric.MType) == metrics.Gauge {
+		last, ok := i.gaugeTimestamps[line.metric.ID]
+		if ok && line.timestamp.Before(last) {
+			i.report.Skipped++
+			return nil
+		}
+		i.gaugeTimestamps[line.metric.ID] = *line.timestamp
+	}
+
+	i.chunk = append(i.chunk, line)
+	if len(i.chunk) >= importChunkSize {
+		return i.commit(req)
+	}
+	return nil
+}
+
+func (i *metricsImporter) commit(req *http.Request) error {
+	if len(i.chunk) == 0 {
+		return nil
+	}
+
+	batch := make([]metrics.Metrics, 0, len(i.chunk))
+	for _, line := range i.chunk {
+		batch = append(batch, line.metric)
+	}
+
+	err := i.s.MetricService.UpdateMetricsBatchWithBody(req.Context(), batch,
+		i.s.isSyncSaveStorageState(), i.s.Config.FileStoragePath)
+	if err != nil {
+		for _, line := range i.chunk {
+			i.report.addError(line.number, err)
+		}
+		i.chunk = i.chunk[:0]
+		return err
+	}
+
+	i.report.Imported += len(i.chunk)
+	i.chunk = i.chunk[:0]
+	return nil
+}
+
+// ImportMetricsHandler imports metrics from the request body streamed line by line.
+// The body is NDJSON of metrics objects (application/x-ndjson) or CSV with
+// name,type,value[,timestamp] records (text/csv). Valid lines are committed in chunks,
+// invalid lines are listed in the response report. When a line has a timestamp,
+// an older gauge value than the one already imported is skipped.
+func (s *Server) ImportMetricsHandler(res http.ResponseWriter, req *http.Request) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+
+	var parse func(body io.Reader, add func(line importLine) error, report *ImportReport) error
+	switch mediaType {
+	case ndjsonMediaType:
+		parse = parseNDJSONImport
+	case csvMediaType:
+		parse = parseCSVImport
+	default:
+		http.Error(res, fmt.Sprintf("Unsupported content type: %s", req.Header.Get("Content-Type")),
+			http.StatusUnsupportedMediaType)
+		return
+	}
+
+	report := &ImportReport{Errors: make([]ImportLineError, 0)}
+	importer := &metricsImporter{s: s, report: report, gaugeTimestamps: make(map[string]time.Time)}
+
+	statusCode := http.StatusOK
+	err = parse(req.Body, func(line importLine) error { return importer.add(req, line) }, report)
+	if err == nil {
+		err = importer.commit(req)
+	}
+	if err != nil {
+		s.Logger.Error(err.Error(), zap.String("event", "import metrics"))
+		statusCode = http.StatusInternalServerError
+	}
+
+	resp, err := json.Marshal(report)
+	if err != nil {
+		http.Error(res, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(statusCode)
+	_, err = res.Write(resp)
+	if err != nil {
+		s.Logger.Error(err.Error(), zap.String("event", "import metrics"))
+	}
+}
+
+func parseNDJSONImport(body io.Reader, add func(line importLine) error, report *ImportReport) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), importMaxLineSize)
+
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 {
+			continue
+		}
+
+		var m metrics.Metrics
+		if err := json.Unmarshal([]byte(text), &m); err != nil {
+			report.addError(number, err)
+			continue
+		}
+		if err := validateImportedMetrics(m); err != nil {
+			report.addError(number, err)
+			continue
+		}
+
+		if err := add(importLine{number: number, metric: m}); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		report.addError(number+1, err)
+		return err
+	}
+	return nil
+}
+
+func parseCSVImport(body io.Reader, add func(line importLine) error, report *ImportReport) error {
+	r := csv.NewReader(body)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.ReuseRecord = true
+
+	first := true
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.addError(parseErr.Line, err)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		number, _ := r.FieldPos(0)
+		isHeader := first && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "name")
+		first = false
+		if isHeader {
+			continue
+		}
+
+		line, err := parseCSVImportRecord(record)
+		if err != nil {
+			report.addError(number, err)
+			continue
+		}
+		line.number = number
+
+		if err := add(line); err != nil {
+			return err
+		}
+	}
+}
+
+func parseCSVImportRecord(record []string) (importLine, error) {
+	if len(record) != 3 && len(record) != 4 {
+		return importLine{}, fmt.Errorf("expected name,type,value[,timestamp] fields, got %d fields", len(record))
+	}
+
+	name := strings.TrimSpace(record[0])
+	if len(name) == 0 {
+		return importLine{}, er.NewInvalidMetricName("Metric name is empty", nil)
+	}
+
+	m, err := metrics.NewMetricsWithStringValue(name, strings.TrimSpace(record[1]), strings.TrimSpace(record[2]))
+	if err != nil {
+		return importLine{}, err
+	}
+
+	line := importLine{metric: m}
+	if len(record) == 4 {
+		ts, err := parseImportTimestamp(strings.TrimSpace(record[3]))
+		if err != nil {
+			return importLine{}, err
+		}
+		line.timestamp = &ts
+	}
+
+	return line, nil
+}
+
+// parseImportTimestamp parses a timestamp in RFC 3339 format or in Unix seconds.
+func parseImportTimestamp(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	ts, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: expected RFC 3339 or Unix seconds", value)
+	}
+	return ts, nil
+}
+
+func validateImportedMetrics(m metrics.Metrics) error {
+	if len(strings.TrimSpace(m.ID)) == 0 {
+		return er.NewInvalidMetricName("Metric name is empty", nil)
+	}
+
+	switch metrics.MetricType(m.MType) {
+	case metrics.Gauge:
+		if m.Value == nil {
+			return er.NewInvalidMetricValue(fmt.Sprintf("The value for the %s metric is missing", metrics.Gauge), nil)
+		}
+	case metrics.Counter:
+		if m.Delta == nil {
+			return er.NewInvalidMetricValue(fmt.Sprintf("The delta for the %s metric is missing", metrics.Counter), nil)
+		}
+	default:
+		return er.NewInvalidMetricType(fmt.Sprintf("Invalid metric type: %s", m.MType), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/server"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/server"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func TestImportMetricsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockStorage(ctrl)
+	config := &server.ServerConfig{}
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	metricService := NewMetricService(mockStorage, logger)
+	s := NewServer(metricService, config, nil, nil, logger)
+
+	doImport := func(t *testing.T, contentType string, body string) (int, ImportReport) {
+		req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		s.ImportMetricsHandler(w, req)
+
+		res := w.Result()
+		defer res.Body.Close()
+
+		var report ImportReport
+		if res.StatusCode != http.StatusUnsupportedMediaType {
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&report))
+		}
+		return res.StatusCode, report
+	}
+
+	t.Run("should import valid NDJSON lines and report invalid lines", func(t *testing.T) {
+		mockStorage.
+			EXPECT().
+			UpdateMetrics(gomock.Any(), []metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 1.5), metrics.NewCounterMetrics("PollCount", 3)}).
+			Return(nil)
+
+		body := `{"id":"Alloc","type":"gauge","value":1.5}
+
+{"id":"Frees","type":"gauge"}
+{"id":"PollCount","type":"counter","delta":3}
+{"id":"Bad","type":"unknown","value":1}
+not json
+`
+		statusCode, report := doImport(t, ndjsonMediaType, body)
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, 2, report.Imported)
+		assert.Equal(t, 3, report.Failed)
+		require.Len(t, report.Errors, 3)
+		assert.Equal(t, []int{3, 5, 6}, []int{report.Errors[0].Line, report.Errors[1].Line, report.Errors[2].Line})
+		assert.Equal(t, "The value for the gauge metric is missing", report.Errors[0].Error)
+	})
+
+	t.Run("should import CSV records with header and skip outdated gauge values", func(t *testing.T) {
+		mockStorage.
+			EXPECT().
+			UpdateMetrics(gomock.Any(), []metrics.Metrics{
+				metrics.NewGaugeMetrics("Alloc", 2),
+				metrics.NewCounterMetrics("PollCount", 4),
+				metrics.NewCounterMetrics("PollCount", 1),
+			}).
+			Return(nil)
+
+		body := `name,type,value,timestamp
+Alloc,gauge,2,2024-01-01T00:00:10Z
+Alloc,gauge,1,2024-01-01T00:00:05Z
+PollCount,counter,4
+PollCount,counter,1,1704067200
+Frees,gauge,abc
+Frees,gauge,1,yesterday
+Frees,gauge
+`
+		statusCode, report := doImport(t, "text/csv; charset=utf-8", body)
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, 3, report.Imported)
+		assert.Equal(t, 1, report.Skipped)
+		assert.Equal(t, 3, report.Failed)
+		require.Len(t, report.Errors, 3)
+		assert.Equal(t, 6, report.Errors[0].Line)
+		assert.Equal(t, "The value for the gauge metric should be of float64 type", report.Errors[0].Error)
+		assert.Equal(t, 7, report.Errors[1].Line)
+		assert.Equal(t, 8, report.Errors[2].Line)
+	})
+
+	t.Run("should commit lines in chunks", func(t *testing.T) {
+		var body strings.Builder
+		for i := 0; i < importChunkSize+1; i++ {
+			fmt.Fprintf(&body, "m%d,counter,1\n", i)
+		}
+
+		gomock.InOrder(
+			mockStorage.EXPECT().UpdateMetrics(gomock.Any(), gomock.Len(importChunkSize)).Return(nil),
+			mockStorage.EXPECT().UpdateMetrics(gomock.Any(), gomock.Len(1)).Return(nil),
+		)
+
+		statusCode, report := doImport(t, csvMediaType, body.String())
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, importChunkSize+1, report.Imported)
+	})
+
+	t.Run("should report chunk lines and return status code 500 when storage update failed", func(t *testing.T) {
+		mockStorage.
+			EXPECT().
+			UpdateMetrics(gomock.Any(), gomock.Any()).
+			Return(assert.AnError)
+
+		statusCode, report := doImport(t, csvMediaType, "Alloc,gauge,1\nFrees,gauge,2\n")
+
+		assert.Equal(t, http.StatusInternalServerError, statusCode)
+		assert.Equal(t, 0, report.Imported)
+		assert.Equal(t, 2, report.Failed)
+	})
+
+	t.Run("should return status code 415 when content type is unsupported", func(t *testing.T) {
+		statusCode, _ := doImport(t, "application/json", "[]")
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, statusCode)
+	})
+}
@@ -23,6 +23,7 @@ type MetricService struct {
 	storage              storage.Storage
 	logger               *logger.ServerLogger
 	storageRetryInterval *backoff.ExponentialBackOff
+	broker               *MetricsBroker
 }
 
 // NewMetricService is constructor for creating a new MetricService.
@@ -34,7 +35,8 @@ func NewMetricService(storage storage.Storage, logger *logger.ServerLogger) *Met
 		backoff.WithMaxInterval(5*time.Second),
 		backoff.WithMaxElapsedTime(10*time.Second))
 
-	return &MetricService{storage: storage, logger: logger, storageRetryInterval: storageRetryInterval}
+	return &MetricService{storage: storage, logger: logger, storageRetryInterval: storageRetryInterval,
+		broker: NewMetricsBroker(defaultSubscriptionMaxPending)}
 }
 
 // UpdateMetricWithPathVars updates a metric using string params.
@@ -59,6 +61,7 @@ func (s *MetricService) UpdateMetricWithPathVars(ctx context.Context, mName stri
 	if err = backoff.Retry(update, s.storageRetryInterval); err != nil {
 		return err
 	}
+	s.publishUpdates(ctx, []metrics.Metrics{m})
 
 	if isSyncSaveStorageState {
 		err := s.SaveStateToFile(filePath)
@@ -105,6 +108,7 @@ func (s *MetricService) UpdateMetricWithBody(ctx context.Context, metric metrics
 	if err := backoff.Retry(get, s.storageRetryInterval); err != nil {
 		return metrics.Metrics{}, err
 	}
+	s.publishUpdates(ctx, []metrics.Metrics{m})
 
 	if isSyncSaveStorageState {
 		err := s.SaveStateToFile(filePath)
@@ -136,6 +140,49 @@ func (s *MetricService) UpdateMetricsBatchWithBody(ctx context.Context, metrics
 	if err := backoff.Retry(updateBatch, s.storageRetryInterval); err != nil {
 		return err
 	}
+	s.publishUpdates(ctx, metrics)
+
+	if isSyncSaveStorageState {
+		err := s.SaveStateToFile(filePath)
+		if err != nil {
+			s.logger.Error(err.Error(), zap.String("event", "sync save to file storage"))
+		} else {
+			s.logger.Info("Success sync save to file storage", zap.String("event", "sync save to file storage"))
+		}
+	}
+	return nil
+}
+
+// UpdateMetricsBatchOnce updates a slice of metrics sent as the batch with the key. The batch that was
+// recently applied is acknowledged without updating the metrics again. The batch without key is always applied.
+func (s *MetricService) UpdateMetricsBatchOnce(ctx context.Context, key metrics.BatchKey, metricsBatch []metrics.Metrics,
+	isSyncSaveStorageState bool, filePath string) error {
+	if key.IsZero() {
+		return s.UpdateMetricsBatchWithBody(ctx, metricsBatch, isSyncSaveStorageState, filePath)
+	}
+
+	var applied bool
+	updateBatch := func() error {
+		var err error
+		applied, err = s.storage.UpdateMetricsOnce(ctx, key, metricsBatch)
+		if isDatabaseConnectionError(err) {
+			s.logger.Error(err.Error(), zap.String("event", "failed try update metric batch"))
+			return err
+		} else if err != nil {
+			return backoff.Permanent(err)
+		}
+		return nil
+	}
+
+	if err := backoff.Retry(updateBatch, s.storageRetryInterval); err != nil {
+		return err
+	}
+	if !applied {
+		s.logger.Info("Metrics batch already applied", zap.String("event", "update metric batch"),
+			zap.String("agent id", key.AgentID), zap.Uint64("sequence", key.Sequence))
+		return nil
+	}
+	s.publishUpdates(ctx, metricsBatch)
 
 	if isSyncSaveStorageState {
 		err := s.SaveStateToFile(filePath)
@@ -222,6 +269,63 @@ func (s *MetricService) GetMetrics(ctx context.Context) (map[string]metrics.Gaug
 	return gauges, counters, err
 }
 
+// Subscribe registers a subscription to updates of metrics selected by the filter.
+// The subscription must be released with Unsubscribe.
+func (s *MetricService) Subscribe(filter MetricsFilter) *MetricsSubscription {
+	return s.broker.Subscribe(filter)
+}
+
+// Unsubscribe releases the subscription.
+func (s *MetricService) Unsubscribe(sub *MetricsSubscription) {
+	s.broker.Unsubscribe(sub)
+}
+
+// GetFilteredMetrics returns current values of all metrics selected by the filter.
+func (s *MetricService) GetFilteredMetrics(ctx context.Context, filter MetricsFilter) ([]metrics.Metrics, error) {
+	gauges, counters, err := s.GetMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ms := make([]metrics.Metrics, 0)
+	for _, gauge := range gauges {
+		if filter.Match(gauge.Name) {
+			ms = append(ms, metrics.GaugeMetricToMetrics(gauge))
+		}
+	}
+	for _, counter := range counters {
+		if filter.Match(counter.Name) {
+			ms = append(ms, metrics.CounterMetricToMetrics(counter))
+		}
+	}
+
+	return ms, nil
+}
+
+// publishUpdates reads current values of the updated metrics watched by subscribers
+// and publishes them to the broker. Counter updates hold only increments, so the values
+// are read back from the storage.
+func (s *MetricService) publishUpdates(ctx context.Context, updates []metrics.Metrics) {
+	seen := make(map[string]struct{}, len(updates))
+	current := make([]metrics.Metrics, 0)
+	for _, u := range updates {
+		key := u.MType + ":" + u.ID
+		if _, ok := seen[key]; ok || !s.broker.Watched(u.ID) {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		m, err := s.storage.GetMetric(ctx, metrics.Metrics{ID: u.ID, MType: u.MType})
+		if err != nil {
+			s.logger.Error(err.Error(), zap.String("event", "get updated metric for subscribers"))
+			continue
+		}
+		current = append(current, m)
+	}
+
+	s.broker.Publish(current)
+}
+
 // SaveStateToFile saves the storage state to a file.
 func (s *MetricService) SaveStateToFile(filePath string) error {
 	save := func() error {
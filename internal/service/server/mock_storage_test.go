@@ -154,3 +154,18 @@ func (mr *MockStorageMockRecorder) UpdateMetrics(ctx, metrics any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetrics", reflect.TypeOf((*MockStorage)(nil).UpdateMetrics), ctx, metrics)
 }
+
+// UpdateMetricsOnce mocks base method.
+func (m *MockStorage) UpdateMetricsOnce(ctx context.Context, key metrics.BatchKey, metrics []metrics.Metrics) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMetricsOnce", ctx, key, metrics)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMetricsOnce indicates an expected call of UpdateMetricsOnce.
+func (mr *MockStorageMockRecorder) UpdateMetricsOnce(ctx, key, metrics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricsOnce", reflect.TypeOf((*MockStorage)(nil).UpdateMetricsOnce), ctx, key, metrics)
+}
@@ -0,0 +1,384 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	er "github.com/Stern-Ritter/metrics-and-alerting-service/internal/errors"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
+)
+
+const (
+	otlpExportMethod       = "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export"
+	otlpProtobufMediaType  = "application/x-protobuf"
+	otlpJSONMediaType      = "application/json"
+	otlpHistogramCount     = "_count"
+	otlpHistogramSum       = "_sum"
+	otlpHistogramBucket    = "_bucket"
+	otlpHistogramInfBucket = "+Inf"
+	otlpMaxBodySize        = 16 * 1024 * 1024
+	otlpSeriesTTL          = time.Hour
+	otlpMaxSeries          = 100000
+)
+
+// otlpSeriesState holds the last seen state of a single OTLP time series.
+type otlpSeriesState struct {
+	start    uint64
+	value    float64
+	lastSeen time.Time
+}
+
+// OTLPConverter converts OpenTelemetry (OTLP) metrics data points to the service metrics model.
+//
+// Monotonic sums and histogram counts are converted to counter metrics: delta temporality values
+// are used as is, cumulative temporality values are converted to deltas against the last seen value
+// of the same series. Non-monotonic sums and gauges are converted to gauge metrics.
+// Exponential histograms and summaries are not supported and are rejected.
+//
+// The state of a series that isn't seen for an hour is evicted, and the number of tracked series is limited
+// to 100000 by evicting the least recently seen ones. The next data point of an evicted series is handled
+// as the first one of the series.
+type OTLPConverter struct {
+	mu        sync.Mutex
+	series    map[string]otlpSeriesState
+	ttl       time.Duration
+	maxSeries int
+	lastEvict time.Time
+	now       func() time.Time
+}
+
+// NewOTLPConverter is constructor for creating a new OTLPConverter.
+func NewOTLPConverter() *OTLPConverter {
+	return &OTLPConverter{
+		series:    make(map[string]otlpSeriesState),
+		ttl:       otlpSeriesTTL,
+		maxSeries: otlpMaxSeries,
+		now:       time.Now,
+	}
+}
+
+// Convert converts OTLP resource metrics to a slice of Metrics.
+// It returns the converted metrics and the number of rejected data points.
+func (c *OTLPConverter) Convert(resourceMetrics []*metricspb.ResourceMetrics) ([]metrics.Metrics, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictExpiredSeries()
+
+	result := make([]metrics.Metrics, 0)
+	var rejected int64
+
+	for _, rm := range resourceMetrics {
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				switch data := m.GetData().(type) {
+				case *metricspb.Metric_Gauge:
+					result = append(result, c.convertGauge(m.GetName(), data.Gauge)...)
+				case *metricspb.Metric_Sum:
+					result = append(result, c.convertSum(m.GetName(), data.Sum)...)
+				case *metricspb.Metric_Histogram:
+					result = append(result, c.convertHistogram(m.GetName(), data.Histogram)...)
+				case *metricspb.Metric_ExponentialHistogram:
+					rejected += int64(len(data.ExponentialHistogram.GetDataPoints()))
+				case *metricspb.Metric_Summary:
+					rejected += int64(len(data.Summary.GetDataPoints()))
+				}
+			}
+		}
+	}
+
+	return result, rejected
+}
+
+func (c *OTLPConverter) convertGauge(name string, gauge *metricspb.Gauge) []metrics.Metrics {
+	result := make([]metrics.Metrics, 0, len(gauge.GetDataPoints()))
+	for _, dp := range gauge.GetDataPoints() {
+		result = append(result, metrics.NewGaugeMetrics(otlpSeriesName(name, dp.GetAttributes()), numberDataPointValue(dp)))
+	}
+	return result
+}
+
+func (c *OTLPConverter) convertSum(name string, sum *metricspb.Sum) []metrics.Metrics {
+	isDelta := sum.GetAggregationTemporality() == metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+
+	result := make([]metrics.Metrics, 0, len(sum.GetDataPoints()))
+	for _, dp := range sum.GetDataPoints() {
+		seriesName := otlpSeriesName(name, dp.GetAttributes())
+		value := numberDataPointValue(dp)
+
+		switch {
+		case sum.GetIsMonotonic() && isDelta:
+			result = append(result, metrics.NewCounterMetrics(seriesName, int64(math.Round(value))))
+		case sum.GetIsMonotonic():
+			result = append(result, metrics.NewCounterMetrics(seriesName, c.cumulativeDelta(seriesName, dp.GetStartTimeUnixNano(), value)))
+		case isDelta:
+			result = append(result, metrics.NewGaugeMetrics(seriesName, c.deltaTotal(seriesName, value)))
+		default:
+			result = append(result, metrics.NewGaugeMetrics(seriesName, value))
+		}
+	}
+	return result
+}
+
+func (c *OTLPConverter) convertHistogram(name string, histogram *metricspb.Histogram) []metrics.Metrics {
+	isDelta := histogram.GetAggregationTemporality() == metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+
+	counter := func(seriesName string, start uint64, value float64) metrics.Metrics {
+		if isDelta {
+			return metrics.NewCounterMetrics(seriesName, int64(math.Round(value)))
+		}
+		return metrics.NewCounterMetrics(seriesName, c.cumulativeDelta(seriesName, start, value))
+	}
+
+	result := make([]metrics.Metrics, 0)
+	for _, dp := range histogram.GetDataPoints() {
+		start := dp.GetStartTimeUnixNano()
+
+		countName := otlpSeriesName(name+otlpHistogramCount, dp.GetAttributes())
+		result = append(result, counter(countName, start, float64(dp.GetCount())))
+
+		if dp.Sum != nil {
+			sumName := otlpSeriesName(name+otlpHistogramSum, dp.GetAttributes())
+			sumValue := dp.GetSum()
+			if isDelta {
+				sumValue = c.deltaTotal(sumName, sumValue)
+			}
+			result = append(result, metrics.NewGaugeMetrics(sumName, sumValue))
+		}
+
+		var bucketCount uint64
+		for i, count := range dp.GetBucketCounts() {
+			bucketCount += count
+			le := otlpHistogramInfBucket
+			if i < len(dp.GetExplicitBounds()) {
+				le = utils.FormatGaugeMetricValue(dp.GetExplicitBounds()[i])
+			}
+			attrs := append([]*commonpb.KeyValue{{Key: "le",
+				Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: le}}}}, dp.GetAttributes()...)
+			bucketName := otlpSeriesName(name+otlpHistogramBucket, attrs)
+			result = append(result, counter(bucketName, start, float64(bucketCount)))
+		}
+	}
+	return result
+}
+
+// cumulativeDelta returns the difference between the cumulative value and the last seen value of the series.
+// The whole value is returned when the series is seen for the first time or has been reset.
+func (c *OTLPConverter) cumulativeDelta(seriesName string, start uint64, value float64) int64 {
+	prev, exists := c.series[seriesName]
+	c.setSeries(seriesName, otlpSeriesState{start: start, value: value})
+
+	isReset := !exists || prev.start != start || value < prev.value
+	if isReset {
+		return int64(math.Round(value))
+	}
+
+	return int64(math.Round(value)) - int64(math.Round(prev.value))
+}
+
+// deltaTotal adds the delta value to the running total of the series and returns the new total.
+func (c *OTLPConverter) deltaTotal(seriesName string, value float64) float64 {
+	state := c.series[seriesName]
+	state.value += value
+	c.setSeries(seriesName, state)
+	return state.value
+}
+
+// setSeries stores the state of the series. When the series is new and the number of tracked series
+// reaches the limit, the least recently seen series is evicted.
+func (c *OTLPConverter) setSeries(seriesName string, state otlpSeriesState) {
+	if _, exists := c.series[seriesName]; !exists && len(c.series) >= c.maxSeries {
+		c.evictOldestSeries()
+	}
+
+	state.lastSeen = c.now()
+	c.series[seriesName] = state
+}
+
+// evictExpiredSeries removes the series that aren't seen longer than the series TTL.
+// The series are checked at most once per TTL.
+func (c *OTLPConverter) evictExpiredSeries() {
+	now := c.now()
+	if now.Sub(c.lastEvict) < c.ttl {
+		return
+	}
+	c.lastEvict = now
+
+	for name, state := range c.series {
+		if now.Sub(state.lastSeen) >= c.ttl {
+			delete(c.series, name)
+		}
+	}
+}
+
+func (c *OTLPConverter) evictOldestSeries() {
+	var oldestName string
+	var oldestSeen time.Time
+	for name, state := range c.series {
+		if len(oldestName) == 0 || state.lastSeen.Before(oldestSeen) {
+			oldestName = name
+			oldestSeen = state.lastSeen
+		}
+	}
+	delete(c.series, oldestName)
+}
+
+func numberDataPointValue(dp *metricspb.NumberDataPoint) float64 {
+	switch v := dp.GetValue().(type) {
+	case *metricspb.NumberDataPoint_AsInt:
+		return float64(v.AsInt)
+	case *metricspb.NumberDataPoint_AsDouble:
+		return v.AsDouble
+	default:
+		return 0
+	}
+}
+
+// otlpSeriesName encodes the data point attributes into the metric name in format: name{key1=value1,key2=value2}.
+func otlpSeriesName(name string, attributes []*commonpb.KeyValue) string {
+	labels := make(map[string]string, len(attributes))
+	for _, attr := range attributes {
+		labels[attr.GetKey()] = anyValueString(attr.GetValue())
+	}
+
+	return metrics.SeriesName(name, labels)
+}
+
+func anyValueString(v *commonpb.AnyValue) string {
+	switch value := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return value.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return fmt.Sprintf("%t", value.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return utils.FormatCounterMetricValue(value.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return utils.FormatGaugeMetricValue(value.DoubleValue)
+	default:
+		return ""
+	}
+}
+
+// Export handles OTLP metrics export requests sent over gRPC.
+func (s *Server) Export(ctx context.Context, in *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	resp, err := s.exportOTLPMetrics(ctx, in)
+	if err != nil {
+		var invalidMetricType er.InvalidMetricType
+		var invalidMetricValue er.InvalidMetricValue
+		if errors.As(err, &invalidMetricType) || errors.As(err, &invalidMetricValue) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return resp, nil
+}
+
+// OTLPMetricsHandler handles OTLP/HTTP metrics export requests encoded as protobuf or JSON.
+// The request body size is limited to 16 MiB.
+func (s *Server) OTLPMetricsHandler(res http.ResponseWriter, req *http.Request) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+
+	var unmarshal func(body []byte, m proto.Message) error
+	switch mediaType {
+	case otlpProtobufMediaType:
+		unmarshal = proto.Unmarshal
+	case otlpJSONMediaType:
+		unmarshal = protojson.Unmarshal
+	default:
+		http.Error(res, fmt.Sprintf("Unsupported content type: %s", req.Header.Get("Content-Type")),
+			http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(res, req.Body, otlpMaxBodySize))
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			http.Error(res, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(res, "Read request body error", http.StatusBadRequest)
+		return
+	}
+
+	in := &colmetricspb.ExportMetricsServiceRequest{}
+	err = unmarshal(body, in)
+	if err != nil {
+		http.Error(res, "Error decode request body", http.StatusBadRequest)
+		return
+	}
+
+	out, err := s.exportOTLPMetrics(req.Context(), in)
+	if err != nil {
+		var invalidMetricType er.InvalidMetricType
+		var invalidMetricValue er.InvalidMetricValue
+		if errors.As(err, &invalidMetricType) || errors.As(err, &invalidMetricValue) {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var resp []byte
+	if mediaType == otlpJSONMediaType {
+		resp, err = protojson.Marshal(out)
+	} else {
+		resp, err = proto.Marshal(out)
+	}
+	if err != nil {
+		http.Error(res, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Set("Content-Type", mediaType)
+	_, err = res.Write(resp)
+	if err != nil {
+		http.Error(res, "Error encoding response", http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) exportOTLPMetrics(ctx context.Context, in *colmetricspb.ExportMetricsServiceRequest) (
+	*colmetricspb.ExportMetricsServiceResponse, error) {
+	metricsBatch, rejected := s.otlpConverter.Convert(in.GetResourceMetrics())
+
+	if len(metricsBatch) > 0 {
+		err := s.MetricService.UpdateMetricsBatchWithBody(ctx, metricsBatch, s.isSyncSaveStorageState(),
+			s.Config.FileStoragePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &colmetricspb.ExportMetricsServiceResponse{}
+	if rejected > 0 {
+		resp.PartialSuccess = &colmetricspb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: rejected,
+			ErrorMessage:       "exponential histogram and summary data points are not supported",
+		}
+	}
+
+	return resp, nil
+}
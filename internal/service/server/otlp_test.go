@@ -0,0 +1,294 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/server"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/server"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func otlpRequest(ms ...*metricspb.Metric) *colmetricspb.ExportMetricsServiceRequest {
+	return &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{
+			{ScopeMetrics: []*metricspb.ScopeMetrics{{Metrics: ms}}},
+		},
+	}
+}
+
+func otlpSum(name string, temporality metricspb.AggregationTemporality, isMonotonic bool, start uint64,
+	value float64) *metricspb.Metric {
+	return &metricspb.Metric{
+		Name: name,
+		Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+			AggregationTemporality: temporality,
+			IsMonotonic:            isMonotonic,
+			DataPoints: []*metricspb.NumberDataPoint{
+				{StartTimeUnixNano: start, Value: &metricspb.NumberDataPoint_AsDouble{AsDouble: value}},
+			},
+		}},
+	}
+}
+
+func TestOTLPConverter_Convert(t *testing.T) {
+	t.Run("should convert gauge data points with attributes to gauge metrics", func(t *testing.T) {
+		c := NewOTLPConverter()
+		m := &metricspb.Metric{
+			Name: "temperature",
+			Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{
+				DataPoints: []*metricspb.NumberDataPoint{
+					{
+						Attributes: []*commonpb.KeyValue{
+							{Key: "room", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "b"}}},
+							{Key: "floor", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 2}}},
+						},
+						Value: &metricspb.NumberDataPoint_AsDouble{AsDouble: 21.5},
+					},
+				},
+			}},
+		}
+
+		got, rejected := c.Convert(otlpRequest(m).ResourceMetrics)
+
+		assert.Equal(t, int64(0), rejected)
+		assert.Equal(t, []metrics.Metrics{metrics.NewGaugeMetrics("temperature{floor=2,room=b}", 21.5)}, got)
+	})
+
+	t.Run("should convert monotonic delta sum to counter metric", func(t *testing.T) {
+		c := NewOTLPConverter()
+		m := otlpSum("requests", metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, true, 1, 5)
+
+		got, _ := c.Convert(otlpRequest(m).ResourceMetrics)
+		assert.Equal(t, []metrics.Metrics{metrics.NewCounterMetrics("requests", 5)}, got)
+
+		got, _ = c.Convert(otlpRequest(m).ResourceMetrics)
+		assert.Equal(t, []metrics.Metrics{metrics.NewCounterMetrics("requests", 5)}, got)
+	})
+
+	t.Run("should convert monotonic cumulative sum to counter metric deltas", func(t *testing.T) {
+		c := NewOTLPConverter()
+		cumulative := metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+
+		got, _ := c.Convert(otlpRequest(otlpSum("requests", cumulative, true, 1, 10)).ResourceMetrics)
+		assert.Equal(t, []metrics.Metrics{metrics.NewCounterMetrics("requests", 10)}, got, "first value should be sent as is")
+
+		got, _ = c.Convert(otlpRequest(otlpSum("requests", cumulative, true, 1, 25)).ResourceMetrics)
+		assert.Equal(t, []metrics.Metrics{metrics.NewCounterMetrics("requests", 15)}, got, "should send difference")
+
+		got, _ = c.Convert(otlpRequest(otlpSum("requests", cumulative, true, 2, 3)).ResourceMetrics)
+		assert.Equal(t, []metrics.Metrics{metrics.NewCounterMetrics("requests", 3)}, got, "should send value after reset")
+	})
+
+	t.Run("should convert non-monotonic sums to gauge metrics", func(t *testing.T) {
+		c := NewOTLPConverter()
+		delta := metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+		cumulative := metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+
+		got, _ := c.Convert(otlpRequest(otlpSum("queue", delta, false, 1, 4)).ResourceMetrics)
+		assert.Equal(t, []metrics.Metrics{metrics.NewGaugeMetrics("queue", 4)}, got)
+
+		got, _ = c.Convert(otlpRequest(otlpSum("queue", delta, false, 1, -1)).ResourceMetrics)
+		assert.Equal(t, []metrics.Metrics{metrics.NewGaugeMetrics("queue", 3)}, got, "should accumulate delta values")
+
+		got, _ = c.Convert(otlpRequest(otlpSum("connections", cumulative, false, 1, 7)).ResourceMetrics)
+		assert.Equal(t, []metrics.Metrics{metrics.NewGaugeMetrics("connections", 7)}, got)
+	})
+
+	t.Run("should convert histogram to count, sum and bucket metrics", func(t *testing.T) {
+		c := NewOTLPConverter()
+		sum := 12.5
+		m := &metricspb.Metric{
+			Name: "latency",
+			Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+				DataPoints: []*metricspb.HistogramDataPoint{
+					{Count: 3, Sum: &sum, BucketCounts: []uint64{1, 2}, ExplicitBounds: []float64{0.5}},
+				},
+			}},
+		}
+
+		got, _ := c.Convert(otlpRequest(m).ResourceMetrics)
+
+		assert.Equal(t, []metrics.Metrics{
+			metrics.NewCounterMetrics("latency_count", 3),
+			metrics.NewGaugeMetrics("latency_sum", 12.5),
+			metrics.NewCounterMetrics("latency_bucket{le=0.5}", 1),
+			metrics.NewCounterMetrics("latency_bucket{le=+Inf}", 3),
+		}, got)
+	})
+
+	t.Run("should evict series that aren't seen longer than the series TTL", func(t *testing.T) {
+		c := NewOTLPConverter()
+		now := time.Now()
+		c.now = func() time.Time { return now }
+		cumulative := metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+
+		c.Convert(otlpRequest(otlpSum("requests", cumulative, true, 1, 10)).ResourceMetrics)
+		c.Convert(otlpRequest(otlpSum("errors", cumulative, true, 1, 1)).ResourceMetrics)
+		now = now.Add(otlpSeriesTTL)
+		got, _ := c.Convert(otlpRequest(otlpSum("errors", cumulative, true, 1, 2)).ResourceMetrics)
+		assert.Equal(t, []metrics.Metrics{metrics.NewCounterMetrics("errors", 2)}, got, "should send value of evicted series as is")
+
+		assert.NotContains(t, c.series, "requests", "should evict expired series")
+		assert.Contains(t, c.series, "errors", "should keep series seen in the last request")
+	})
+
+	t.Run("should evict the least recently seen series when series limit is reached", func(t *testing.T) {
+		c := NewOTLPConverter()
+		c.maxSeries = 2
+		now := time.Now()
+		c.now = func() time.Time { return now }
+		cumulative := metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+
+		for _, name := range []string{"a", "b", "c"} {
+			now = now.Add(time.Second)
+			c.Convert(otlpRequest(otlpSum(name, cumulative, true, 1, 1)).ResourceMetrics)
+		}
+
+		assert.Len(t, c.series, 2, "shouldn't track more series than the limit")
+		assert.NotContains(t, c.series, "a", "should evict the least recently seen series")
+	})
+
+	t.Run("should reject exponential histogram and summary data points", func(t *testing.T) {
+		c := NewOTLPConverter()
+		eh := &metricspb.Metric{Name: "eh", Data: &metricspb.Metric_ExponentialHistogram{
+			ExponentialHistogram: &metricspb.ExponentialHistogram{
+				DataPoints: []*metricspb.ExponentialHistogramDataPoint{{}, {}},
+			}}}
+		summary := &metricspb.Metric{Name: "summary", Data: &metricspb.Metric_Summary{
+			Summary: &metricspb.Summary{DataPoints: []*metricspb.SummaryDataPoint{{}}}}}
+
+		got, rejected := c.Convert(otlpRequest(eh, summary).ResourceMetrics)
+
+		assert.Empty(t, got)
+		assert.Equal(t, int64(3), rejected)
+	})
+}
+
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockStorage(ctrl)
+	config := &server.ServerConfig{}
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	metricService := NewMetricService(mockStorage, logger)
+	s := NewServer(metricService, config, nil, nil, logger)
+
+	req := otlpRequest(otlpSum("requests", metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, true, 1, 2))
+	mockStorage.
+		EXPECT().
+		UpdateMetrics(gomock.Any(), []metrics.Metrics{metrics.NewCounterMetrics("requests", 2)}).
+		Return(nil)
+
+	resp, err := s.Export(context.Background(), req)
+	require.NoError(t, err, "shouldn't return error, but got: %s", err)
+	assert.Nil(t, resp.PartialSuccess, "should accept all data points")
+}
+
+func TestOTLPMetricsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockStorage(ctrl)
+	config := &server.ServerConfig{}
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	metricService := NewMetricService(mockStorage, logger)
+	s := NewServer(metricService, config, nil, nil, logger)
+
+	t.Run("should update metrics from protobuf encoded request", func(t *testing.T) {
+		body, err := proto.Marshal(otlpRequest(
+			otlpSum("requests", metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, true, 1, 2)))
+		require.NoError(t, err)
+
+		mockStorage.
+			EXPECT().
+			UpdateMetrics(gomock.Any(), []metrics.Metrics{metrics.NewCounterMetrics("requests", 2)}).
+			Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader(body))
+		req.Header.Set("Content-Type", otlpProtobufMediaType)
+		w := httptest.NewRecorder()
+		s.OTLPMetricsHandler(w, req)
+
+		res := w.Result()
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, otlpProtobufMediaType, res.Header.Get("Content-Type"))
+
+		respBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.NoError(t, proto.Unmarshal(respBody, &colmetricspb.ExportMetricsServiceResponse{}))
+	})
+
+	t.Run("should update metrics from JSON encoded request with charset parameter", func(t *testing.T) {
+		body, err := protojson.Marshal(otlpRequest(
+			otlpSum("requests", metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, true, 1, 3)))
+		require.NoError(t, err)
+
+		mockStorage.
+			EXPECT().
+			UpdateMetrics(gomock.Any(), []metrics.Metrics{metrics.NewCounterMetrics("requests", 3)}).
+			Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		w := httptest.NewRecorder()
+		s.OTLPMetricsHandler(w, req)
+
+		res := w.Result()
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, otlpJSONMediaType, res.Header.Get("Content-Type"))
+	})
+
+	t.Run("should return status code 413 when body is too large", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader(make([]byte, otlpMaxBodySize+1)))
+		req.Header.Set("Content-Type", otlpProtobufMediaType)
+		w := httptest.NewRecorder()
+		s.OTLPMetricsHandler(w, req)
+
+		res := w.Result()
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+	})
+
+	t.Run("should return status code 415 when content type is unsupported", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader([]byte("data")))
+		req.Header.Set("Content-Type", "text/plain")
+		w := httptest.NewRecorder()
+		s.OTLPMetricsHandler(w, req)
+
+		res := w.Result()
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.StatusCode)
+	})
+
+	t.Run("should return status code 400 when body is invalid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader([]byte("{")))
+		req.Header.Set("Content-Type", otlpJSONMediaType)
+		w := httptest.NewRecorder()
+		s.OTLPMetricsHandler(w, req)
+
+		res := w.Result()
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
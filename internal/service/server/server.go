@@ -4,6 +4,8 @@ import (
 	"crypto/rsa"
 	"net"
 
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+
 	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/server"
 	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/server"
 	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
@@ -15,8 +17,10 @@ type Server struct {
 	Config        *config.ServerConfig // Config holds the server configuration
 	rsaPrivateKey *rsa.PrivateKey      // rsaPrivateKey is secret private key for asymmetric encryption
 	trustedSubnet *net.IPNet           // trustedSubnet is trusted subnet for agents
+	otlpConverter *OTLPConverter       // otlpConverter converts OTLP metrics to the service metrics model
 	Logger        *logger.ServerLogger // Logger is used for logging server events
 	pb.UnimplementedMetricsV1ServiceServer
+	colmetricspb.UnimplementedMetricsServiceServer
 }
 
 // NewServer is constructor for creating a new Server instance.
@@ -27,6 +31,7 @@ func NewServer(metricService *MetricService, config *config.ServerConfig, rsaPri
 		Config:        config,
 		rsaPrivateKey: rsaPrivateKey,
 		trustedSubnet: trustedSubnet,
+		otlpConverter: NewOTLPConverter(),
 		Logger:        logger,
 	}
 }
@@ -21,7 +21,8 @@ import (
 )
 
 const (
-	signKey = "HashSHA256"
+	signKey   = "HashSHA256"
+	signField = "sign"
 )
 
 // signWriter wraps http.ResponseWriter and adds an HMAC SHA256 signature to the response body.
@@ -52,6 +53,11 @@ func (s *signWriter) WriteHeader(statusCode int) {
 	s.w.WriteHeader(statusCode)
 }
 
+// Unwrap returns the original http.ResponseWriter, so http.ResponseController can flush streamed responses.
+func (s *signWriter) Unwrap() http.ResponseWriter {
+	return s.w
+}
+
 // SignMiddleware is a middleware that checks the request body signature and
 // signs the response body with HMAC SHA256 if a secret key is configured.
 func (s *Server) SignMiddleware(next http.Handler) http.Handler {
@@ -88,46 +94,143 @@ func (s *Server) SignMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// RequireSignMiddleware is a middleware that rejects requests without the body signature
+// if a secret key is configured. The signature itself is checked by SignMiddleware.
+func (s *Server) RequireSignMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hasSign := len(strings.TrimSpace(r.Header.Get(signKey))) > 0
+		needCheckSign := len(s.Config.SecretKey) > 0
+
+		if needCheckSign && !hasSign {
+			http.Error(w, "Missing request body sign", http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // SignInterceptor is a gRPC interceptor that verifies the signature of incoming requests.
+// OTLP export requests are verified as other requests, unless unauthenticated OTLP requests are allowed
+// in the server config, because OpenTelemetry SDKs can not sign them.
 func (s *Server) SignInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler) (interface{}, error) {
-	needCheckSign := len(s.Config.SecretKey) > 0
+	needCheckSign := len(s.Config.SecretKey) > 0 && !(s.Config.OTLPAllowUnauthenticated && isOTLPMethod(info))
 
 	if needCheckSign {
-		var sign string
-
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Errorf(codes.InvalidArgument, "sign interceptor: missing request metadata")
+		if err := checkRequestSign(ctx, req, s.Config.SecretKey); err != nil {
+			return nil, err
 		}
+	}
 
-		values := md.Get(signKey)
-		if len(values) > 0 {
-			sign = values[0]
-		}
-		if len(sign) == 0 {
-			return nil, status.Errorf(codes.InvalidArgument, "sign interceptor: missing request sign")
-		}
+	return handler(ctx, req)
+}
 
-		message, ok := req.(proto.Message)
-		if !ok {
-			return nil, status.Errorf(codes.Internal, "sign interceptor: request isn't a proto.Message")
-		}
+// signServerStream wraps grpc.ServerStream and verifies the signature of each received message.
+type signServerStream struct {
+	grpc.ServerStream
+	secretKey      string
+	isClientStream bool
+}
+
+// RecvMsg receives a message from the stream and verifies its signature.
+func (s *signServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	message, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "sign interceptor: request isn't a proto.Message")
+	}
 
-		body, err := proto.Marshal(message)
+	if !s.isClientStream {
+		return checkRequestSign(s.Context(), message, s.secretKey)
+	}
+	return checkMessageSign(message, s.secretKey)
+}
+
+// SignStreamInterceptor is a gRPC stream interceptor that verifies the signature of each message
+// received over the stream. Client stream messages carry their signature in the sign field, because
+// the request metadata is sent only once per stream. The single request of a server stream
+// is signed in the request metadata as unary requests are.
+func (s *Server) SignStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	needCheckSign := len(s.Config.SecretKey) > 0
+	if needCheckSign {
+		isClientStream := info == nil || info.IsClientStream
+		ss = &signServerStream{ServerStream: ss, secretKey: s.Config.SecretKey, isClientStream: isClientStream}
+	}
+
+	return handler(srv, ss)
+}
+
+func checkRequestSign(ctx context.Context, req interface{}, secretKey string) error {
+	var sign string
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "sign interceptor: missing request metadata")
+	}
+
+	values := md.Get(signKey)
+	if len(values) > 0 {
+		sign = values[0]
+	}
+	if len(sign) == 0 {
+		return status.Errorf(codes.InvalidArgument, "sign interceptor: missing request sign")
+	}
+
+	message, ok := req.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "sign interceptor: request isn't a proto.Message")
+	}
+
+	body, err := proto.Marshal(message)
+	if err != nil {
+		return status.Errorf(codes.Internal, "sign interceptor: %s", err)
+	}
+
+	if len(body) > 0 {
+		err := checkSign(body, sign, secretKey)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "sign interceptor: %s", err)
+			return status.Errorf(codes.Unauthenticated, "sign interceptor: invalid sign")
 		}
+	}
 
-		if len(body) > 0 {
-			err := checkSign(body, sign, s.Config.SecretKey)
-			if err != nil {
-				return nil, status.Errorf(codes.Unauthenticated, "sign interceptor: invalid sign")
-			}
+	return nil
+}
+
+func checkMessageSign(message proto.Message, secretKey string) error {
+	m := message.ProtoReflect()
+	field := m.Descriptor().Fields().ByName(signField)
+	if field == nil {
+		return status.Errorf(codes.Internal, "sign interceptor: message doesn't support sign")
+	}
+
+	sign := m.Get(field).String()
+	if len(sign) == 0 {
+		return status.Errorf(codes.InvalidArgument, "sign interceptor: missing message sign")
+	}
+
+	m.Clear(field)
+	body, err := proto.Marshal(message)
+	if err != nil {
+		return status.Errorf(codes.Internal, "sign interceptor: %s", err)
+	}
+
+	if len(body) > 0 {
+		err := checkSign(body, sign, secretKey)
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "sign interceptor: invalid sign")
 		}
 	}
 
-	return handler(ctx, req)
+	return nil
+}
+
+func isOTLPMethod(info *grpc.UnaryServerInfo) bool {
+	return info != nil && info.FullMethod == otlpExportMethod
 }
 
 func checkSign(value []byte, sign string, secretKey string) error {
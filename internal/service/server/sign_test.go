@@ -3,6 +3,9 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -10,9 +13,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/server"
 	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
@@ -127,6 +134,46 @@ func TestSignMiddleware(t *testing.T) {
 	}
 }
 
+func TestRequireSignMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverSignKey string
+		sign          string
+		status        int
+	}{
+		{
+			name:          "Unsigned request when server secret key is set",
+			serverSignKey: "secret-key",
+			status:        http.StatusBadRequest,
+		},
+		{
+			name:          "Signed request when server secret key is set",
+			serverSignKey: "secret-key",
+			sign:          getTestSign([]byte("body"), "secret-key"),
+			status:        http.StatusOK,
+		},
+		{
+			name:   "Unsigned request when server secret key isn't set",
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader([]byte("body")))
+			req.Header.Set(signKey, tt.sign)
+			server := &Server{Config: &config.ServerConfig{SecretKey: tt.serverSignKey}}
+
+			handler := server.SignMiddleware(server.RequireSignMiddleware(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })))
+			r := httptest.NewRecorder()
+			handler.ServeHTTP(r, req)
+
+			assert.Equal(t, tt.status, r.Code, "response status code should be %d, got %d", tt.status, r.Code)
+		})
+	}
+}
+
 func TestSignInterceptor(t *testing.T) {
 	secretKey := "secret-key"
 
@@ -221,3 +268,175 @@ func TestSignInterceptor(t *testing.T) {
 		})
 	}
 }
+
+func TestSignInterceptor_OTLP(t *testing.T) {
+	req := &colmetricspb.ExportMetricsServiceRequest{ResourceMetrics: []*metricspb.ResourceMetrics{{}}}
+	info := &grpc.UnaryServerInfo{FullMethod: otlpExportMethod}
+
+	tests := []struct {
+		name                     string
+		otlpAllowUnauthenticated bool
+		expectedErr              error
+	}{
+		{
+			name:                     "Unsigned OTLP request when unauthenticated OTLP requests aren't allowed",
+			otlpAllowUnauthenticated: false,
+			expectedErr:              status.Errorf(codes.InvalidArgument, "sign interceptor: missing request sign"),
+		},
+		{
+			name:                     "Unsigned OTLP request when unauthenticated OTLP requests are allowed",
+			otlpAllowUnauthenticated: true,
+			expectedErr:              nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{Config: &config.ServerConfig{SecretKey: "secret-key",
+				OTLPAllowUnauthenticated: tt.otlpAllowUnauthenticated}}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+
+			_, err := server.SignInterceptor(ctx, req, info,
+				func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil })
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err, "should return error: %s, got: %s", tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err, "should not return error, but got: %s", err)
+			}
+		})
+	}
+}
+
+func TestSignStreamInterceptor(t *testing.T) {
+	secretKey := "secret-key"
+	newRequest := func(sign string) *pb.MetricsV1ServiceStreamMetricsRequest {
+		return &pb.MetricsV1ServiceStreamMetricsRequest{
+			Sequence: 1,
+			Metrics: []*pb.MetricData{{
+				Name:        "Alloc",
+				Type:        "gauge",
+				MetricValue: &pb.MetricData_Value{Value: 22.22},
+			}},
+			Sign: sign,
+		}
+	}
+	unsigned, err := proto.Marshal(newRequest(""))
+	require.NoError(t, err)
+	validSign := getTestSign(unsigned, secretKey)
+
+	tests := []struct {
+		name        string
+		secretKey   string
+		req         *pb.MetricsV1ServiceStreamMetricsRequest
+		expectedErr error
+	}{
+		{
+			name:        "Valid sign in stream message",
+			secretKey:   secretKey,
+			req:         newRequest(validSign),
+			expectedErr: nil,
+		},
+		{
+			name:        "No sign in stream message",
+			secretKey:   secretKey,
+			req:         newRequest(""),
+			expectedErr: status.Errorf(codes.InvalidArgument, "sign interceptor: missing message sign"),
+		},
+		{
+			name:        "Invalid sign in stream message",
+			secretKey:   secretKey,
+			req:         newRequest("invalid"),
+			expectedErr: status.Errorf(codes.Unauthenticated, "sign interceptor: invalid sign"),
+		},
+		{
+			name:        "No secret key in server config",
+			secretKey:   "",
+			req:         newRequest(""),
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{Config: &config.ServerConfig{SecretKey: tt.secretKey}}
+			stream := &testStreamMetricsServer{
+				ctx:  context.Background(),
+				reqs: []*pb.MetricsV1ServiceStreamMetricsRequest{tt.req},
+			}
+
+			err := server.SignStreamInterceptor(nil, stream, nil, func(srv interface{}, ss grpc.ServerStream) error {
+				return ss.RecvMsg(&pb.MetricsV1ServiceStreamMetricsRequest{})
+			})
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err, "should return error: %s, got: %s", tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err, "should not return error, but got: %s", err)
+			}
+		})
+	}
+}
+
+func getTestSign(value []byte, secretKey string) string {
+	h := hmac.New(sha256.New, []byte(secretKey))
+	h.Write(value)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestSignStreamInterceptor_ServerStream(t *testing.T) {
+	secretKey := "secret-key"
+	req := &pb.MetricsV1ServiceWatchMetricsRequest{Prefix: "CPU"}
+	body, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		md          metadata.MD
+		expectedErr error
+	}{
+		{
+			name:        "Valid sign in request metadata",
+			md:          metadata.Pairs(signKey, getTestSign(body, secretKey)),
+			expectedErr: nil,
+		},
+		{
+			name:        "Invalid sign in request metadata",
+			md:          metadata.Pairs(signKey, "invalid"),
+			expectedErr: status.Errorf(codes.Unauthenticated, "sign interceptor: invalid sign"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{Config: &config.ServerConfig{SecretKey: secretKey}}
+			stream := &testWatchRequestStream{ctx: metadata.NewIncomingContext(context.Background(), tt.md), req: req}
+
+			err := server.SignStreamInterceptor(nil, stream, &grpc.StreamServerInfo{IsServerStream: true},
+				func(srv interface{}, ss grpc.ServerStream) error {
+					return ss.RecvMsg(&pb.MetricsV1ServiceWatchMetricsRequest{})
+				})
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err, "should return error: %s, got: %s", tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err, "should not return error, but got: %s", err)
+			}
+		})
+	}
+}
+
+type testWatchRequestStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req proto.Message
+}
+
+func (s *testWatchRequestStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testWatchRequestStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
@@ -61,13 +61,32 @@ func (s *Server) SubnetMiddleware(next http.Handler) http.Handler {
 // request is within a trusted subnet.
 func (s *Server) SubnetInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.checkTrustedSubnet(ctx); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// SubnetStreamInterceptor is a gRPC stream interceptor that verifies if the IP address of the incoming
+// stream is within a trusted subnet.
+func (s *Server) SubnetStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	if err := s.checkTrustedSubnet(ss.Context()); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+func (s *Server) checkTrustedSubnet(ctx context.Context) error {
 	needCheckTrustedSubnet := s.trustedSubnet != nil
 	if needCheckTrustedSubnet {
 		var ipStr string
 
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
-			return nil, status.Errorf(codes.InvalidArgument, "subnet interceptor: missing request metadata")
+			return status.Errorf(codes.InvalidArgument, "subnet interceptor: missing request metadata")
 		}
 
 		values := md.Get(ipKey)
@@ -75,18 +94,18 @@ func (s *Server) SubnetInterceptor(ctx context.Context, req interface{}, info *g
 			ipStr = values[0]
 		}
 		if len(ipStr) == 0 {
-			return nil, status.Errorf(codes.InvalidArgument, "subnet interceptor: missing ip address")
+			return status.Errorf(codes.InvalidArgument, "subnet interceptor: missing ip address")
 		}
 
 		ip := net.ParseIP(ipStr)
 		if ip == nil {
-			return nil, status.Errorf(codes.InvalidArgument, "subnet interceptor: invalid ip address")
+			return status.Errorf(codes.InvalidArgument, "subnet interceptor: invalid ip address")
 		}
 
 		if !s.trustedSubnet.Contains(ip) {
-			return nil, status.Errorf(codes.Unauthenticated, "ip address isn`t in trusted subnet")
+			return status.Errorf(codes.Unauthenticated, "ip address isn`t in trusted subnet")
 		}
 	}
 
-	return handler(ctx, req)
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -189,3 +190,56 @@ func TestSubnetInterceptor(t *testing.T) {
 		})
 	}
 }
+
+func TestSubnetStreamInterceptor(t *testing.T) {
+	trustedSubnet := &net.IPNet{
+		IP:   net.ParseIP("192.168.1.0"),
+		Mask: net.CIDRMask(24, 32),
+	}
+
+	tests := []struct {
+		name          string
+		trustedSubnet *net.IPNet
+		md            metadata.MD
+		expectedErr   error
+	}{
+		{
+			name:          "IP in trusted subnet",
+			trustedSubnet: trustedSubnet,
+			md:            metadata.Pairs(ipKey, "192.168.1.10"),
+			expectedErr:   nil,
+		},
+		{
+			name:          "No metadata",
+			trustedSubnet: trustedSubnet,
+			md:            nil,
+			expectedErr:   status.Errorf(codes.InvalidArgument, "subnet interceptor: missing request metadata"),
+		},
+		{
+			name:          "IP outside trusted subnet",
+			trustedSubnet: trustedSubnet,
+			md:            metadata.Pairs(ipKey, "192.168.2.10"),
+			expectedErr:   status.Errorf(codes.Unauthenticated, "ip address isn`t in trusted subnet"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{trustedSubnet: tt.trustedSubnet}
+
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			stream := &testStreamMetricsServer{ctx: ctx}
+			err := server.SubnetStreamInterceptor(nil, stream, nil,
+				func(srv interface{}, ss grpc.ServerStream) error { return nil })
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err, "should return error: %s, got: %s", tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err, "should not return error, but got: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
+)
+
+const (
+	sseMediaType         = "text/event-stream"
+	sseMetricsEvent      = "metrics"
+	sseKeepAliveInterval = 15 * time.Second
+)
+
+// WatchMetrics streams updates of the metrics selected by names or name prefix.
+// The current values of the selected metrics are sent first, then each response holds
+// the metrics updated since the previous one. Updates that the client can't receive in time
+// are coalesced to the latest value of each metric.
+func (s *Server) WatchMetrics(in *pb.MetricsV1ServiceWatchMetricsRequest, stream pb.MetricsV1Service_WatchMetricsServer) error {
+	if err := in.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := stream.Context()
+	filter := MetricsFilter{Names: in.GetNames(), Prefix: in.GetPrefix()}
+	sub := s.MetricService.Subscribe(filter)
+	defer s.MetricService.Unsubscribe(sub)
+
+	snapshot, err := s.MetricService.GetFilteredMetrics(ctx, filter)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	send := func(ms []metrics.Metrics) error {
+		if len(ms) == 0 {
+			return nil
+		}
+		return stream.Send(&pb.MetricsV1ServiceWatchMetricsResponse{Metrics: metrics.MetricsToRepeatedMetricData(ms)})
+	}
+
+	if err := send(snapshot); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Updates():
+			if err := send(sub.Next()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// WatchMetricsHandler streams updates of the metrics selected by the name query parameters
+// or the prefix query parameter as Server-Sent Events. Each event holds a JSON array of metrics.
+func (s *Server) WatchMetricsHandler(res http.ResponseWriter, req *http.Request) {
+	rc := http.NewResponseController(res)
+	ctx := req.Context()
+	query := req.URL.Query()
+	filter := MetricsFilter{Names: query["name"], Prefix: query.Get("prefix")}
+
+	sub := s.MetricService.Subscribe(filter)
+	defer s.MetricService.Unsubscribe(sub)
+
+	snapshot, err := s.MetricService.GetFilteredMetrics(ctx, filter)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Set("Content-Type", sseMediaType)
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+
+	send := func(ms []metrics.Metrics) error {
+		if len(ms) > 0 {
+			data, err := json.Marshal(ms)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", sseMetricsEvent, data); err != nil {
+				return err
+			}
+		}
+		return rc.Flush()
+	}
+
+	if err := send(snapshot); err != nil {
+		s.Logger.Error(err.Error(), zap.String("event", "send metrics event"))
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case <-sub.Updates():
+			if err := send(sub.Next()); err != nil {
+				s.Logger.Error(err.Error(), zap.String("event", "send metrics event"))
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/server"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/server"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+	pb "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
+)
+
+type testWatchMetricsServer struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *pb.MetricsV1ServiceWatchMetricsResponse
+}
+
+func (s *testWatchMetricsServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testWatchMetricsServer) Send(resp *pb.MetricsV1ServiceWatchMetricsResponse) error {
+	s.sent <- resp
+	return nil
+}
+
+func TestWatchMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockStorage(ctrl)
+	config := &server.ServerConfig{}
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	metricService := NewMetricService(mockStorage, logger)
+	s := NewServer(metricService, config, nil, nil, logger)
+
+	t.Run("should send current values and then updates of watched metrics", func(t *testing.T) {
+		mockStorage.
+			EXPECT().
+			GetMetrics(gomock.Any()).
+			Return(map[string]metrics.GaugeMetric{"Alloc": metrics.NewGauge("Alloc", 1), "Frees": metrics.NewGauge("Frees", 2)},
+				map[string]metrics.CounterMetric{"PollCount": metrics.NewCounter("PollCount", 5)}, nil)
+		mockStorage.
+			EXPECT().
+			UpdateMetrics(gomock.Any(), gomock.Any()).
+			Return(nil)
+		mockStorage.
+			EXPECT().
+			GetMetric(gomock.Any(), metrics.Metrics{ID: "PollCount", MType: "counter"}).
+			Return(metrics.NewCounterMetrics("PollCount", 7), nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream := &testWatchMetricsServer{ctx: ctx, sent: make(chan *pb.MetricsV1ServiceWatchMetricsResponse, 10)}
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- s.WatchMetrics(&pb.MetricsV1ServiceWatchMetricsRequest{Names: []string{"Alloc", "PollCount"}}, stream)
+		}()
+
+		snapshot := <-stream.sent
+		assert.ElementsMatch(t, []metrics.Metrics{metrics.NewGaugeMetrics("Alloc", 1), metrics.NewCounterMetrics("PollCount", 5)},
+			metrics.RepeatedMetricDataToMetrics(snapshot.Metrics), "should send current values of watched metrics")
+
+		err := metricService.UpdateMetricsBatchWithBody(context.Background(),
+			[]metrics.Metrics{metrics.NewCounterMetrics("PollCount", 2), metrics.NewGaugeMetrics("Frees", 3)}, false, "")
+		require.NoError(t, err)
+
+		select {
+		case update := <-stream.sent:
+			assert.Equal(t, []metrics.Metrics{metrics.NewCounterMetrics("PollCount", 7)},
+				metrics.RepeatedMetricDataToMetrics(update.Metrics), "should send current value of updated metric")
+		case <-time.After(time.Second):
+			t.Fatal("should send metrics update")
+		}
+
+		cancel()
+		assert.NoError(t, <-errCh, "should stop watching when stream context is done")
+	})
+
+	t.Run("should return error when storage is unavailable", func(t *testing.T) {
+		mockStorage.
+			EXPECT().
+			GetMetrics(gomock.Any()).
+			Return(nil, nil, assert.AnError)
+
+		stream := &testWatchMetricsServer{ctx: context.Background(), sent: make(chan *pb.MetricsV1ServiceWatchMetricsResponse, 1)}
+		err := s.WatchMetrics(&pb.MetricsV1ServiceWatchMetricsRequest{}, stream)
+		assert.Equal(t, codes.Internal, status.Code(err), "should return Internal error, but got: %s", err)
+	})
+}
+
+func TestWatchMetricsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockStorage(ctrl)
+	config := &server.ServerConfig{}
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	metricService := NewMetricService(mockStorage, logger)
+	s := NewServer(metricService, config, nil, nil, logger)
+
+	mockStorage.
+		EXPECT().
+		GetMetrics(gomock.Any()).
+		Return(map[string]metrics.GaugeMetric{"CPUutilization1": metrics.NewGauge("CPUutilization1", 10)},
+			map[string]metrics.CounterMetric{}, nil)
+	mockStorage.
+		EXPECT().
+		UpdateMetric(gomock.Any(), gomock.Any()).
+		Return(nil)
+	mockStorage.
+		EXPECT().
+		GetMetric(gomock.Any(), gomock.Any()).
+		Return(metrics.NewGaugeMetrics("CPUutilization1", 20), nil).
+		Times(2)
+
+	ts := httptest.NewServer(http.HandlerFunc(s.WatchMetricsHandler))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/watch?prefix=CPU", nil)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, sseMediaType, res.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(res.Body)
+	readEvent := func() string {
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimRight(line, "\n")
+			if len(line) == 0 {
+				return strings.Join(lines, "\n")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	assert.Equal(t, `event: metrics
+data: [{"id":"CPUutilization1","type":"gauge","value":10}]`, readEvent(), "should send current values")
+
+	_, err = metricService.UpdateMetricWithBody(context.Background(), metrics.NewGaugeMetrics("CPUutilization1", 20), false, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, `event: metrics
+data: [{"id":"CPUutilization1","type":"gauge","value":20}]`, readEvent(), "should send metric update")
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"regexp"
+	"strings"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+// aggregateLabel is the label of the aggregated gauge metric series holding the aggregate function.
+const aggregateLabel = "agg"
+
+// allAggregateFunctions are the aggregate functions applied when the aggregation doesn't list them.
+var allAggregateFunctions = []string{config.AggregateMin, config.AggregateMax, config.AggregateAvg, config.AggregateCount}
+
+// gaugeAggregation aggregates the samples of the gauge metrics with names matching the pattern.
+type gaugeAggregation struct {
+	pattern   *regexp.Regexp
+	functions []string
+}
+
+// gaugeWindow holds the aggregates of the gauge metric samples collected since the previous report.
+type gaugeWindow struct {
+	aggregation *gaugeAggregation
+	min         float64
+	max         float64
+	sum         float64
+	count       int64
+}
+
+// SetAggregations sets the aggregations of the gauge metric samples collected between reports.
+// The first aggregation with the pattern matching the gauge metric name is applied to its samples.
+// The samples aggregated with the previous aggregations are discarded.
+func (c *AgentMemCache) SetAggregations(aggregations []config.AggregationConfig) {
+	c.gaugesMu.Lock()
+	defer c.gaugesMu.Unlock()
+
+	c.aggregations = make([]*gaugeAggregation, 0, len(aggregations))
+	for _, a := range aggregations {
+		functions := a.Functions
+		if len(functions) == 0 {
+			functions = allAggregateFunctions
+		}
+		c.aggregations = append(c.aggregations, &gaugeAggregation{pattern: globRegexp(a.Pattern), functions: functions})
+	}
+	c.gaugeAggregations = make(map[string]*gaugeAggregation)
+	c.gaugeWindows = make(map[string]*gaugeWindow)
+}
+
+// TakeAggregatedMetrics returns the aggregates of the gauge metric samples collected since the previous call
+// as the gauge metrics named with the agg label, and starts new aggregation windows.
+func (c *AgentMemCache) TakeAggregatedMetrics() map[string]metrics.GaugeMetric {
+	c.gaugesMu.Lock()
+	defer c.gaugesMu.Unlock()
+
+	aggregated := make(map[string]metrics.GaugeMetric)
+	for name, w := range c.gaugeWindows {
+		for _, function := range w.aggregation.functions {
+			seriesName := aggregatedName(name, function)
+			aggregated[seriesName] = metrics.NewGauge(seriesName, w.value(function))
+		}
+	}
+	if len(c.gaugeWindows) > 0 {
+		c.gaugeWindows = make(map[string]*gaugeWindow)
+	}
+
+	return aggregated
+}
+
+// aggregateGaugeSample adds the gauge metric sample to the aggregation window of the metric,
+// if the metric is aggregated. The gauges mutex should be locked.
+func (c *AgentMemCache) aggregateGaugeSample(name string, value float64) {
+	if len(c.aggregations) == 0 {
+		return
+	}
+
+	aggregation, resolved := c.gaugeAggregations[name]
+	if !resolved {
+		for _, a := range c.aggregations {
+			if a.pattern.MatchString(name) {
+				aggregation = a
+				break
+			}
+		}
+		c.gaugeAggregations[name] = aggregation
+	}
+	if aggregation == nil {
+		return
+	}
+
+	w, exists := c.gaugeWindows[name]
+	if !exists {
+		c.gaugeWindows[name] = &gaugeWindow{aggregation: aggregation, min: value, max: value, sum: value, count: 1}
+		return
+	}
+	w.min = min(w.min, value)
+	w.max = max(w.max, value)
+	w.sum += value
+	w.count++
+}
+
+// value returns the aggregate of the window samples by the aggregate function.
+func (w *gaugeWindow) value(function string) float64 {
+	switch function {
+	case config.AggregateMin:
+		return w.min
+	case config.AggregateMax:
+		return w.max
+	case config.AggregateAvg:
+		return w.sum / float64(w.count)
+	default:
+		return float64(w.count)
+	}
+}
+
+// aggregatedName adds the agg label with the aggregate function to the metric name
+// in format: name{key1=value1,key2=value2}.
+func aggregatedName(name, function string) string {
+	name, labels := metrics.ParseSeriesName(name)
+	labels[aggregateLabel] = function
+	return metrics.SeriesName(name, labels)
+}
+
+// globRegexp converts the glob pattern to the regular expression matching the whole name.
+// The * matches any characters and the ? matches a single character.
+func globRegexp(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.MustCompile("^" + expr + "$")
+}
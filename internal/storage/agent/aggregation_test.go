@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func TestTakeAggregatedMetrics(t *testing.T) {
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	storage := NewAgentMemCache(make(map[string]metrics.GaugeMetric), make(map[string]metrics.CounterMetric), logger)
+	storage.Declare(
+		metrics.NewGaugeDescriptor("Alloc"),
+		metrics.NewGaugeDescriptor("HeapInuse"),
+		metrics.NewPrefixDescriptor("DiskUsed", metrics.Gauge),
+	)
+
+	assert.Empty(t, storage.TakeAggregatedMetrics(), "should not aggregate samples without aggregations")
+
+	storage.SetAggregations([]config.AggregationConfig{
+		{Pattern: "Alloc"},
+		{Pattern: "DiskUsed*", Functions: []string{config.AggregateMax}},
+	})
+
+	for _, value := range []float64{4, 1, 7} {
+		_, err = storage.UpdateGaugeMetric(metrics.NewGauge("Alloc", value))
+		require.NoError(t, err)
+	}
+	_, err = storage.UpdateGaugeMetric(metrics.NewGauge("DiskUsed{mountpoint=/}", 10))
+	require.NoError(t, err)
+	_, err = storage.UpdateGaugeMetric(metrics.NewGauge("DiskUsed{mountpoint=/}", 5))
+	require.NoError(t, err)
+	_, err = storage.UpdateGaugeMetric(metrics.NewGauge("HeapInuse", 3))
+	require.NoError(t, err)
+
+	expected := map[string]metrics.GaugeMetric{
+		"Alloc{agg=min}":                 metrics.NewGauge("Alloc{agg=min}", 1),
+		"Alloc{agg=max}":                 metrics.NewGauge("Alloc{agg=max}", 7),
+		"Alloc{agg=avg}":                 metrics.NewGauge("Alloc{agg=avg}", 4),
+		"Alloc{agg=count}":               metrics.NewGauge("Alloc{agg=count}", 3),
+		"DiskUsed{agg=max,mountpoint=/}": metrics.NewGauge("DiskUsed{agg=max,mountpoint=/}", 10),
+	}
+	assert.Equal(t, expected, storage.TakeAggregatedMetrics(), "should aggregate samples of matching gauge metrics")
+
+	gauges, _ := storage.GetMetrics()
+	assert.Equal(t, 7.0, gauges["Alloc"].Value, "should keep last value of aggregated gauge metric")
+
+	assert.Empty(t, storage.TakeAggregatedMetrics(), "should start new aggregation windows after taking aggregates")
+}
+
+func TestAggregatedName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		metric   string
+		function string
+		expected string
+	}{
+		{name: "should add agg label to metric without labels", metric: "Alloc", function: "min", expected: "Alloc{agg=min}"},
+		{name: "should add agg label to sorted labels", metric: "DiskUsed{mountpoint=/}", function: "avg",
+			expected: "DiskUsed{agg=avg,mountpoint=/}"},
+		{name: "should keep labels sorted with agg label", metric: "CPU{cpu=0}", function: "max",
+			expected: "CPU{agg=max,cpu=0}"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, aggregatedName(tt.metric, tt.function))
+		})
+	}
+}
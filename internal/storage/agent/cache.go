@@ -2,14 +2,16 @@ package storage
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 
 	"go.uber.org/zap"
 
+	config "github.com/Stern-Ritter/metrics-and-alerting-service/internal/config/agent"
 	er "github.com/Stern-Ritter/metrics-and-alerting-service/internal/errors"
 	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
-	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/monitors"
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/utils"
 )
 
@@ -17,10 +19,14 @@ import (
 type AgentCache interface {
 	UpdateGaugeMetric(metric metrics.GaugeMetric) (metrics.GaugeMetric, error)
 	UpdateCounterMetric(metric metrics.CounterMetric) (metrics.CounterMetric, error)
-	UpdateRuntimeMonitorMetrics(m *monitors.RuntimeMonitor)
-	UpdateUtilMonitorMetrics(m *monitors.UtilMonitor)
+	UpdateMetrics(ms []metrics.Metrics)
+	RemoveMetrics(ms []metrics.Metrics)
+	SetAggregations(aggregations []config.AggregationConfig)
+	TakeAggregatedMetrics() map[string]metrics.GaugeMetric
+	Declare(descriptors ...metrics.Descriptor)
 	ResetMetricValue(metricType, metricName string) error
 	GetMetrics() (map[string]metrics.GaugeMetric, map[string]metrics.CounterMetric)
+	TakeMetrics() (map[string]metrics.GaugeMetric, map[string]metrics.CounterMetric)
 }
 
 // AgentMemCache is an in-memory implementation of the AgentCache interface.
@@ -31,6 +37,14 @@ type AgentMemCache struct {
 	gauges   map[string]metrics.GaugeMetric
 	counters map[string]metrics.CounterMetric
 
+	gaugePrefixes   []string
+	counterPrefixes []string
+	removedCounters map[string]struct{}
+
+	aggregations      []*gaugeAggregation
+	gaugeAggregations map[string]*gaugeAggregation
+	gaugeWindows      map[string]*gaugeWindow
+
 	Logger *logger.AgentLogger
 }
 
@@ -45,6 +59,7 @@ func NewAgentMemCache(supportedGaugeMetrics map[string]metrics.GaugeMetric, supp
 }
 
 // UpdateGaugeMetric updates or adds a gauge metric in the cache and returns the updated metric.
+// The value is also added to the aggregation window of the metric, if it's aggregated.
 func (c *AgentMemCache) UpdateGaugeMetric(metric metrics.GaugeMetric) (metrics.GaugeMetric, error) {
 	c.gaugesMu.Lock()
 	defer c.gaugesMu.Unlock()
@@ -60,6 +75,7 @@ func (c *AgentMemCache) UpdateGaugeMetric(metric metrics.GaugeMetric) (metrics.G
 	} else {
 		c.gauges[metric.Name] = metric
 	}
+	c.aggregateGaugeSample(metric.Name, metric.GetValue())
 
 	return c.gauges[metric.Name], nil
 }
@@ -80,6 +96,7 @@ func (c *AgentMemCache) UpdateCounterMetric(metric metrics.CounterMetric) (metri
 	} else {
 		c.counters[metric.Name] = metric
 	}
+	delete(c.removedCounters, metric.Name)
 
 	return c.counters[metric.Name], nil
 }
@@ -130,42 +147,112 @@ func (c *AgentMemCache) GetMetrics() (map[string]metrics.GaugeMetric, map[string
 	return gauges, counters
 }
 
-// UpdateRuntimeMonitorMetrics updates the runtime monitor metrics in the cache.
-func (c *AgentMemCache) UpdateRuntimeMonitorMetrics(m *monitors.RuntimeMonitor) {
-	c.updateMonitorMetric(metrics.NewGauge("Alloc", m.Alloc))
-	c.updateMonitorMetric(metrics.NewGauge("BuckHashSys", m.BuckHashSys))
-	c.updateMonitorMetric(metrics.NewGauge("Frees", m.Frees))
-	c.updateMonitorMetric(metrics.NewGauge("GCCPUFraction", m.GCCPUFraction))
-	c.updateMonitorMetric(metrics.NewGauge("GCSys", m.GCSys))
-	c.updateMonitorMetric(metrics.NewGauge("HeapAlloc", m.HeapAlloc))
-	c.updateMonitorMetric(metrics.NewGauge("HeapIdle", m.HeapIdle))
-	c.updateMonitorMetric(metrics.NewGauge("HeapInuse", m.HeapInuse))
-	c.updateMonitorMetric(metrics.NewGauge("HeapObjects", m.HeapObjects))
-	c.updateMonitorMetric(metrics.NewGauge("HeapReleased", m.HeapReleased))
-	c.updateMonitorMetric(metrics.NewGauge("HeapSys", m.HeapSys))
-	c.updateMonitorMetric(metrics.NewGauge("LastGC", m.LastGC))
-	c.updateMonitorMetric(metrics.NewGauge("Lookups", m.Lookups))
-	c.updateMonitorMetric(metrics.NewGauge("MCacheInuse", m.MCacheInuse))
-	c.updateMonitorMetric(metrics.NewGauge("MCacheSys", m.MCacheSys))
-	c.updateMonitorMetric(metrics.NewGauge("MSpanInuse", m.MSpanInuse))
-	c.updateMonitorMetric(metrics.NewGauge("MSpanSys", m.MSpanSys))
-	c.updateMonitorMetric(metrics.NewGauge("Mallocs", m.Mallocs))
-	c.updateMonitorMetric(metrics.NewGauge("NextGC", m.NextGC))
-	c.updateMonitorMetric(metrics.NewGauge("NumForcedGC", m.NumForcedGC))
-	c.updateMonitorMetric(metrics.NewGauge("NumGC", m.NumGC))
-	c.updateMonitorMetric(metrics.NewGauge("OtherSys", m.OtherSys))
-	c.updateMonitorMetric(metrics.NewGauge("PauseTotalNs", m.PauseTotalNs))
-	c.updateMonitorMetric(metrics.NewGauge("StackInuse", m.StackInuse))
-	c.updateMonitorMetric(metrics.NewGauge("StackSys", m.StackSys))
-	c.updateMonitorMetric(metrics.NewGauge("Sys", m.Sys))
-	c.updateMonitorMetric(metrics.NewGauge("TotalAlloc", m.TotalAlloc))
-}
-
-// UpdateUtilMonitorMetrics updates the utilization monitor metrics in the cache.
-func (c *AgentMemCache) UpdateUtilMonitorMetrics(m *monitors.UtilMonitor) {
-	c.updateMonitorMetric(metrics.NewGauge("TotalMemory", m.TotalMemory))
-	c.updateMonitorMetric(metrics.NewGauge("FreeMemory", m.FreeMemory))
-	c.updateMonitorMetric(metrics.NewGauge("CPUutilization1", m.CPUutilization1))
+// TakeMetrics returns all the gauge and counter metrics from the cache and resets the counter metrics.
+// The counter metrics are copied and reset under the same lock, so the increments made meanwhile
+// by the collectors aren't lost. The removed counter metrics are deleted once taken.
+func (c *AgentMemCache) TakeMetrics() (map[string]metrics.GaugeMetric, map[string]metrics.CounterMetric) {
+	c.gaugesMu.RLock()
+	gauges := utils.CopyMap(c.gauges)
+	c.gaugesMu.RUnlock()
+
+	c.countersMu.Lock()
+	counters := utils.CopyMap(c.counters)
+	for name, counter := range c.counters {
+		counter.ClearValue()
+		c.counters[name] = counter
+	}
+	for name := range c.removedCounters {
+		delete(c.counters, name)
+	}
+	c.removedCounters = nil
+	c.countersMu.Unlock()
+
+	return gauges, counters
+}
+
+// Declare adds the declared metrics to the metrics accepted by the cache. Declared gauge and counter
+// metrics are added to the cache with zero values, declared metrics families accept any metric
+// with the name starting with the family prefix. Declaring the same metrics again doesn't change the cache.
+func (c *AgentMemCache) Declare(descriptors ...metrics.Descriptor) {
+	c.countersMu.Lock()
+	c.gaugesMu.Lock()
+	defer c.countersMu.Unlock()
+	defer c.gaugesMu.Unlock()
+
+	for _, d := range descriptors {
+		switch d.Type {
+		case metrics.Gauge:
+			if d.Prefix {
+				if !slices.Contains(c.gaugePrefixes, d.Name) {
+					c.gaugePrefixes = append(c.gaugePrefixes, d.Name)
+				}
+			} else if _, exists := c.gauges[d.Name]; !exists {
+				c.gauges[d.Name] = metrics.NewGauge(d.Name, 0)
+			}
+		case metrics.Counter:
+			if d.Prefix {
+				if !slices.Contains(c.counterPrefixes, d.Name) {
+					c.counterPrefixes = append(c.counterPrefixes, d.Name)
+				}
+			} else if _, exists := c.counters[d.Name]; !exists {
+				c.counters[d.Name] = metrics.NewCounter(d.Name, 0)
+			}
+		}
+	}
+}
+
+// UpdateMetrics updates the collected metrics in the cache. Gauge metrics are set to the collected values,
+// counter metrics are incremented by the collected values. The PollCount counter metric is incremented
+// for each updated gauge metric.
+func (c *AgentMemCache) UpdateMetrics(ms []metrics.Metrics) {
+	for _, m := range ms {
+		switch {
+		case metrics.MetricType(m.MType) == metrics.Gauge && m.Value != nil:
+			c.updateMonitorMetric(metrics.NewGauge(m.ID, *m.Value))
+		case metrics.MetricType(m.MType) == metrics.Counter && m.Delta != nil:
+			_, err := c.UpdateCounterMetric(metrics.NewCounter(m.ID, *m.Delta))
+			if err != nil {
+				c.Logger.Error(err.Error(), zap.String("event", "update collected metric"),
+					zap.String("metric type", m.MType), zap.String("metric name", m.ID))
+			}
+		default:
+			c.Logger.Error("Invalid collected metric", zap.String("event", "update collected metric"),
+				zap.String("metric type", m.MType), zap.String("metric name", m.ID))
+		}
+	}
+}
+
+// RemoveMetrics removes the metrics of the declared metrics families from the cache, so the series
+// that are no longer collected aren't reported. The declared metrics with fixed names are kept.
+// The counter metric with the delta that isn't taken yet is deleted by the next TakeMetrics.
+func (c *AgentMemCache) RemoveMetrics(ms []metrics.Metrics) {
+	c.countersMu.Lock()
+	c.gaugesMu.Lock()
+	defer c.countersMu.Unlock()
+	defer c.gaugesMu.Unlock()
+
+	for _, m := range ms {
+		switch metrics.MetricType(m.MType) {
+		case metrics.Gauge:
+			if hasAnyPrefix(m.ID, c.gaugePrefixes) {
+				delete(c.gauges, m.ID)
+				delete(c.gaugeAggregations, m.ID)
+			}
+		case metrics.Counter:
+			counter, exists := c.counters[m.ID]
+			if !exists || !hasAnyPrefix(m.ID, c.counterPrefixes) {
+				continue
+			}
+			if counter.GetValue() == 0 {
+				delete(c.counters, m.ID)
+				continue
+			}
+			if c.removedCounters == nil {
+				c.removedCounters = make(map[string]struct{})
+			}
+			c.removedCounters[m.ID] = struct{}{}
+		}
+	}
 }
 
 func (c *AgentMemCache) updateMonitorMetric(metric metrics.GaugeMetric) {
@@ -184,7 +271,7 @@ func (c *AgentMemCache) updateMonitorMetric(metric metrics.GaugeMetric) {
 
 func (c *AgentMemCache) checkGaugeMetricNameWhenUpdate(name string) error {
 	_, exists := c.gauges[name]
-	if !exists {
+	if !exists && !hasAnyPrefix(name, c.gaugePrefixes) {
 		return er.NewInvalidMetricName(fmt.Sprintf("Invalid metric name: %s", name), nil)
 	}
 	return nil
@@ -192,7 +279,7 @@ func (c *AgentMemCache) checkGaugeMetricNameWhenUpdate(name string) error {
 
 func (c *AgentMemCache) checkCounterMetricNameWhenUpdate(name string) error {
 	_, exists := c.counters[name]
-	if !exists {
+	if !exists && !hasAnyPrefix(name, c.counterPrefixes) {
 		return er.NewInvalidMetricName(fmt.Sprintf("Invalid metric name: %s", name), nil)
 	}
 	return nil
@@ -215,3 +302,12 @@ func (c *AgentMemCache) checkCounterMetricNameWhenReset(name string) error {
 
 	return nil
 }
+
+func hasAnyPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,7 +11,6 @@ import (
 	er "github.com/Stern-Ritter/metrics-and-alerting-service/internal/errors"
 	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
-	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/monitors"
 )
 
 const (
@@ -147,7 +147,7 @@ func TestUpdateCounterMetric(t *testing.T) {
 	}
 }
 
-func TestUpdateMonitorMetrics(t *testing.T) {
+func TestUpdateMetrics(t *testing.T) {
 	type state struct {
 		gauges   map[string]metrics.GaugeMetric
 		counters map[string]metrics.CounterMetric
@@ -155,39 +155,9 @@ func TestUpdateMonitorMetrics(t *testing.T) {
 
 	testCases := []struct {
 		name          string
-		monitor       *monitors.RuntimeMonitor
 		expectedState state
 	}{{
-		name: "should correct update monitor metrics and related counters",
-		monitor: &monitors.RuntimeMonitor{
-			Alloc:         1.0,
-			BuckHashSys:   2.0,
-			Frees:         3.0,
-			GCCPUFraction: 4.0,
-			GCSys:         5.0,
-			HeapAlloc:     6.0,
-			HeapIdle:      7.0,
-			HeapInuse:     8.0,
-			HeapObjects:   9.0,
-			HeapReleased:  10.0,
-			HeapSys:       11.0,
-			LastGC:        12.0,
-			Lookups:       13.0,
-			MCacheInuse:   14.0,
-			MCacheSys:     15.0,
-			MSpanInuse:    16.0,
-			MSpanSys:      17.0,
-			Mallocs:       18.0,
-			NextGC:        19.0,
-			NumForcedGC:   20.0,
-			NumGC:         21.0,
-			OtherSys:      22.0,
-			PauseTotalNs:  23.0,
-			StackInuse:    24.0,
-			StackSys:      25.0,
-			Sys:           26.0,
-			TotalAlloc:    27.0,
-		},
+		name: "should correct update gauge metrics and related counters",
 		expectedState: state{
 			gauges: map[string]metrics.GaugeMetric{
 				"Alloc":         metrics.NewGauge("Alloc", 1.0),
@@ -230,7 +200,11 @@ func TestUpdateMonitorMetrics(t *testing.T) {
 			logger, err := logger.Initialize("info")
 			require.NoError(t, err, "Error init logger")
 			storage := NewAgentMemCache(metrics.SupportedGaugeMetrics, metrics.SupportedCounterMetrics, logger)
-			storage.UpdateRuntimeMonitorMetrics(tt.monitor)
+			ms := make([]metrics.Metrics, 0, len(tt.expectedState.gauges))
+			for _, gauge := range tt.expectedState.gauges {
+				ms = append(ms, metrics.GaugeMetricToMetrics(gauge))
+			}
+			storage.UpdateMetrics(ms)
 
 			gauges, counters := storage.GetMetrics()
 
@@ -365,3 +339,111 @@ func TestResetMetricValue(t *testing.T) {
 		})
 	}
 }
+
+func TestDeclare(t *testing.T) {
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	storage := NewAgentMemCache(make(map[string]metrics.GaugeMetric), metrics.SupportedCounterMetrics, logger)
+
+	storage.Declare(
+		metrics.NewGaugeDescriptor("TotalMemory"),
+		metrics.NewCounterDescriptor("Requests"),
+		metrics.NewPrefixDescriptor("CPUutilization", metrics.Gauge),
+	)
+
+	gauges, counters := storage.GetMetrics()
+	assert.Equal(t, metrics.NewGauge("TotalMemory", 0), gauges["TotalMemory"], "should declare gauge metric with zero value")
+	assert.Equal(t, metrics.NewCounter("Requests", 0), counters["Requests"], "should declare counter metric with zero value")
+
+	_, err = storage.UpdateGaugeMetric(metrics.NewGauge("CPUutilization2", 12.5))
+	assert.NoError(t, err, "should update gauge metric matching declared prefix")
+
+	_, err = storage.UpdateGaugeMetric(metrics.NewGauge("Unknown", 1))
+	assert.Error(t, err, "should not update undeclared gauge metric")
+
+	_, err = storage.UpdateCounterMetric(metrics.NewCounter("CPUutilization2", 1))
+	assert.Error(t, err, "should not update counter metric matching gauge prefix")
+
+	_, err = storage.UpdateCounterMetric(metrics.NewCounter("Requests", 3))
+	require.NoError(t, err)
+	storage.Declare(
+		metrics.NewCounterDescriptor("Requests"),
+		metrics.NewPrefixDescriptor("CPUutilization", metrics.Gauge),
+	)
+	_, counters = storage.GetMetrics()
+	assert.Equal(t, int64(3), counters["Requests"].Value, "should keep counter metric value when declared again")
+	assert.Len(t, storage.gaugePrefixes, 1, "should not duplicate declared prefix")
+}
+
+func TestTakeMetrics(t *testing.T) {
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	storage := NewAgentMemCache(map[string]metrics.GaugeMetric{"Alloc": metrics.NewGauge("Alloc", 1)},
+		map[string]metrics.CounterMetric{"Requests": metrics.NewCounter("Requests", 0)}, logger)
+
+	const workers, increments = 4, 1000
+	taken := int64(0)
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				_, err := storage.UpdateCounterMetric(metrics.NewCounter("Requests", 1))
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		gauges, counters := storage.TakeMetrics()
+		assert.Equal(t, 1.0, gauges["Alloc"].Value, "should keep gauge metric value")
+		taken += counters["Requests"].Value
+	}
+
+	assert.Equal(t, int64(workers*increments), taken, "should not lose counter increments made while taking metrics")
+	_, counters := storage.GetMetrics()
+	assert.Equal(t, int64(0), counters["Requests"].Value, "should reset taken counter metric")
+}
+
+func TestRemoveMetrics(t *testing.T) {
+	logger, err := logger.Initialize("info")
+	require.NoError(t, err, "Error init logger")
+	storage := NewAgentMemCache(make(map[string]metrics.GaugeMetric), make(map[string]metrics.CounterMetric), logger)
+	storage.Declare(
+		metrics.NewGaugeDescriptor("TotalMemory"),
+		metrics.NewPrefixDescriptor("DiskUsed{", metrics.Gauge),
+		metrics.NewPrefixDescriptor("DiskReadBytes{", metrics.Counter),
+	)
+
+	used, read := 40.0, int64(100)
+	storage.UpdateMetrics([]metrics.Metrics{
+		{ID: "TotalMemory", MType: string(metrics.Gauge), Value: &used},
+		{ID: "DiskUsed{mountpoint=/mnt}", MType: string(metrics.Gauge), Value: &used},
+		{ID: "DiskReadBytes{device=sdb}", MType: string(metrics.Counter), Delta: &read},
+	})
+
+	storage.RemoveMetrics([]metrics.Metrics{
+		{ID: "TotalMemory", MType: string(metrics.Gauge)},
+		{ID: "DiskUsed{mountpoint=/mnt}", MType: string(metrics.Gauge)},
+		{ID: "DiskReadBytes{device=sdb}", MType: string(metrics.Counter)},
+	})
+
+	gauges, counters := storage.TakeMetrics()
+	assert.Contains(t, gauges, "TotalMemory", "should keep declared metric with fixed name")
+	assert.NotContains(t, gauges, "DiskUsed{mountpoint=/mnt}", "should remove gauge metric of metrics family")
+	assert.Equal(t, int64(100), counters["DiskReadBytes{device=sdb}"].Value, "should take counter delta of removed metric")
+
+	_, counters = storage.GetMetrics()
+	assert.NotContains(t, counters, "DiskReadBytes{device=sdb}", "should remove counter metric once taken")
+}
@@ -0,0 +1,350 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+const (
+	spoolSegmentPrefix = "segment-"
+	spoolSegmentSuffix = ".jsonl"
+)
+
+// ErrSpoolReplaying is returned by Replay when the spool is being replayed by another goroutine.
+var ErrSpoolReplaying = errors.New("spool is being replayed")
+
+// spoolRecord is a metrics batch stored in a spool segment file.
+type spoolRecord struct {
+	Time     int64             `json:"time"`
+	AgentID  string            `json:"agent_id,omitempty"`
+	Sequence uint64            `json:"sequence,omitempty"`
+	Metrics  []metrics.Metrics `json:"metrics"`
+}
+
+// spoolSegment is a spool segment file, holding one JSON encoded record per line.
+type spoolSegment struct {
+	index   uint64
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Spool is a bounded on-disk queue of metrics batches that failed to be sent to the server.
+// Batches are appended to segment files in the spool directory and replayed in the order
+// they were written. Segments are dropped, oldest first, when the spool exceeds its maximum size,
+// and batches older than the maximum age are discarded.
+type Spool struct {
+	mu       sync.Mutex
+	replayMu sync.Mutex
+
+	dir         string
+	maxSize     int64
+	maxAge      time.Duration
+	segmentSize int64
+
+	segments  []spoolSegment
+	nextIndex uint64
+	replaying uint64
+
+	now    func() time.Time
+	Logger *logger.AgentLogger
+}
+
+// NewSpool is constructor for creating a new Spool in the specified directory.
+// The segments left in the directory by a previous run of the agent are loaded for replay.
+// The segment size is limited by the maximum spool size.
+func NewSpool(dir string, maxSize int64, maxAge time.Duration, segmentSize int64, logger *logger.AgentLogger) (*Spool, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("invalid spool max size: %d", maxSize)
+	}
+	if segmentSize <= 0 || segmentSize > maxSize {
+		segmentSize = maxSize
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create spool directory %s: %w", dir, err)
+	}
+
+	s := &Spool{
+		dir:         dir,
+		maxSize:     maxSize,
+		maxAge:      maxAge,
+		segmentSize: segmentSize,
+		nextIndex:   1,
+		now:         time.Now,
+		Logger:      logger,
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	s.enforceLimits()
+
+	return s, nil
+}
+
+// load reads the list of segment files from the spool directory.
+func (s *Spool) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("read spool directory %s: %w", s.dir, err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, spoolSegmentPrefix) || !strings.HasSuffix(name, spoolSegmentSuffix) {
+			continue
+		}
+		index, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, spoolSegmentPrefix), spoolSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("read spool segment %s: %w", name, err)
+		}
+
+		s.segments = append(s.segments, spoolSegment{
+			index:   index,
+			path:    filepath.Join(s.dir, name),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		if index >= s.nextIndex {
+			s.nextIndex = index + 1
+		}
+	}
+
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i].index < s.segments[j].index })
+	return nil
+}
+
+// Write appends the metrics batch with its key to the last segment of the spool, starting a new segment
+// when the last one reaches the segment size or is being replayed.
+func (s *Spool) Write(key metrics.BatchKey, batch []metrics.Metrics) error {
+	line, err := json.Marshal(spoolRecord{Time: s.now().Unix(), AgentID: key.AgentID, Sequence: key.Sequence, Metrics: batch})
+	if err != nil {
+		return fmt.Errorf("encode spool record: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.segments) == 0 || s.segments[len(s.segments)-1].index == s.replaying ||
+		s.segments[len(s.segments)-1].size+int64(len(line)) > s.segmentSize {
+		s.segments = append(s.segments, spoolSegment{
+			index: s.nextIndex,
+			path:  filepath.Join(s.dir, fmt.Sprintf("%s%020d%s", spoolSegmentPrefix, s.nextIndex, spoolSegmentSuffix)),
+		})
+		s.nextIndex++
+	}
+
+	last := &s.segments[len(s.segments)-1]
+	f, err := os.OpenFile(last.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open spool segment %s: %w", last.path, err)
+	}
+	_, err = f.Write(line)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write spool segment %s: %w", last.path, err)
+	}
+	last.size += int64(len(line))
+	last.modTime = s.now()
+
+	s.enforceLimits()
+	return nil
+}
+
+// Replay sends the spooled batches with their keys in the order they were written, removing each segment once
+// all of its batches are sent. Replay stops at the first batch that fails to be sent and keeps it
+// with the following batches for the next replay. Batches older than the maximum age are discarded.
+// The spool is replayed by one goroutine at a time, Replay returns ErrSpoolReplaying when it's already
+// being replayed. The batches are sent without locking the spool, so they can be written meanwhile.
+func (s *Spool) Replay(send func(key metrics.BatchKey, batch []metrics.Metrics) error) error {
+	if !s.replayMu.TryLock() {
+		return ErrSpoolReplaying
+	}
+	defer s.replayMu.Unlock()
+
+	for {
+		s.mu.Lock()
+		s.enforceLimits()
+		if len(s.segments) == 0 {
+			s.mu.Unlock()
+			return nil
+		}
+		segment := s.segments[0]
+		s.replaying = segment.index
+		s.mu.Unlock()
+
+		err := s.replaySegment(segment, send)
+
+		s.mu.Lock()
+		s.replaying = 0
+		if err == nil {
+			err = s.removeSegment(segment)
+		}
+		s.mu.Unlock()
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// replaySegment sends the batches of the segment. When a batch fails to be sent, the segment
+// is rewritten with the batches that weren't sent.
+func (s *Spool) replaySegment(segment spoolSegment, send func(key metrics.BatchKey, batch []metrics.Metrics) error) error {
+	data, err := os.ReadFile(segment.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read spool segment %s: %w", segment.path, err)
+	}
+
+	minTime := s.now().Add(-s.maxAge).Unix()
+	offset := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		lineSize := len(line) + 1
+
+		record := spoolRecord{}
+		if err := json.Unmarshal(line, &record); err != nil {
+			s.Logger.Error(err.Error(), zap.String("event", "decode spool record"), zap.String("segment", segment.path))
+			offset += lineSize
+			continue
+		}
+		if s.maxAge > 0 && record.Time < minTime {
+			s.Logger.Warn("Spooled metrics batch expired", zap.String("event", "replay spool"),
+				zap.String("segment", segment.path))
+			offset += lineSize
+			continue
+		}
+
+		key := metrics.BatchKey{AgentID: record.AgentID, Sequence: record.Sequence}
+		if err := send(key, record.Metrics); err != nil {
+			if offset > 0 {
+				s.rewriteSegment(segment, data[offset:])
+			}
+			return err
+		}
+		offset += lineSize
+	}
+
+	return nil
+}
+
+// rewriteSegment replaces the content of the segment with the batches that weren't sent,
+// unless the segment was dropped by the spool limits meanwhile.
+func (s *Spool) rewriteSegment(segment spoolSegment, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.segmentPosition(segment)
+	if i < 0 {
+		return
+	}
+
+	tmpPath := segment.path + ".tmp"
+	err := os.WriteFile(tmpPath, data, 0o600)
+	if err == nil {
+		err = os.Rename(tmpPath, segment.path)
+	}
+	if err != nil {
+		s.Logger.Error(err.Error(), zap.String("event", "rewrite spool segment"), zap.String("segment", segment.path))
+		return
+	}
+
+	s.segments[i].size = int64(len(data))
+}
+
+// removeSegment removes the replayed segment from the spool. The spool mutex should be locked.
+func (s *Spool) removeSegment(segment spoolSegment) error {
+	if err := os.Remove(segment.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove spool segment %s: %w", segment.path, err)
+	}
+	if i := s.segmentPosition(segment); i >= 0 {
+		s.segments = append(s.segments[:i], s.segments[i+1:]...)
+	}
+	return nil
+}
+
+// segmentPosition returns the position of the segment in the spool or -1, if it was dropped.
+// The spool mutex should be locked.
+func (s *Spool) segmentPosition(segment spoolSegment) int {
+	for i, sg := range s.segments {
+		if sg.index == segment.index {
+			return i
+		}
+	}
+	return -1
+}
+
+// enforceLimits removes the expired segments and the oldest segments exceeding the maximum spool size.
+// The segment being replayed is counted against the maximum size, but isn't removed, so its batches
+// that weren't sent yet aren't lost. It's removed by the replay once its batches are sent.
+func (s *Spool) enforceLimits() {
+	var total int64
+	for _, segment := range s.segments {
+		total += segment.size
+	}
+
+	minModTime := s.now().Add(-s.maxAge)
+	kept := make([]spoolSegment, 0, len(s.segments))
+	for i, segment := range s.segments {
+		expired := s.maxAge > 0 && segment.modTime.Before(minModTime)
+		if !expired && total <= s.maxSize {
+			kept = append(kept, s.segments[i:]...)
+			break
+		}
+		if segment.index == s.replaying {
+			kept = append(kept, segment)
+			continue
+		}
+
+		if err := os.Remove(segment.path); err != nil && !os.IsNotExist(err) {
+			s.Logger.Error(err.Error(), zap.String("event", "remove spool segment"), zap.String("segment", segment.path))
+			kept = append(kept, s.segments[i:]...)
+			break
+		}
+		s.Logger.Warn("Spooled metrics batches dropped", zap.String("event", "enforce spool limits"),
+			zap.String("segment", segment.path), zap.Bool("expired", expired))
+
+		total -= segment.size
+	}
+	s.segments = kept
+}
+
+// Size returns the total size of the spool segments in bytes.
+func (s *Spool) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total int64
+	for _, segment := range s.segments {
+		total += segment.size
+	}
+	return total
+}
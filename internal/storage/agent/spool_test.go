@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/agent"
+	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
+)
+
+func newSpoolBatch(name string) []metrics.Metrics {
+	value := 1.5
+	return []metrics.Metrics{{ID: name, MType: string(metrics.Gauge), Value: &value}}
+}
+
+func replayedNames(t *testing.T, s *Spool) []string {
+	names := make([]string, 0)
+	err := s.Replay(func(_ metrics.BatchKey, batch []metrics.Metrics) error {
+		names = append(names, batch[0].ID)
+		return nil
+	})
+	require.NoError(t, err, "unexpected error replaying spool")
+	return names
+}
+
+func TestSpoolReplayInOrder(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewSpool(dir, 1024*1024, time.Hour, 150, &logger.AgentLogger{Logger: zap.NewNop()})
+	require.NoError(t, err)
+
+	for _, name := range []string{"first", "second", "third"} {
+		require.NoError(t, s.Write(metrics.BatchKey{}, newSpoolBatch(name)))
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Greater(t, len(entries), 1, "should split batches into segments by the segment size")
+
+	assert.Equal(t, []string{"first", "second", "third"}, replayedNames(t, s))
+	assert.Equal(t, int64(0), s.Size(), "should remove replayed batches")
+
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "should remove replayed segments")
+}
+
+func TestSpoolReplayStopsOnError(t *testing.T) {
+	s, err := NewSpool(t.TempDir(), 1024*1024, time.Hour, 0, &logger.AgentLogger{Logger: zap.NewNop()})
+	require.NoError(t, err)
+
+	for _, name := range []string{"first", "second", "third"} {
+		require.NoError(t, s.Write(metrics.BatchKey{}, newSpoolBatch(name)))
+	}
+
+	sent := make([]string, 0)
+	err = s.Replay(func(_ metrics.BatchKey, batch []metrics.Metrics) error {
+		if batch[0].ID == "second" {
+			return errors.New("server unavailable")
+		}
+		sent = append(sent, batch[0].ID)
+		return nil
+	})
+	assert.Error(t, err, "should return the send error")
+	assert.Equal(t, []string{"first"}, sent)
+
+	assert.Equal(t, []string{"second", "third"}, replayedNames(t, s),
+		"should keep the batches that weren't sent")
+}
+
+func TestSpoolSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	aLogger := &logger.AgentLogger{Logger: zap.NewNop()}
+
+	s, err := NewSpool(dir, 1024*1024, time.Hour, 150, aLogger)
+	require.NoError(t, err)
+	require.NoError(t, s.Write(metrics.BatchKey{AgentID: "agent", Sequence: 1}, newSpoolBatch("first")))
+	require.NoError(t, s.Write(metrics.BatchKey{AgentID: "agent", Sequence: 2}, newSpoolBatch("second")))
+
+	restarted, err := NewSpool(dir, 1024*1024, time.Hour, 150, aLogger)
+	require.NoError(t, err)
+	require.NoError(t, restarted.Write(metrics.BatchKey{AgentID: "agent", Sequence: 3}, newSpoolBatch("third")))
+
+	keys := make([]metrics.BatchKey, 0)
+	names := make([]string, 0)
+	err = restarted.Replay(func(key metrics.BatchKey, batch []metrics.Metrics) error {
+		keys = append(keys, key)
+		names = append(names, batch[0].ID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first", "second", "third"}, names)
+	assert.Equal(t, []metrics.BatchKey{
+		{AgentID: "agent", Sequence: 1},
+		{AgentID: "agent", Sequence: 2},
+		{AgentID: "agent", Sequence: 3},
+	}, keys, "should replay batches with their keys")
+}
+
+func TestSpoolMaxSize(t *testing.T) {
+	line, err := json.Marshal(spoolRecord{Time: time.Now().Unix(), Metrics: newSpoolBatch("name0")})
+	require.NoError(t, err)
+	recordSize := int64(len(line) + 1)
+
+	s, err := NewSpool(t.TempDir(), 3*recordSize, time.Hour, recordSize, &logger.AgentLogger{Logger: zap.NewNop()})
+	require.NoError(t, err)
+
+	for _, name := range []string{"name1", "name2", "name3", "name4", "name5"} {
+		require.NoError(t, s.Write(metrics.BatchKey{}, newSpoolBatch(name)))
+	}
+
+	assert.Equal(t, 3*recordSize, s.Size(), "should keep the spool within the max size")
+	assert.Equal(t, []string{"name3", "name4", "name5"}, replayedNames(t, s),
+		"should drop the oldest batches")
+}
+
+func TestSpoolMaxSizeKeepsReplayingSegment(t *testing.T) {
+	line, err := json.Marshal(spoolRecord{Time: time.Now().Unix(), Metrics: newSpoolBatch("name0")})
+	require.NoError(t, err)
+	recordSize := int64(len(line) + 1)
+
+	s, err := NewSpool(t.TempDir(), 3*recordSize, time.Hour, 2*recordSize, &logger.AgentLogger{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	for _, name := range []string{"name1", "name2"} {
+		require.NoError(t, s.Write(metrics.BatchKey{}, newSpoolBatch(name)))
+	}
+
+	sent := make([]string, 0)
+	err = s.Replay(func(_ metrics.BatchKey, batch []metrics.Metrics) error {
+		if batch[0].ID == "name1" {
+			for _, name := range []string{"name3", "name4", "name5"} {
+				require.NoError(t, s.Write(metrics.BatchKey{}, newSpoolBatch(name)))
+			}
+		}
+		sent = append(sent, batch[0].ID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"name1", "name2", "name5"}, sent,
+		"should drop the oldest batches written during the replay, but not the batches being replayed")
+}
+
+func TestSpoolMaxAge(t *testing.T) {
+	s, err := NewSpool(t.TempDir(), 1024*1024, time.Minute, 0, &logger.AgentLogger{Logger: zap.NewNop()})
+	require.NoError(t, err)
+
+	now := time.Now()
+	s.now = func() time.Time { return now.Add(-2 * time.Minute) }
+	require.NoError(t, s.Write(metrics.BatchKey{}, newSpoolBatch("expired")))
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.Write(metrics.BatchKey{}, newSpoolBatch("actual")))
+
+	assert.Equal(t, []string{"actual"}, replayedNames(t, s), "should discard expired batches")
+}
+
+func TestNewSpoolInvalidMaxSize(t *testing.T) {
+	_, err := NewSpool(t.TempDir(), 0, time.Hour, 0, &logger.AgentLogger{Logger: zap.NewNop()})
+	assert.Error(t, err)
+}
+
+func TestSpoolReplayByOneGoroutine(t *testing.T) {
+	s, err := NewSpool(t.TempDir(), 1024*1024, time.Hour, 0, &logger.AgentLogger{Logger: zap.NewNop()})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Write(metrics.BatchKey{}, newSpoolBatch("first")))
+
+	sent := make([]string, 0)
+	err = s.Replay(func(_ metrics.BatchKey, batch []metrics.Metrics) error {
+		if batch[0].ID == "first" {
+			assert.ErrorIs(t, s.Replay(func(metrics.BatchKey, []metrics.Metrics) error { return nil }), ErrSpoolReplaying,
+				"should not replay the spool being replayed")
+			require.NoError(t, s.Write(metrics.BatchKey{}, newSpoolBatch("second")),
+				"should write to the spool being replayed")
+		}
+		sent = append(sent, batch[0].ID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first", "second"}, sent,
+		"should replay the batches written during the replay")
+	assert.Equal(t, int64(0), s.Size(), "should remove replayed batches")
+}
@@ -5,12 +5,16 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	er "github.com/Stern-Ritter/metrics-and-alerting-service/internal/errors"
 	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/server"
 	"github.com/Stern-Ritter/metrics-and-alerting-service/internal/model/metrics"
 )
 
+// appliedBatchesRetention is the time the applied batch keys are kept in the database and in the memory storage.
+const appliedBatchesRetention = 24 * time.Hour
+
 // DBStorage is an implementation of Storage that uses a database.
 type DBStorage struct {
 	db     *sql.DB
@@ -58,6 +62,59 @@ func (s *DBStorage) UpdateMetrics(ctx context.Context, metricsBatch []metrics.Me
 	return tx.Commit()
 }
 
+// UpdateMetricsOnce updates multiple metrics in the database unless the batch with the same key
+// was applied within the retention period. The batch key is saved in the same transaction
+// as the metrics, so the batch is applied at most once. It reports whether the batch was applied.
+func (s *DBStorage) UpdateMetricsOnce(ctx context.Context, key metrics.BatchKey, metricsBatch []metrics.Metrics) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO applied_batches
+		(agent_id, sequence)
+		VALUES
+		($1, $2)
+		ON CONFLICT DO NOTHING
+	`, key.AgentID, int64(key.Sequence))
+	if err != nil {
+		//nolint:errcheck
+		tx.Rollback()
+		return false, err
+	}
+
+	inserted, err := res.RowsAffected()
+	if err != nil || inserted == 0 {
+		//nolint:errcheck
+		tx.Rollback()
+		return false, err
+	}
+
+	for _, metric := range metricsBatch {
+		err = s.updateMetricInTx(ctx, tx, metric)
+		if err != nil {
+			//nolint:errcheck
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM applied_batches
+		WHERE
+			agent_id = $1 AND
+			applied_at < $2
+	`, key.AgentID, time.Now().Add(-appliedBatchesRetention))
+	if err != nil {
+		//nolint:errcheck
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
 func (s *DBStorage) updateMetricInTx(ctx context.Context, tx *sql.Tx, metric metrics.Metrics) error {
 	mValue, err := metric.GetValue()
 	if err != nil {
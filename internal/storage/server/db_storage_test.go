@@ -122,6 +122,66 @@ func TestDBStorage_UpdateMetrics(t *testing.T) {
 	})
 }
 
+func TestDBStorage_UpdateMetricsOnce(t *testing.T) {
+	key := metrics.BatchKey{AgentID: "agent", Sequence: 42}
+	metricsBatch := []metrics.Metrics{
+		{
+			ID:    "first",
+			MType: "counter",
+			Delta: int64Ptr(10),
+		},
+	}
+
+	t.Run("Apply new batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err, "unexpected error when mock db connection")
+		defer db.Close()
+
+		storage := NewDBStorage(db, &logger.ServerLogger{})
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO applied_batches \(agent_id, sequence\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+			WithArgs(key.AgentID, int64(key.Sequence)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT id, value FROM metrics WHERE name = \$1 AND type = \$2`).
+			WithArgs("first", "counter").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "value"}).AddRow(1, 5))
+		mock.ExpectExec(`UPDATE metrics SET value = \$1 WHERE id = \$2`).
+			WithArgs(float64(15), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM applied_batches WHERE agent_id = \$1 AND applied_at < \$2`).
+			WithArgs(key.AgentID, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		applied, err := storage.UpdateMetricsOnce(context.Background(), key, metricsBatch)
+		assert.NoError(t, err, "unexpected error when update metrics")
+		assert.True(t, applied, "should apply new batch")
+
+		assert.NoError(t, mock.ExpectationsWereMet(), "not all queued expectations were met in order")
+	})
+
+	t.Run("Skip applied batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err, "unexpected error when mock db connection")
+		defer db.Close()
+
+		storage := NewDBStorage(db, &logger.ServerLogger{})
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO applied_batches \(agent_id, sequence\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+			WithArgs(key.AgentID, int64(key.Sequence)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		applied, err := storage.UpdateMetricsOnce(context.Background(), key, metricsBatch)
+		assert.NoError(t, err, "unexpected error when update metrics")
+		assert.False(t, applied, "shouldn't apply batch again")
+
+		assert.NoError(t, mock.ExpectationsWereMet(), "not all queued expectations were met in order")
+	})
+}
+
 func TestDBStorage_GetMetric(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err, "unexpected error when mock db connection")
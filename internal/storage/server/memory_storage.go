@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	er "github.com/Stern-Ritter/metrics-and-alerting-service/internal/errors"
 	logger "github.com/Stern-Ritter/metrics-and-alerting-service/internal/logger/server"
@@ -15,18 +16,37 @@ import (
 )
 
 // StorageState is the state of the in-memory implementation of the Storage interface.
+// The recently applied batch sequence numbers are saved by agent ID with the metrics they were applied to,
+// so the batches replayed by the agents after the server restart aren't applied again.
 type StorageState struct {
 	Gauges   map[string]metrics.GaugeMetric   `json:"gauges"`
 	Counters map[string]metrics.CounterMetric `json:"counters"`
+	Batches  map[string][]AppliedBatch        `json:"batches,omitempty"`
+}
+
+// AppliedBatch is the sequence number of the batch applied to the memory storage and the time it was applied.
+type AppliedBatch struct {
+	Sequence  uint64    `json:"sequence"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// appliedBatches holds the batch sequence numbers of an agent applied within the retention time,
+// in the order they were applied.
+type appliedBatches struct {
+	sequences map[uint64]struct{}
+	order     []AppliedBatch
 }
 
 // MemoryStorage is an in-memory implementation of the Storage interface.
 type MemoryStorage struct {
 	gaugesMu   sync.Mutex
 	countersMu sync.Mutex
+	batchesMu  sync.Mutex
 
 	gauges   map[string]metrics.GaugeMetric
 	counters map[string]metrics.CounterMetric
+	batches  map[string]*appliedBatches
+	now      func() time.Time
 
 	Logger *logger.ServerLogger
 }
@@ -36,6 +56,8 @@ func NewMemoryStorage(logger *logger.ServerLogger) *MemoryStorage {
 	return &MemoryStorage{
 		gauges:   make(map[string]metrics.GaugeMetric),
 		counters: make(map[string]metrics.CounterMetric),
+		batches:  make(map[string]*appliedBatches),
+		now:      time.Now,
 		Logger:   logger,
 	}
 }
@@ -86,6 +108,48 @@ func (s *MemoryStorage) UpdateMetrics(ctx context.Context, metricsBatch []metric
 	return nil
 }
 
+// UpdateMetricsOnce updates multiple metrics in the memory storage unless the batch with the same key
+// is among the recently applied batches of the agent. It reports whether the batch was applied.
+func (s *MemoryStorage) UpdateMetricsOnce(ctx context.Context, key metrics.BatchKey, metricsBatch []metrics.Metrics) (bool, error) {
+	s.batchesMu.Lock()
+	defer s.batchesMu.Unlock()
+
+	now := s.now()
+	applied, exists := s.batches[key.AgentID]
+	if !exists {
+		applied = &appliedBatches{sequences: make(map[uint64]struct{})}
+		s.batches[key.AgentID] = applied
+	}
+	applied.removeExpired(now.Add(-appliedBatchesRetention))
+	if _, ok := applied.sequences[key.Sequence]; ok {
+		return false, nil
+	}
+
+	if err := s.UpdateMetrics(ctx, metricsBatch); err != nil {
+		return false, err
+	}
+
+	applied.add(AppliedBatch{Sequence: key.Sequence, AppliedAt: now})
+
+	return true, nil
+}
+
+// add remembers the applied batch sequence number.
+func (a *appliedBatches) add(batch AppliedBatch) {
+	a.sequences[batch.Sequence] = struct{}{}
+	a.order = append(a.order, batch)
+}
+
+// removeExpired forgets the batch sequence numbers applied before the specified time.
+func (a *appliedBatches) removeExpired(before time.Time) {
+	expired := 0
+	for expired < len(a.order) && a.order[expired].AppliedAt.Before(before) {
+		delete(a.sequences, a.order[expired].Sequence)
+		expired++
+	}
+	a.order = a.order[expired:]
+}
+
 func (s *MemoryStorage) updateGaugeMetric(metric metrics.GaugeMetric) (metrics.GaugeMetric, error) {
 	s.gaugesMu.Lock()
 	defer s.gaugesMu.Unlock()
@@ -183,6 +247,23 @@ func (s *MemoryStorage) Restore(fname string) error {
 		return err
 	}
 
+	expireBefore := s.now().Add(-appliedBatchesRetention)
+	batches := make(map[string]*appliedBatches, len(state.Batches))
+	for agentID, appliedOrder := range state.Batches {
+		applied := &appliedBatches{sequences: make(map[uint64]struct{})}
+		for _, batch := range appliedOrder {
+			applied.add(batch)
+		}
+		applied.removeExpired(expireBefore)
+		if len(applied.order) > 0 {
+			batches[agentID] = applied
+		}
+	}
+
+	s.batchesMu.Lock()
+	s.batches = batches
+	s.batchesMu.Unlock()
+
 	s.gaugesMu.Lock()
 	s.gauges = state.Gauges
 	s.gaugesMu.Unlock()
@@ -202,19 +283,32 @@ func (s *MemoryStorage) Save(fname string) error {
 	}
 	defer file.Close()
 
+	s.batchesMu.Lock()
 	s.gaugesMu.Lock()
 	s.countersMu.Lock()
+	expireBefore := s.now().Add(-appliedBatchesRetention)
+	batches := make(map[string][]AppliedBatch, len(s.batches))
+	for agentID, applied := range s.batches {
+		applied.removeExpired(expireBefore)
+		if len(applied.order) == 0 {
+			delete(s.batches, agentID)
+			continue
+		}
+		batches[agentID] = applied.order
+	}
 	state := StorageState{
 		Gauges:   s.gauges,
 		Counters: s.counters,
+		Batches:  batches,
 	}
 
 	data, err := json.Marshal(&state)
+	s.countersMu.Unlock()
+	s.gaugesMu.Unlock()
+	s.batchesMu.Unlock()
 	if err != nil {
 		return err
 	}
-	s.gaugesMu.Unlock()
-	s.countersMu.Unlock()
 
 	_, err = file.Write(data)
 	return err
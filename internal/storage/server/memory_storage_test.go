@@ -3,8 +3,10 @@ package server
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -270,6 +272,71 @@ func TestMemoryStorage_UpdateMetrics(t *testing.T) {
 	}
 }
 
+func TestMemoryStorage_UpdateMetricsOnce(t *testing.T) {
+	storage := NewMemoryStorage(&logger.ServerLogger{})
+	delta := int64(10)
+	metricsBatch := []metrics.Metrics{{ID: defaultMetricName, MType: validCounterMetricType, Delta: &delta}}
+	key := metrics.BatchKey{AgentID: "agent", Sequence: 1}
+
+	applied, err := storage.UpdateMetricsOnce(context.Background(), key, metricsBatch)
+	require.NoError(t, err)
+	assert.True(t, applied, "should apply new batch")
+
+	applied, err = storage.UpdateMetricsOnce(context.Background(), key, metricsBatch)
+	require.NoError(t, err)
+	assert.False(t, applied, "shouldn't apply batch with the same key again")
+
+	applied, err = storage.UpdateMetricsOnce(context.Background(),
+		metrics.BatchKey{AgentID: "other", Sequence: 1}, metricsBatch)
+	require.NoError(t, err)
+	assert.True(t, applied, "should apply batch with the same sequence of other agent")
+
+	m, err := storage.GetMetric(context.Background(), metrics.Metrics{ID: defaultMetricName, MType: validCounterMetricType})
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), *m.Delta, "should add counter deltas of applied batches only")
+
+	now := time.Now()
+	storage.now = func() time.Time { return now.Add(appliedBatchesRetention - time.Second) }
+	applied, err = storage.UpdateMetricsOnce(context.Background(), key, nil)
+	require.NoError(t, err)
+	assert.False(t, applied, "should remember batch keys within the retention time")
+
+	storage.now = func() time.Time { return now.Add(appliedBatchesRetention + time.Second) }
+	applied, err = storage.UpdateMetricsOnce(context.Background(), key, nil)
+	require.NoError(t, err)
+	assert.True(t, applied, "should forget batch keys applied before the retention time")
+}
+
+func TestMemoryStorage_SaveAndRestoreAppliedBatches(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "file-storage.json")
+	delta := int64(10)
+	metricsBatch := []metrics.Metrics{{ID: defaultMetricName, MType: validCounterMetricType, Delta: &delta}}
+	key := metrics.BatchKey{AgentID: "agent", Sequence: 1}
+
+	storage := NewMemoryStorage(&logger.ServerLogger{})
+	_, err := storage.UpdateMetricsOnce(context.Background(), key, metricsBatch)
+	require.NoError(t, err)
+	require.NoError(t, storage.Save(fname))
+
+	restored := NewMemoryStorage(&logger.ServerLogger{})
+	require.NoError(t, restored.Restore(fname))
+	assert.WithinDuration(t, storage.batches["agent"].order[0].AppliedAt,
+		restored.batches["agent"].order[0].AppliedAt, 0, "should restore time the batch was applied")
+
+	applied, err := restored.UpdateMetricsOnce(context.Background(), key, metricsBatch)
+	require.NoError(t, err)
+	assert.False(t, applied, "shouldn't apply batch replayed after restore again")
+
+	applied, err = restored.UpdateMetricsOnce(context.Background(),
+		metrics.BatchKey{AgentID: "agent", Sequence: 2}, metricsBatch)
+	require.NoError(t, err)
+	assert.True(t, applied, "should apply new batch after restore")
+
+	m, err := restored.GetMetric(context.Background(), metrics.Metrics{ID: defaultMetricName, MType: validCounterMetricType})
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), *m.Delta, "should count replayed batch once")
+}
+
 func TestMemoryStorage_GetMetric(t *testing.T) {
 	testCases := []struct {
 		name              string
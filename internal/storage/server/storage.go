@@ -12,6 +12,9 @@ type Storage interface {
 	UpdateMetric(ctx context.Context, metric metrics.Metrics) error
 	// UpdateMetrics updates multiple metrics in the storage.
 	UpdateMetrics(ctx context.Context, metrics []metrics.Metrics) error
+	// UpdateMetricsOnce updates multiple metrics in the storage unless the batch with the same key
+	// was recently applied. It reports whether the batch was applied.
+	UpdateMetricsOnce(ctx context.Context, key metrics.BatchKey, metrics []metrics.Metrics) (bool, error)
 	// GetMetric gets a single metric from the storage.
 	GetMetric(ctx context.Context, metric metrics.Metrics) (metrics.Metrics, error)
 	// GetMetrics gets all metrics from the storage.
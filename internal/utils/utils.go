@@ -94,8 +94,8 @@ func AddProtocolPrefix(url string) string {
 // If the first value is non-zero, it will be returned.
 // Otherwise, the second value is returned.
 //
-// This function supports the types string, int, and bool.
-func Coalesce[T string | int | bool](firstValue, secondValue T) T {
+// This function supports the types string, int, int64, and bool.
+func Coalesce[T string | int | int64 | bool](firstValue, secondValue T) T {
 	var zeroValue T
 	if firstValue != zeroValue {
 		return firstValue
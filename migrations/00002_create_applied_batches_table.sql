@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE applied_batches
+(
+    agent_id   VARCHAR(256) NOT NULL,
+    sequence   BIGINT       NOT NULL,
+    applied_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (agent_id, sequence)
+);
+
+CREATE INDEX applied_batches_applied_at_idx ON applied_batches (agent_id, applied_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE applied_batches;
+-- +goose StatementEnd
@@ -16,6 +16,7 @@ import (
 	v1 "github.com/Stern-Ritter/metrics-and-alerting-service/proto/gen/metrics/metricsapi/v1"
 	gomock "go.uber.org/mock/gomock"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
 // MockMetricsV1ServiceClient is a mock of MetricsV1ServiceClient interface.
@@ -41,6 +42,26 @@ func (m *MockMetricsV1ServiceClient) EXPECT() *MockMetricsV1ServiceClientMockRec
 	return m.recorder
 }
 
+// ExportMetrics mocks base method.
+func (m *MockMetricsV1ServiceClient) ExportMetrics(ctx context.Context, in *v1.MetricsV1ServiceExportMetricsRequest, opts ...grpc.CallOption) (v1.MetricsV1Service_ExportMetricsClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExportMetrics", varargs...)
+	ret0, _ := ret[0].(v1.MetricsV1Service_ExportMetricsClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportMetrics indicates an expected call of ExportMetrics.
+func (mr *MockMetricsV1ServiceClientMockRecorder) ExportMetrics(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportMetrics", reflect.TypeOf((*MockMetricsV1ServiceClient)(nil).ExportMetrics), varargs...)
+}
+
 // GetMetric mocks base method.
 func (m *MockMetricsV1ServiceClient) GetMetric(ctx context.Context, in *v1.MetricsV1ServiceGetMetricRequest, opts ...grpc.CallOption) (*v1.MetricsV1ServiceGetMetricResponse, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +122,26 @@ func (mr *MockMetricsV1ServiceClientMockRecorder) Ping(ctx, in any, opts ...any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockMetricsV1ServiceClient)(nil).Ping), varargs...)
 }
 
+// StreamMetrics mocks base method.
+func (m *MockMetricsV1ServiceClient) StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (v1.MetricsV1Service_StreamMetricsClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StreamMetrics", varargs...)
+	ret0, _ := ret[0].(v1.MetricsV1Service_StreamMetricsClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamMetrics indicates an expected call of StreamMetrics.
+func (mr *MockMetricsV1ServiceClientMockRecorder) StreamMetrics(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamMetrics", reflect.TypeOf((*MockMetricsV1ServiceClient)(nil).StreamMetrics), varargs...)
+}
+
 // UpdateMetric mocks base method.
 func (m *MockMetricsV1ServiceClient) UpdateMetric(ctx context.Context, in *v1.MetricsV1ServiceUpdateMetricRequest, opts ...grpc.CallOption) (*v1.MetricsV1ServiceUpdateMetricResponse, error) {
 	m.ctrl.T.Helper()
@@ -141,6 +182,409 @@ func (mr *MockMetricsV1ServiceClientMockRecorder) UpdateMetricsBatch(ctx, in any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricsBatch", reflect.TypeOf((*MockMetricsV1ServiceClient)(nil).UpdateMetricsBatch), varargs...)
 }
 
+// WatchMetrics mocks base method.
+func (m *MockMetricsV1ServiceClient) WatchMetrics(ctx context.Context, in *v1.MetricsV1ServiceWatchMetricsRequest, opts ...grpc.CallOption) (v1.MetricsV1Service_WatchMetricsClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchMetrics", varargs...)
+	ret0, _ := ret[0].(v1.MetricsV1Service_WatchMetricsClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchMetrics indicates an expected call of WatchMetrics.
+func (mr *MockMetricsV1ServiceClientMockRecorder) WatchMetrics(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchMetrics", reflect.TypeOf((*MockMetricsV1ServiceClient)(nil).WatchMetrics), varargs...)
+}
+
+// MockMetricsV1Service_StreamMetricsClient is a mock of MetricsV1Service_StreamMetricsClient interface.
+type MockMetricsV1Service_StreamMetricsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsV1Service_StreamMetricsClientMockRecorder
+}
+
+// MockMetricsV1Service_StreamMetricsClientMockRecorder is the mock recorder for MockMetricsV1Service_StreamMetricsClient.
+type MockMetricsV1Service_StreamMetricsClientMockRecorder struct {
+	mock *MockMetricsV1Service_StreamMetricsClient
+}
+
+// NewMockMetricsV1Service_StreamMetricsClient creates a new mock instance.
+func NewMockMetricsV1Service_StreamMetricsClient(ctrl *gomock.Controller) *MockMetricsV1Service_StreamMetricsClient {
+	mock := &MockMetricsV1Service_StreamMetricsClient{ctrl: ctrl}
+	mock.recorder = &MockMetricsV1Service_StreamMetricsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricsV1Service_StreamMetricsClient) EXPECT() *MockMetricsV1Service_StreamMetricsClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockMetricsV1Service_StreamMetricsClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockMetricsV1Service_StreamMetricsClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockMetricsV1Service_StreamMetricsClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsClient) Recv() (*v1.MetricsV1ServiceStreamMetricsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*v1.MetricsV1ServiceStreamMetricsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockMetricsV1Service_StreamMetricsClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockMetricsV1Service_StreamMetricsClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockMetricsV1Service_StreamMetricsClientMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsClient)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsClient) Send(arg0 *v1.MetricsV1ServiceStreamMetricsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMetricsV1Service_StreamMetricsClientMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockMetricsV1Service_StreamMetricsClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockMetricsV1Service_StreamMetricsClientMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockMetricsV1Service_StreamMetricsClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsClient)(nil).Trailer))
+}
+
+// MockMetricsV1Service_WatchMetricsClient is a mock of MetricsV1Service_WatchMetricsClient interface.
+type MockMetricsV1Service_WatchMetricsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsV1Service_WatchMetricsClientMockRecorder
+}
+
+// MockMetricsV1Service_WatchMetricsClientMockRecorder is the mock recorder for MockMetricsV1Service_WatchMetricsClient.
+type MockMetricsV1Service_WatchMetricsClientMockRecorder struct {
+	mock *MockMetricsV1Service_WatchMetricsClient
+}
+
+// NewMockMetricsV1Service_WatchMetricsClient creates a new mock instance.
+func NewMockMetricsV1Service_WatchMetricsClient(ctrl *gomock.Controller) *MockMetricsV1Service_WatchMetricsClient {
+	mock := &MockMetricsV1Service_WatchMetricsClient{ctrl: ctrl}
+	mock.recorder = &MockMetricsV1Service_WatchMetricsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricsV1Service_WatchMetricsClient) EXPECT() *MockMetricsV1Service_WatchMetricsClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockMetricsV1Service_WatchMetricsClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockMetricsV1Service_WatchMetricsClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockMetricsV1Service_WatchMetricsClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsClient) Recv() (*v1.MetricsV1ServiceWatchMetricsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*v1.MetricsV1ServiceWatchMetricsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockMetricsV1Service_WatchMetricsClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockMetricsV1Service_WatchMetricsClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockMetricsV1Service_WatchMetricsClientMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockMetricsV1Service_WatchMetricsClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockMetricsV1Service_WatchMetricsClientMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockMetricsV1Service_WatchMetricsClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsClient)(nil).Trailer))
+}
+
+// MockMetricsV1Service_ExportMetricsClient is a mock of MetricsV1Service_ExportMetricsClient interface.
+type MockMetricsV1Service_ExportMetricsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsV1Service_ExportMetricsClientMockRecorder
+}
+
+// MockMetricsV1Service_ExportMetricsClientMockRecorder is the mock recorder for MockMetricsV1Service_ExportMetricsClient.
+type MockMetricsV1Service_ExportMetricsClientMockRecorder struct {
+	mock *MockMetricsV1Service_ExportMetricsClient
+}
+
+// NewMockMetricsV1Service_ExportMetricsClient creates a new mock instance.
+func NewMockMetricsV1Service_ExportMetricsClient(ctrl *gomock.Controller) *MockMetricsV1Service_ExportMetricsClient {
+	mock := &MockMetricsV1Service_ExportMetricsClient{ctrl: ctrl}
+	mock.recorder = &MockMetricsV1Service_ExportMetricsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricsV1Service_ExportMetricsClient) EXPECT() *MockMetricsV1Service_ExportMetricsClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockMetricsV1Service_ExportMetricsClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockMetricsV1Service_ExportMetricsClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockMetricsV1Service_ExportMetricsClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsClient) Recv() (*v1.MetricsV1ServiceExportMetricsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*v1.MetricsV1ServiceExportMetricsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockMetricsV1Service_ExportMetricsClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockMetricsV1Service_ExportMetricsClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockMetricsV1Service_ExportMetricsClientMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockMetricsV1Service_ExportMetricsClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockMetricsV1Service_ExportMetricsClientMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockMetricsV1Service_ExportMetricsClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsClient)(nil).Trailer))
+}
+
 // MockMetricsV1ServiceServer is a mock of MetricsV1ServiceServer interface.
 type MockMetricsV1ServiceServer struct {
 	ctrl     *gomock.Controller
@@ -164,6 +608,20 @@ func (m *MockMetricsV1ServiceServer) EXPECT() *MockMetricsV1ServiceServerMockRec
 	return m.recorder
 }
 
+// ExportMetrics mocks base method.
+func (m *MockMetricsV1ServiceServer) ExportMetrics(arg0 *v1.MetricsV1ServiceExportMetricsRequest, arg1 v1.MetricsV1Service_ExportMetricsServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportMetrics", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportMetrics indicates an expected call of ExportMetrics.
+func (mr *MockMetricsV1ServiceServerMockRecorder) ExportMetrics(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportMetrics", reflect.TypeOf((*MockMetricsV1ServiceServer)(nil).ExportMetrics), arg0, arg1)
+}
+
 // GetMetric mocks base method.
 func (m *MockMetricsV1ServiceServer) GetMetric(arg0 context.Context, arg1 *v1.MetricsV1ServiceGetMetricRequest) (*v1.MetricsV1ServiceGetMetricResponse, error) {
 	m.ctrl.T.Helper()
@@ -209,6 +667,20 @@ func (mr *MockMetricsV1ServiceServerMockRecorder) Ping(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockMetricsV1ServiceServer)(nil).Ping), arg0, arg1)
 }
 
+// StreamMetrics mocks base method.
+func (m *MockMetricsV1ServiceServer) StreamMetrics(arg0 v1.MetricsV1Service_StreamMetricsServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamMetrics", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamMetrics indicates an expected call of StreamMetrics.
+func (mr *MockMetricsV1ServiceServerMockRecorder) StreamMetrics(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamMetrics", reflect.TypeOf((*MockMetricsV1ServiceServer)(nil).StreamMetrics), arg0)
+}
+
 // UpdateMetric mocks base method.
 func (m *MockMetricsV1ServiceServer) UpdateMetric(arg0 context.Context, arg1 *v1.MetricsV1ServiceUpdateMetricRequest) (*v1.MetricsV1ServiceUpdateMetricResponse, error) {
 	m.ctrl.T.Helper()
@@ -239,6 +711,20 @@ func (mr *MockMetricsV1ServiceServerMockRecorder) UpdateMetricsBatch(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricsBatch", reflect.TypeOf((*MockMetricsV1ServiceServer)(nil).UpdateMetricsBatch), arg0, arg1)
 }
 
+// WatchMetrics mocks base method.
+func (m *MockMetricsV1ServiceServer) WatchMetrics(arg0 *v1.MetricsV1ServiceWatchMetricsRequest, arg1 v1.MetricsV1Service_WatchMetricsServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchMetrics", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchMetrics indicates an expected call of WatchMetrics.
+func (mr *MockMetricsV1ServiceServerMockRecorder) WatchMetrics(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchMetrics", reflect.TypeOf((*MockMetricsV1ServiceServer)(nil).WatchMetrics), arg0, arg1)
+}
+
 // mustEmbedUnimplementedMetricsV1ServiceServer mocks base method.
 func (m *MockMetricsV1ServiceServer) mustEmbedUnimplementedMetricsV1ServiceServer() {
 	m.ctrl.T.Helper()
@@ -285,3 +771,375 @@ func (mr *MockUnsafeMetricsV1ServiceServerMockRecorder) mustEmbedUnimplementedMe
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedMetricsV1ServiceServer", reflect.TypeOf((*MockUnsafeMetricsV1ServiceServer)(nil).mustEmbedUnimplementedMetricsV1ServiceServer))
 }
+
+// MockMetricsV1Service_StreamMetricsServer is a mock of MetricsV1Service_StreamMetricsServer interface.
+type MockMetricsV1Service_StreamMetricsServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsV1Service_StreamMetricsServerMockRecorder
+}
+
+// MockMetricsV1Service_StreamMetricsServerMockRecorder is the mock recorder for MockMetricsV1Service_StreamMetricsServer.
+type MockMetricsV1Service_StreamMetricsServerMockRecorder struct {
+	mock *MockMetricsV1Service_StreamMetricsServer
+}
+
+// NewMockMetricsV1Service_StreamMetricsServer creates a new mock instance.
+func NewMockMetricsV1Service_StreamMetricsServer(ctrl *gomock.Controller) *MockMetricsV1Service_StreamMetricsServer {
+	mock := &MockMetricsV1Service_StreamMetricsServer{ctrl: ctrl}
+	mock.recorder = &MockMetricsV1Service_StreamMetricsServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricsV1Service_StreamMetricsServer) EXPECT() *MockMetricsV1Service_StreamMetricsServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockMetricsV1Service_StreamMetricsServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsServer) Recv() (*v1.MetricsV1ServiceStreamMetricsRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*v1.MetricsV1ServiceStreamMetricsRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockMetricsV1Service_StreamMetricsServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockMetricsV1Service_StreamMetricsServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockMetricsV1Service_StreamMetricsServerMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsServer) Send(arg0 *v1.MetricsV1ServiceStreamMetricsResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMetricsV1Service_StreamMetricsServerMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockMetricsV1Service_StreamMetricsServerMockRecorder) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockMetricsV1Service_StreamMetricsServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockMetricsV1Service_StreamMetricsServerMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockMetricsV1Service_StreamMetricsServerMockRecorder) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockMetricsV1Service_StreamMetricsServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockMetricsV1Service_StreamMetricsServerMockRecorder) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockMetricsV1Service_StreamMetricsServer)(nil).SetTrailer), arg0)
+}
+
+// MockMetricsV1Service_WatchMetricsServer is a mock of MetricsV1Service_WatchMetricsServer interface.
+type MockMetricsV1Service_WatchMetricsServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsV1Service_WatchMetricsServerMockRecorder
+}
+
+// MockMetricsV1Service_WatchMetricsServerMockRecorder is the mock recorder for MockMetricsV1Service_WatchMetricsServer.
+type MockMetricsV1Service_WatchMetricsServerMockRecorder struct {
+	mock *MockMetricsV1Service_WatchMetricsServer
+}
+
+// NewMockMetricsV1Service_WatchMetricsServer creates a new mock instance.
+func NewMockMetricsV1Service_WatchMetricsServer(ctrl *gomock.Controller) *MockMetricsV1Service_WatchMetricsServer {
+	mock := &MockMetricsV1Service_WatchMetricsServer{ctrl: ctrl}
+	mock.recorder = &MockMetricsV1Service_WatchMetricsServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricsV1Service_WatchMetricsServer) EXPECT() *MockMetricsV1Service_WatchMetricsServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockMetricsV1Service_WatchMetricsServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockMetricsV1Service_WatchMetricsServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockMetricsV1Service_WatchMetricsServerMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsServer) Send(arg0 *v1.MetricsV1ServiceWatchMetricsResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMetricsV1Service_WatchMetricsServerMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockMetricsV1Service_WatchMetricsServerMockRecorder) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockMetricsV1Service_WatchMetricsServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockMetricsV1Service_WatchMetricsServerMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockMetricsV1Service_WatchMetricsServerMockRecorder) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockMetricsV1Service_WatchMetricsServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockMetricsV1Service_WatchMetricsServerMockRecorder) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockMetricsV1Service_WatchMetricsServer)(nil).SetTrailer), arg0)
+}
+
+// MockMetricsV1Service_ExportMetricsServer is a mock of MetricsV1Service_ExportMetricsServer interface.
+type MockMetricsV1Service_ExportMetricsServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsV1Service_ExportMetricsServerMockRecorder
+}
+
+// MockMetricsV1Service_ExportMetricsServerMockRecorder is the mock recorder for MockMetricsV1Service_ExportMetricsServer.
+type MockMetricsV1Service_ExportMetricsServerMockRecorder struct {
+	mock *MockMetricsV1Service_ExportMetricsServer
+}
+
+// NewMockMetricsV1Service_ExportMetricsServer creates a new mock instance.
+func NewMockMetricsV1Service_ExportMetricsServer(ctrl *gomock.Controller) *MockMetricsV1Service_ExportMetricsServer {
+	mock := &MockMetricsV1Service_ExportMetricsServer{ctrl: ctrl}
+	mock.recorder = &MockMetricsV1Service_ExportMetricsServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricsV1Service_ExportMetricsServer) EXPECT() *MockMetricsV1Service_ExportMetricsServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockMetricsV1Service_ExportMetricsServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockMetricsV1Service_ExportMetricsServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockMetricsV1Service_ExportMetricsServerMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsServer) Send(arg0 *v1.MetricsV1ServiceExportMetricsResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMetricsV1Service_ExportMetricsServerMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockMetricsV1Service_ExportMetricsServerMockRecorder) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockMetricsV1Service_ExportMetricsServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockMetricsV1Service_ExportMetricsServerMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockMetricsV1Service_ExportMetricsServerMockRecorder) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockMetricsV1Service_ExportMetricsServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockMetricsV1Service_ExportMetricsServerMockRecorder) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockMetricsV1Service_ExportMetricsServer)(nil).SetTrailer), arg0)
+}
@@ -607,6 +607,319 @@ func (*MetricsV1ServicePingResponse) Descriptor() ([]byte, []int) {
 	return file_metrics_metricsapi_v1_metrics_proto_rawDescGZIP(), []int{11}
 }
 
+type MetricsV1ServiceStreamMetricsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sequence uint64        `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Metrics  []*MetricData `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	Sign     string        `protobuf:"bytes,3,opt,name=sign,proto3" json:"sign,omitempty"`
+}
+
+func (x *MetricsV1ServiceStreamMetricsRequest) Reset() {
+	*x = MetricsV1ServiceStreamMetricsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricsV1ServiceStreamMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsV1ServiceStreamMetricsRequest) ProtoMessage() {}
+
+func (x *MetricsV1ServiceStreamMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsV1ServiceStreamMetricsRequest.ProtoReflect.Descriptor instead.
+func (*MetricsV1ServiceStreamMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_metrics_metricsapi_v1_metrics_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MetricsV1ServiceStreamMetricsRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *MetricsV1ServiceStreamMetricsRequest) GetMetrics() []*MetricData {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *MetricsV1ServiceStreamMetricsRequest) GetSign() string {
+	if x != nil {
+		return x.Sign
+	}
+	return ""
+}
+
+type MetricsV1ServiceStreamMetricsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sequence uint64 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Code     uint32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Error    string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *MetricsV1ServiceStreamMetricsResponse) Reset() {
+	*x = MetricsV1ServiceStreamMetricsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricsV1ServiceStreamMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsV1ServiceStreamMetricsResponse) ProtoMessage() {}
+
+func (x *MetricsV1ServiceStreamMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsV1ServiceStreamMetricsResponse.ProtoReflect.Descriptor instead.
+func (*MetricsV1ServiceStreamMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_metrics_metricsapi_v1_metrics_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MetricsV1ServiceStreamMetricsResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *MetricsV1ServiceStreamMetricsResponse) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *MetricsV1ServiceStreamMetricsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type MetricsV1ServiceWatchMetricsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Names  []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	Prefix string   `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+}
+
+func (x *MetricsV1ServiceWatchMetricsRequest) Reset() {
+	*x = MetricsV1ServiceWatchMetricsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricsV1ServiceWatchMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsV1ServiceWatchMetricsRequest) ProtoMessage() {}
+
+func (x *MetricsV1ServiceWatchMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsV1ServiceWatchMetricsRequest.ProtoReflect.Descriptor instead.
+func (*MetricsV1ServiceWatchMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_metrics_metricsapi_v1_metrics_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MetricsV1ServiceWatchMetricsRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *MetricsV1ServiceWatchMetricsRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type MetricsV1ServiceWatchMetricsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metrics []*MetricData `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+}
+
+func (x *MetricsV1ServiceWatchMetricsResponse) Reset() {
+	*x = MetricsV1ServiceWatchMetricsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricsV1ServiceWatchMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsV1ServiceWatchMetricsResponse) ProtoMessage() {}
+
+func (x *MetricsV1ServiceWatchMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsV1ServiceWatchMetricsResponse.ProtoReflect.Descriptor instead.
+func (*MetricsV1ServiceWatchMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_metrics_metricsapi_v1_metrics_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *MetricsV1ServiceWatchMetricsResponse) GetMetrics() []*MetricData {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type MetricsV1ServiceExportMetricsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *MetricsV1ServiceExportMetricsRequest) Reset() {
+	*x = MetricsV1ServiceExportMetricsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricsV1ServiceExportMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsV1ServiceExportMetricsRequest) ProtoMessage() {}
+
+func (x *MetricsV1ServiceExportMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsV1ServiceExportMetricsRequest.ProtoReflect.Descriptor instead.
+func (*MetricsV1ServiceExportMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_metrics_metricsapi_v1_metrics_proto_rawDescGZIP(), []int{16}
+}
+
+type MetricsV1ServiceExportMetricsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metrics []*MetricData `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+}
+
+func (x *MetricsV1ServiceExportMetricsResponse) Reset() {
+	*x = MetricsV1ServiceExportMetricsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricsV1ServiceExportMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsV1ServiceExportMetricsResponse) ProtoMessage() {}
+
+func (x *MetricsV1ServiceExportMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_metricsapi_v1_metrics_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsV1ServiceExportMetricsResponse.ProtoReflect.Descriptor instead.
+func (*MetricsV1ServiceExportMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_metrics_metricsapi_v1_metrics_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MetricsV1ServiceExportMetricsResponse) GetMetrics() []*MetricData {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
 var File_metrics_metricsapi_v1_metrics_proto protoreflect.FileDescriptor
 
 var file_metrics_metricsapi_v1_metrics_proto_rawDesc = []byte{
@@ -675,9 +988,47 @@ var file_metrics_metricsapi_v1_metrics_proto_rawDesc = []byte{
 	0x1b, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
 	0x65, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x1e, 0x0a, 0x1c,
 	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x17, 0x5a, 0x15,
-	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61,
-	0x70, 0x69, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa4, 0x01, 0x0a,
+	0x24, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x12, 0x4c, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x44, 0x61, 0x74, 0x61, 0x42, 0x0f, 0xfa, 0x42, 0x0c, 0x92, 0x01, 0x09, 0x08, 0x01, 0x22,
+	0x05, 0x8a, 0x01, 0x02, 0x10, 0x01, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x69, 0x67, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73,
+	0x69, 0x67, 0x6e, 0x22, 0x6d, 0x0a, 0x25, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08,
+	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x22, 0x53, 0x0a, 0x23, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0x63, 0x0a, 0x24, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3b, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x21, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x44,
+	0x61, 0x74, 0x61, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x22, 0x26, 0x0a, 0x24,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x64, 0x0a, 0x25, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56,
+	0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a,
+	0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21,
+	0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x61, 0x70, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x44, 0x61, 0x74,
+	0x61, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x42, 0x17, 0x5a, 0x15, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69,
+	0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -692,7 +1043,7 @@ func file_metrics_metricsapi_v1_metrics_proto_rawDescGZIP() []byte {
 	return file_metrics_metricsapi_v1_metrics_proto_rawDescData
 }
 
-var file_metrics_metricsapi_v1_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_metrics_metricsapi_v1_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_metrics_metricsapi_v1_metrics_proto_goTypes = []any{
 	(*MetricData)(nil),                                 // 0: metrics.metricsapi.v1.MetricData
 	(*MetricInfo)(nil),                                 // 1: metrics.metricsapi.v1.MetricInfo
@@ -706,6 +1057,12 @@ var file_metrics_metricsapi_v1_metrics_proto_goTypes = []any{
 	(*MetricsV1ServiceGetMetricsResponse)(nil),         // 9: metrics.metricsapi.v1.MetricsV1ServiceGetMetricsResponse
 	(*MetricsV1ServicePingRequest)(nil),                // 10: metrics.metricsapi.v1.MetricsV1ServicePingRequest
 	(*MetricsV1ServicePingResponse)(nil),               // 11: metrics.metricsapi.v1.MetricsV1ServicePingResponse
+	(*MetricsV1ServiceStreamMetricsRequest)(nil),       // 12: metrics.metricsapi.v1.MetricsV1ServiceStreamMetricsRequest
+	(*MetricsV1ServiceStreamMetricsResponse)(nil),      // 13: metrics.metricsapi.v1.MetricsV1ServiceStreamMetricsResponse
+	(*MetricsV1ServiceWatchMetricsRequest)(nil),        // 14: metrics.metricsapi.v1.MetricsV1ServiceWatchMetricsRequest
+	(*MetricsV1ServiceWatchMetricsResponse)(nil),       // 15: metrics.metricsapi.v1.MetricsV1ServiceWatchMetricsResponse
+	(*MetricsV1ServiceExportMetricsRequest)(nil),       // 16: metrics.metricsapi.v1.MetricsV1ServiceExportMetricsRequest
+	(*MetricsV1ServiceExportMetricsResponse)(nil),      // 17: metrics.metricsapi.v1.MetricsV1ServiceExportMetricsResponse
 }
 var file_metrics_metricsapi_v1_metrics_proto_depIdxs = []int32{
 	0, // 0: metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricRequest.metric:type_name -> metrics.metricsapi.v1.MetricData
@@ -713,11 +1070,14 @@ var file_metrics_metricsapi_v1_metrics_proto_depIdxs = []int32{
 	0, // 2: metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricsBatchRequest.metrics:type_name -> metrics.metricsapi.v1.MetricData
 	1, // 3: metrics.metricsapi.v1.MetricsV1ServiceGetMetricRequest.metric:type_name -> metrics.metricsapi.v1.MetricInfo
 	0, // 4: metrics.metricsapi.v1.MetricsV1ServiceGetMetricResponse.metric:type_name -> metrics.metricsapi.v1.MetricData
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	0, // 5: metrics.metricsapi.v1.MetricsV1ServiceStreamMetricsRequest.metrics:type_name -> metrics.metricsapi.v1.MetricData
+	0, // 6: metrics.metricsapi.v1.MetricsV1ServiceWatchMetricsResponse.metrics:type_name -> metrics.metricsapi.v1.MetricData
+	0, // 7: metrics.metricsapi.v1.MetricsV1ServiceExportMetricsResponse.metrics:type_name -> metrics.metricsapi.v1.MetricData
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_metrics_metricsapi_v1_metrics_proto_init() }
@@ -870,6 +1230,78 @@ func file_metrics_metricsapi_v1_metrics_proto_init() {
 				return nil
 			}
 		}
+		file_metrics_metricsapi_v1_metrics_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*MetricsV1ServiceStreamMetricsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metrics_metricsapi_v1_metrics_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*MetricsV1ServiceStreamMetricsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metrics_metricsapi_v1_metrics_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*MetricsV1ServiceWatchMetricsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metrics_metricsapi_v1_metrics_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*MetricsV1ServiceWatchMetricsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metrics_metricsapi_v1_metrics_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*MetricsV1ServiceExportMetricsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_metrics_metricsapi_v1_metrics_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*MetricsV1ServiceExportMetricsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_metrics_metricsapi_v1_metrics_proto_msgTypes[0].OneofWrappers = []any{
 		(*MetricData_Delta)(nil),
@@ -881,7 +1313,7 @@ func file_metrics_metricsapi_v1_metrics_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_metrics_metricsapi_v1_metrics_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1522,3 +1522,771 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = MetricsV1ServicePingResponseValidationError{}
+
+// Validate checks the field values on MetricsV1ServiceStreamMetricsRequest
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the first error encountered is returned, or nil if there
+// are no violations.
+func (m *MetricsV1ServiceStreamMetricsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MetricsV1ServiceStreamMetricsRequest
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the result is a list of violation errors wrapped in
+// MetricsV1ServiceStreamMetricsRequestMultiError, or nil if none found.
+func (m *MetricsV1ServiceStreamMetricsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MetricsV1ServiceStreamMetricsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Sequence
+
+	if len(m.GetMetrics()) < 1 {
+		err := MetricsV1ServiceStreamMetricsRequestValidationError{
+			field:  "Metrics",
+			reason: "value must contain at least 1 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetMetrics() {
+		_, _ = idx, item
+
+		if item == nil {
+			err := MetricsV1ServiceStreamMetricsRequestValidationError{
+				field:  fmt.Sprintf("Metrics[%v]", idx),
+				reason: "value is required",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, MetricsV1ServiceStreamMetricsRequestValidationError{
+						field:  fmt.Sprintf("Metrics[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, MetricsV1ServiceStreamMetricsRequestValidationError{
+						field:  fmt.Sprintf("Metrics[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return MetricsV1ServiceStreamMetricsRequestValidationError{
+					field:  fmt.Sprintf("Metrics[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Sign
+
+	if len(errors) > 0 {
+		return MetricsV1ServiceStreamMetricsRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// MetricsV1ServiceStreamMetricsRequestMultiError is an error wrapping multiple
+// validation errors returned by
+// MetricsV1ServiceStreamMetricsRequest.ValidateAll() if the designated
+// constraints aren't met.
+type MetricsV1ServiceStreamMetricsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MetricsV1ServiceStreamMetricsRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MetricsV1ServiceStreamMetricsRequestMultiError) AllErrors() []error { return m }
+
+// MetricsV1ServiceStreamMetricsRequestValidationError is the validation error
+// returned by MetricsV1ServiceStreamMetricsRequest.Validate if the designated
+// constraints aren't met.
+type MetricsV1ServiceStreamMetricsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MetricsV1ServiceStreamMetricsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MetricsV1ServiceStreamMetricsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MetricsV1ServiceStreamMetricsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MetricsV1ServiceStreamMetricsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MetricsV1ServiceStreamMetricsRequestValidationError) ErrorName() string {
+	return "MetricsV1ServiceStreamMetricsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e MetricsV1ServiceStreamMetricsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMetricsV1ServiceStreamMetricsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MetricsV1ServiceStreamMetricsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MetricsV1ServiceStreamMetricsRequestValidationError{}
+
+// Validate checks the field values on MetricsV1ServiceStreamMetricsResponse
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the first error encountered is returned, or nil if there
+// are no violations.
+func (m *MetricsV1ServiceStreamMetricsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MetricsV1ServiceStreamMetricsResponse
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the result is a list of violation errors wrapped in
+// MetricsV1ServiceStreamMetricsResponseMultiError, or nil if none found.
+func (m *MetricsV1ServiceStreamMetricsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MetricsV1ServiceStreamMetricsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Sequence
+
+	// no validation rules for Code
+
+	// no validation rules for Error
+
+	if len(errors) > 0 {
+		return MetricsV1ServiceStreamMetricsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// MetricsV1ServiceStreamMetricsResponseMultiError is an error wrapping
+// multiple validation errors returned by
+// MetricsV1ServiceStreamMetricsResponse.ValidateAll() if the designated
+// constraints aren't met.
+type MetricsV1ServiceStreamMetricsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MetricsV1ServiceStreamMetricsResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MetricsV1ServiceStreamMetricsResponseMultiError) AllErrors() []error { return m }
+
+// MetricsV1ServiceStreamMetricsResponseValidationError is the validation error
+// returned by MetricsV1ServiceStreamMetricsResponse.Validate if the designated
+// constraints aren't met.
+type MetricsV1ServiceStreamMetricsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MetricsV1ServiceStreamMetricsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MetricsV1ServiceStreamMetricsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MetricsV1ServiceStreamMetricsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MetricsV1ServiceStreamMetricsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MetricsV1ServiceStreamMetricsResponseValidationError) ErrorName() string {
+	return "MetricsV1ServiceStreamMetricsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e MetricsV1ServiceStreamMetricsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMetricsV1ServiceStreamMetricsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MetricsV1ServiceStreamMetricsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MetricsV1ServiceStreamMetricsResponseValidationError{}
+
+// Validate checks the field values on MetricsV1ServiceWatchMetricsRequest with
+// the rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *MetricsV1ServiceWatchMetricsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MetricsV1ServiceWatchMetricsRequest
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the result is a list of violation errors wrapped in
+// MetricsV1ServiceWatchMetricsRequestMultiError, or nil if none found.
+func (m *MetricsV1ServiceWatchMetricsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MetricsV1ServiceWatchMetricsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Names
+
+	// no validation rules for Prefix
+
+	if len(errors) > 0 {
+		return MetricsV1ServiceWatchMetricsRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// MetricsV1ServiceWatchMetricsRequestMultiError is an error wrapping multiple
+// validation errors returned by
+// MetricsV1ServiceWatchMetricsRequest.ValidateAll() if the designated
+// constraints aren't met.
+type MetricsV1ServiceWatchMetricsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MetricsV1ServiceWatchMetricsRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MetricsV1ServiceWatchMetricsRequestMultiError) AllErrors() []error { return m }
+
+// MetricsV1ServiceWatchMetricsRequestValidationError is the validation error
+// returned by MetricsV1ServiceWatchMetricsRequest.Validate if the designated
+// constraints aren't met.
+type MetricsV1ServiceWatchMetricsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MetricsV1ServiceWatchMetricsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MetricsV1ServiceWatchMetricsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MetricsV1ServiceWatchMetricsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MetricsV1ServiceWatchMetricsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MetricsV1ServiceWatchMetricsRequestValidationError) ErrorName() string {
+	return "MetricsV1ServiceWatchMetricsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e MetricsV1ServiceWatchMetricsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMetricsV1ServiceWatchMetricsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MetricsV1ServiceWatchMetricsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MetricsV1ServiceWatchMetricsRequestValidationError{}
+
+// Validate checks the field values on MetricsV1ServiceWatchMetricsResponse
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the first error encountered is returned, or nil if there
+// are no violations.
+func (m *MetricsV1ServiceWatchMetricsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MetricsV1ServiceWatchMetricsResponse
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the result is a list of violation errors wrapped in
+// MetricsV1ServiceWatchMetricsResponseMultiError, or nil if none found.
+func (m *MetricsV1ServiceWatchMetricsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MetricsV1ServiceWatchMetricsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetMetrics() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, MetricsV1ServiceWatchMetricsResponseValidationError{
+						field:  fmt.Sprintf("Metrics[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, MetricsV1ServiceWatchMetricsResponseValidationError{
+						field:  fmt.Sprintf("Metrics[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return MetricsV1ServiceWatchMetricsResponseValidationError{
+					field:  fmt.Sprintf("Metrics[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return MetricsV1ServiceWatchMetricsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// MetricsV1ServiceWatchMetricsResponseMultiError is an error wrapping multiple
+// validation errors returned by
+// MetricsV1ServiceWatchMetricsResponse.ValidateAll() if the designated
+// constraints aren't met.
+type MetricsV1ServiceWatchMetricsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MetricsV1ServiceWatchMetricsResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MetricsV1ServiceWatchMetricsResponseMultiError) AllErrors() []error { return m }
+
+// MetricsV1ServiceWatchMetricsResponseValidationError is the validation error
+// returned by MetricsV1ServiceWatchMetricsResponse.Validate if the designated
+// constraints aren't met.
+type MetricsV1ServiceWatchMetricsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MetricsV1ServiceWatchMetricsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MetricsV1ServiceWatchMetricsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MetricsV1ServiceWatchMetricsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MetricsV1ServiceWatchMetricsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MetricsV1ServiceWatchMetricsResponseValidationError) ErrorName() string {
+	return "MetricsV1ServiceWatchMetricsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e MetricsV1ServiceWatchMetricsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMetricsV1ServiceWatchMetricsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MetricsV1ServiceWatchMetricsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MetricsV1ServiceWatchMetricsResponseValidationError{}
+
+// Validate checks the field values on MetricsV1ServiceExportMetricsRequest
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the first error encountered is returned, or nil if there
+// are no violations.
+func (m *MetricsV1ServiceExportMetricsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MetricsV1ServiceExportMetricsRequest
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the result is a list of violation errors wrapped in
+// MetricsV1ServiceExportMetricsRequestMultiError, or nil if none found.
+func (m *MetricsV1ServiceExportMetricsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MetricsV1ServiceExportMetricsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return MetricsV1ServiceExportMetricsRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// MetricsV1ServiceExportMetricsRequestMultiError is an error wrapping multiple
+// validation errors returned by
+// MetricsV1ServiceExportMetricsRequest.ValidateAll() if the designated
+// constraints aren't met.
+type MetricsV1ServiceExportMetricsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MetricsV1ServiceExportMetricsRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MetricsV1ServiceExportMetricsRequestMultiError) AllErrors() []error { return m }
+
+// MetricsV1ServiceExportMetricsRequestValidationError is the validation error
+// returned by MetricsV1ServiceExportMetricsRequest.Validate if the designated
+// constraints aren't met.
+type MetricsV1ServiceExportMetricsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MetricsV1ServiceExportMetricsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MetricsV1ServiceExportMetricsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MetricsV1ServiceExportMetricsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MetricsV1ServiceExportMetricsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MetricsV1ServiceExportMetricsRequestValidationError) ErrorName() string {
+	return "MetricsV1ServiceExportMetricsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e MetricsV1ServiceExportMetricsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMetricsV1ServiceExportMetricsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MetricsV1ServiceExportMetricsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MetricsV1ServiceExportMetricsRequestValidationError{}
+
+// Validate checks the field values on MetricsV1ServiceExportMetricsResponse
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the first error encountered is returned, or nil if there
+// are no violations.
+func (m *MetricsV1ServiceExportMetricsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MetricsV1ServiceExportMetricsResponse
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the result is a list of violation errors wrapped in
+// MetricsV1ServiceExportMetricsResponseMultiError, or nil if none found.
+func (m *MetricsV1ServiceExportMetricsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MetricsV1ServiceExportMetricsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetMetrics() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, MetricsV1ServiceExportMetricsResponseValidationError{
+						field:  fmt.Sprintf("Metrics[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, MetricsV1ServiceExportMetricsResponseValidationError{
+						field:  fmt.Sprintf("Metrics[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return MetricsV1ServiceExportMetricsResponseValidationError{
+					field:  fmt.Sprintf("Metrics[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return MetricsV1ServiceExportMetricsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// MetricsV1ServiceExportMetricsResponseMultiError is an error wrapping
+// multiple validation errors returned by
+// MetricsV1ServiceExportMetricsResponse.ValidateAll() if the designated
+// constraints aren't met.
+type MetricsV1ServiceExportMetricsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MetricsV1ServiceExportMetricsResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MetricsV1ServiceExportMetricsResponseMultiError) AllErrors() []error { return m }
+
+// MetricsV1ServiceExportMetricsResponseValidationError is the validation error
+// returned by MetricsV1ServiceExportMetricsResponse.Validate if the designated
+// constraints aren't met.
+type MetricsV1ServiceExportMetricsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MetricsV1ServiceExportMetricsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MetricsV1ServiceExportMetricsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MetricsV1ServiceExportMetricsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MetricsV1ServiceExportMetricsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MetricsV1ServiceExportMetricsResponseValidationError) ErrorName() string {
+	return "MetricsV1ServiceExportMetricsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e MetricsV1ServiceExportMetricsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMetricsV1ServiceExportMetricsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MetricsV1ServiceExportMetricsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MetricsV1ServiceExportMetricsResponseValidationError{}
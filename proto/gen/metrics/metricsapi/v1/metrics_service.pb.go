@@ -28,7 +28,7 @@ var file_metrics_metricsapi_v1_metrics_service_proto_rawDesc = []byte{
 	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70,
 	0x69, 0x2e, 0x76, 0x31, 0x1a, 0x23, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2f, 0x6d, 0x65,
 	0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x65, 0x74, 0x72,
-	0x69, 0x63, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xad, 0x05, 0x0a, 0x10, 0x4d, 0x65,
+	0x69, 0x63, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xd9, 0x08, 0x0a, 0x10, 0x4d, 0x65,
 	0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x87,
 	0x01, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12,
 	0x3a, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
@@ -71,9 +71,36 @@ var file_metrics_metricsapi_v1_metrics_service_proto_rawDesc = []byte{
 	0x75, 0x65, 0x73, 0x74, 0x1a, 0x33, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x6d,
 	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74,
 	0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x50, 0x69, 0x6e,
-	0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x17, 0x5a, 0x15, 0x6d, 0x65, 0x74,
-	0x72, 0x69, 0x63, 0x73, 0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69, 0x2f,
-	0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x8e, 0x01, 0x0a, 0x0d, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x3b, 0x2e, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69,
+	0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3c, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x31,
+	0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x89, 0x01, 0x0a, 0x0c, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x3a, 0x2e, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69,
+	0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3b, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x73, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x31, 0x2e,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x8c, 0x01, 0x0a, 0x0d, 0x45, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x3b, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x31,
+	0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3c, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x17, 0x5a, 0x15, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_metrics_metricsapi_v1_metrics_service_proto_goTypes = []any{
@@ -82,28 +109,40 @@ var file_metrics_metricsapi_v1_metrics_service_proto_goTypes = []any{
 	(*MetricsV1ServiceGetMetricRequest)(nil),           // 2: metrics.metricsapi.v1.MetricsV1ServiceGetMetricRequest
 	(*MetricsV1ServiceGetMetricsRequest)(nil),          // 3: metrics.metricsapi.v1.MetricsV1ServiceGetMetricsRequest
 	(*MetricsV1ServicePingRequest)(nil),                // 4: metrics.metricsapi.v1.MetricsV1ServicePingRequest
-	(*MetricsV1ServiceUpdateMetricResponse)(nil),       // 5: metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricResponse
-	(*MetricsV1ServiceUpdateMetricsBatchResponse)(nil), // 6: metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricsBatchResponse
-	(*MetricsV1ServiceGetMetricResponse)(nil),          // 7: metrics.metricsapi.v1.MetricsV1ServiceGetMetricResponse
-	(*MetricsV1ServiceGetMetricsResponse)(nil),         // 8: metrics.metricsapi.v1.MetricsV1ServiceGetMetricsResponse
-	(*MetricsV1ServicePingResponse)(nil),               // 9: metrics.metricsapi.v1.MetricsV1ServicePingResponse
+	(*MetricsV1ServiceStreamMetricsRequest)(nil),       // 5: metrics.metricsapi.v1.MetricsV1ServiceStreamMetricsRequest
+	(*MetricsV1ServiceWatchMetricsRequest)(nil),        // 6: metrics.metricsapi.v1.MetricsV1ServiceWatchMetricsRequest
+	(*MetricsV1ServiceExportMetricsRequest)(nil),       // 7: metrics.metricsapi.v1.MetricsV1ServiceExportMetricsRequest
+	(*MetricsV1ServiceUpdateMetricResponse)(nil),       // 8: metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricResponse
+	(*MetricsV1ServiceUpdateMetricsBatchResponse)(nil), // 9: metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricsBatchResponse
+	(*MetricsV1ServiceGetMetricResponse)(nil),          // 10: metrics.metricsapi.v1.MetricsV1ServiceGetMetricResponse
+	(*MetricsV1ServiceGetMetricsResponse)(nil),         // 11: metrics.metricsapi.v1.MetricsV1ServiceGetMetricsResponse
+	(*MetricsV1ServicePingResponse)(nil),               // 12: metrics.metricsapi.v1.MetricsV1ServicePingResponse
+	(*MetricsV1ServiceStreamMetricsResponse)(nil),      // 13: metrics.metricsapi.v1.MetricsV1ServiceStreamMetricsResponse
+	(*MetricsV1ServiceWatchMetricsResponse)(nil),       // 14: metrics.metricsapi.v1.MetricsV1ServiceWatchMetricsResponse
+	(*MetricsV1ServiceExportMetricsResponse)(nil),      // 15: metrics.metricsapi.v1.MetricsV1ServiceExportMetricsResponse
 }
 var file_metrics_metricsapi_v1_metrics_service_proto_depIdxs = []int32{
-	0, // 0: metrics.metricsapi.v1.MetricsV1Service.UpdateMetric:input_type -> metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricRequest
-	1, // 1: metrics.metricsapi.v1.MetricsV1Service.UpdateMetricsBatch:input_type -> metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricsBatchRequest
-	2, // 2: metrics.metricsapi.v1.MetricsV1Service.GetMetric:input_type -> metrics.metricsapi.v1.MetricsV1ServiceGetMetricRequest
-	3, // 3: metrics.metricsapi.v1.MetricsV1Service.GetMetrics:input_type -> metrics.metricsapi.v1.MetricsV1ServiceGetMetricsRequest
-	4, // 4: metrics.metricsapi.v1.MetricsV1Service.Ping:input_type -> metrics.metricsapi.v1.MetricsV1ServicePingRequest
-	5, // 5: metrics.metricsapi.v1.MetricsV1Service.UpdateMetric:output_type -> metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricResponse
-	6, // 6: metrics.metricsapi.v1.MetricsV1Service.UpdateMetricsBatch:output_type -> metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricsBatchResponse
-	7, // 7: metrics.metricsapi.v1.MetricsV1Service.GetMetric:output_type -> metrics.metricsapi.v1.MetricsV1ServiceGetMetricResponse
-	8, // 8: metrics.metricsapi.v1.MetricsV1Service.GetMetrics:output_type -> metrics.metricsapi.v1.MetricsV1ServiceGetMetricsResponse
-	9, // 9: metrics.metricsapi.v1.MetricsV1Service.Ping:output_type -> metrics.metricsapi.v1.MetricsV1ServicePingResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: metrics.metricsapi.v1.MetricsV1Service.UpdateMetric:input_type -> metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricRequest
+	1,  // 1: metrics.metricsapi.v1.MetricsV1Service.UpdateMetricsBatch:input_type -> metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricsBatchRequest
+	2,  // 2: metrics.metricsapi.v1.MetricsV1Service.GetMetric:input_type -> metrics.metricsapi.v1.MetricsV1ServiceGetMetricRequest
+	3,  // 3: metrics.metricsapi.v1.MetricsV1Service.GetMetrics:input_type -> metrics.metricsapi.v1.MetricsV1ServiceGetMetricsRequest
+	4,  // 4: metrics.metricsapi.v1.MetricsV1Service.Ping:input_type -> metrics.metricsapi.v1.MetricsV1ServicePingRequest
+	5,  // 5: metrics.metricsapi.v1.MetricsV1Service.StreamMetrics:input_type -> metrics.metricsapi.v1.MetricsV1ServiceStreamMetricsRequest
+	6,  // 6: metrics.metricsapi.v1.MetricsV1Service.WatchMetrics:input_type -> metrics.metricsapi.v1.MetricsV1ServiceWatchMetricsRequest
+	7,  // 7: metrics.metricsapi.v1.MetricsV1Service.ExportMetrics:input_type -> metrics.metricsapi.v1.MetricsV1ServiceExportMetricsRequest
+	8,  // 8: metrics.metricsapi.v1.MetricsV1Service.UpdateMetric:output_type -> metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricResponse
+	9,  // 9: metrics.metricsapi.v1.MetricsV1Service.UpdateMetricsBatch:output_type -> metrics.metricsapi.v1.MetricsV1ServiceUpdateMetricsBatchResponse
+	10, // 10: metrics.metricsapi.v1.MetricsV1Service.GetMetric:output_type -> metrics.metricsapi.v1.MetricsV1ServiceGetMetricResponse
+	11, // 11: metrics.metricsapi.v1.MetricsV1Service.GetMetrics:output_type -> metrics.metricsapi.v1.MetricsV1ServiceGetMetricsResponse
+	12, // 12: metrics.metricsapi.v1.MetricsV1Service.Ping:output_type -> metrics.metricsapi.v1.MetricsV1ServicePingResponse
+	13, // 13: metrics.metricsapi.v1.MetricsV1Service.StreamMetrics:output_type -> metrics.metricsapi.v1.MetricsV1ServiceStreamMetricsResponse
+	14, // 14: metrics.metricsapi.v1.MetricsV1Service.WatchMetrics:output_type -> metrics.metricsapi.v1.MetricsV1ServiceWatchMetricsResponse
+	15, // 15: metrics.metricsapi.v1.MetricsV1Service.ExportMetrics:output_type -> metrics.metricsapi.v1.MetricsV1ServiceExportMetricsResponse
+	8,  // [8:16] is the sub-list for method output_type
+	0,  // [0:8] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_metrics_metricsapi_v1_metrics_service_proto_init() }
@@ -24,6 +24,9 @@ const (
 	MetricsV1Service_GetMetric_FullMethodName          = "/metrics.metricsapi.v1.MetricsV1Service/GetMetric"
 	MetricsV1Service_GetMetrics_FullMethodName         = "/metrics.metricsapi.v1.MetricsV1Service/GetMetrics"
 	MetricsV1Service_Ping_FullMethodName               = "/metrics.metricsapi.v1.MetricsV1Service/Ping"
+	MetricsV1Service_StreamMetrics_FullMethodName      = "/metrics.metricsapi.v1.MetricsV1Service/StreamMetrics"
+	MetricsV1Service_WatchMetrics_FullMethodName       = "/metrics.metricsapi.v1.MetricsV1Service/WatchMetrics"
+	MetricsV1Service_ExportMetrics_FullMethodName      = "/metrics.metricsapi.v1.MetricsV1Service/ExportMetrics"
 )
 
 // MetricsV1ServiceClient is the client API for MetricsV1Service service.
@@ -35,6 +38,9 @@ type MetricsV1ServiceClient interface {
 	GetMetric(ctx context.Context, in *MetricsV1ServiceGetMetricRequest, opts ...grpc.CallOption) (*MetricsV1ServiceGetMetricResponse, error)
 	GetMetrics(ctx context.Context, in *MetricsV1ServiceGetMetricsRequest, opts ...grpc.CallOption) (*MetricsV1ServiceGetMetricsResponse, error)
 	Ping(ctx context.Context, in *MetricsV1ServicePingRequest, opts ...grpc.CallOption) (*MetricsV1ServicePingResponse, error)
+	StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (MetricsV1Service_StreamMetricsClient, error)
+	WatchMetrics(ctx context.Context, in *MetricsV1ServiceWatchMetricsRequest, opts ...grpc.CallOption) (MetricsV1Service_WatchMetricsClient, error)
+	ExportMetrics(ctx context.Context, in *MetricsV1ServiceExportMetricsRequest, opts ...grpc.CallOption) (MetricsV1Service_ExportMetricsClient, error)
 }
 
 type metricsV1ServiceClient struct {
@@ -95,6 +101,104 @@ func (c *metricsV1ServiceClient) Ping(ctx context.Context, in *MetricsV1ServiceP
 	return out, nil
 }
 
+func (c *metricsV1ServiceClient) StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (MetricsV1Service_StreamMetricsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MetricsV1Service_ServiceDesc.Streams[0], MetricsV1Service_StreamMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &metricsV1ServiceStreamMetricsClient{stream}
+	return x, nil
+}
+
+type MetricsV1Service_StreamMetricsClient interface {
+	Send(*MetricsV1ServiceStreamMetricsRequest) error
+	Recv() (*MetricsV1ServiceStreamMetricsResponse, error)
+	grpc.ClientStream
+}
+
+type metricsV1ServiceStreamMetricsClient struct {
+	grpc.ClientStream
+}
+
+func (x *metricsV1ServiceStreamMetricsClient) Send(m *MetricsV1ServiceStreamMetricsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *metricsV1ServiceStreamMetricsClient) Recv() (*MetricsV1ServiceStreamMetricsResponse, error) {
+	m := new(MetricsV1ServiceStreamMetricsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *metricsV1ServiceClient) WatchMetrics(ctx context.Context, in *MetricsV1ServiceWatchMetricsRequest, opts ...grpc.CallOption) (MetricsV1Service_WatchMetricsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MetricsV1Service_ServiceDesc.Streams[1], MetricsV1Service_WatchMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &metricsV1ServiceWatchMetricsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MetricsV1Service_WatchMetricsClient interface {
+	Recv() (*MetricsV1ServiceWatchMetricsResponse, error)
+	grpc.ClientStream
+}
+
+type metricsV1ServiceWatchMetricsClient struct {
+	grpc.ClientStream
+}
+
+func (x *metricsV1ServiceWatchMetricsClient) Recv() (*MetricsV1ServiceWatchMetricsResponse, error) {
+	m := new(MetricsV1ServiceWatchMetricsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *metricsV1ServiceClient) ExportMetrics(ctx context.Context, in *MetricsV1ServiceExportMetricsRequest, opts ...grpc.CallOption) (MetricsV1Service_ExportMetricsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MetricsV1Service_ServiceDesc.Streams[2], MetricsV1Service_ExportMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &metricsV1ServiceExportMetricsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MetricsV1Service_ExportMetricsClient interface {
+	Recv() (*MetricsV1ServiceExportMetricsResponse, error)
+	grpc.ClientStream
+}
+
+type metricsV1ServiceExportMetricsClient struct {
+	grpc.ClientStream
+}
+
+func (x *metricsV1ServiceExportMetricsClient) Recv() (*MetricsV1ServiceExportMetricsResponse, error) {
+	m := new(MetricsV1ServiceExportMetricsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // MetricsV1ServiceServer is the server API for MetricsV1Service service.
 // All implementations must embed UnimplementedMetricsV1ServiceServer
 // for forward compatibility
@@ -104,6 +208,9 @@ type MetricsV1ServiceServer interface {
 	GetMetric(context.Context, *MetricsV1ServiceGetMetricRequest) (*MetricsV1ServiceGetMetricResponse, error)
 	GetMetrics(context.Context, *MetricsV1ServiceGetMetricsRequest) (*MetricsV1ServiceGetMetricsResponse, error)
 	Ping(context.Context, *MetricsV1ServicePingRequest) (*MetricsV1ServicePingResponse, error)
+	StreamMetrics(MetricsV1Service_StreamMetricsServer) error
+	WatchMetrics(*MetricsV1ServiceWatchMetricsRequest, MetricsV1Service_WatchMetricsServer) error
+	ExportMetrics(*MetricsV1ServiceExportMetricsRequest, MetricsV1Service_ExportMetricsServer) error
 	mustEmbedUnimplementedMetricsV1ServiceServer()
 }
 
@@ -126,6 +233,15 @@ func (UnimplementedMetricsV1ServiceServer) GetMetrics(context.Context, *MetricsV
 func (UnimplementedMetricsV1ServiceServer) Ping(context.Context, *MetricsV1ServicePingRequest) (*MetricsV1ServicePingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
+func (UnimplementedMetricsV1ServiceServer) StreamMetrics(MetricsV1Service_StreamMetricsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamMetrics not implemented")
+}
+func (UnimplementedMetricsV1ServiceServer) WatchMetrics(*MetricsV1ServiceWatchMetricsRequest, MetricsV1Service_WatchMetricsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchMetrics not implemented")
+}
+func (UnimplementedMetricsV1ServiceServer) ExportMetrics(*MetricsV1ServiceExportMetricsRequest, MetricsV1Service_ExportMetricsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportMetrics not implemented")
+}
 func (UnimplementedMetricsV1ServiceServer) mustEmbedUnimplementedMetricsV1ServiceServer() {}
 
 // UnsafeMetricsV1ServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -229,6 +345,74 @@ func _MetricsV1Service_Ping_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MetricsV1Service_StreamMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MetricsV1ServiceServer).StreamMetrics(&metricsV1ServiceStreamMetricsServer{stream})
+}
+
+type MetricsV1Service_StreamMetricsServer interface {
+	Send(*MetricsV1ServiceStreamMetricsResponse) error
+	Recv() (*MetricsV1ServiceStreamMetricsRequest, error)
+	grpc.ServerStream
+}
+
+type metricsV1ServiceStreamMetricsServer struct {
+	grpc.ServerStream
+}
+
+func (x *metricsV1ServiceStreamMetricsServer) Send(m *MetricsV1ServiceStreamMetricsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *metricsV1ServiceStreamMetricsServer) Recv() (*MetricsV1ServiceStreamMetricsRequest, error) {
+	m := new(MetricsV1ServiceStreamMetricsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _MetricsV1Service_WatchMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MetricsV1ServiceWatchMetricsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MetricsV1ServiceServer).WatchMetrics(m, &metricsV1ServiceWatchMetricsServer{stream})
+}
+
+type MetricsV1Service_WatchMetricsServer interface {
+	Send(*MetricsV1ServiceWatchMetricsResponse) error
+	grpc.ServerStream
+}
+
+type metricsV1ServiceWatchMetricsServer struct {
+	grpc.ServerStream
+}
+
+func (x *metricsV1ServiceWatchMetricsServer) Send(m *MetricsV1ServiceWatchMetricsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MetricsV1Service_ExportMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MetricsV1ServiceExportMetricsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MetricsV1ServiceServer).ExportMetrics(m, &metricsV1ServiceExportMetricsServer{stream})
+}
+
+type MetricsV1Service_ExportMetricsServer interface {
+	Send(*MetricsV1ServiceExportMetricsResponse) error
+	grpc.ServerStream
+}
+
+type metricsV1ServiceExportMetricsServer struct {
+	grpc.ServerStream
+}
+
+func (x *metricsV1ServiceExportMetricsServer) Send(m *MetricsV1ServiceExportMetricsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // MetricsV1Service_ServiceDesc is the grpc.ServiceDesc for MetricsV1Service service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -257,6 +441,23 @@ var MetricsV1Service_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _MetricsV1Service_Ping_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamMetrics",
+			Handler:       _MetricsV1Service_StreamMetrics_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchMetrics",
+			Handler:       _MetricsV1Service_WatchMetrics_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportMetrics",
+			Handler:       _MetricsV1Service_ExportMetrics_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "metrics/metricsapi/v1/metrics_service.proto",
 }